@@ -2,66 +2,161 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 	"time"
 
 	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/app/events"
+	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/app/repository"
 	"github.com/supchaser/LO_test_task/internal/app/usecase"
+	"github.com/supchaser/LO_test_task/internal/app/webhooks"
 	"github.com/supchaser/LO_test_task/internal/config"
+	"github.com/supchaser/LO_test_task/internal/middleware/idempotency"
 	"github.com/supchaser/LO_test_task/internal/middleware/logging"
-	recovery "github.com/supchaser/LO_test_task/internal/middleware/panic"
+	"github.com/supchaser/LO_test_task/internal/utils/health"
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+	"github.com/supchaser/LO_test_task/internal/utils/tlsconfig"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
 )
 
 func main() {
-	logger.Init()
-	defer logger.Close()
-
-	cfg, err := config.LoadConfig(".env")
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
 		logger.Fatal("failed to load config", err, nil)
 	}
 
+	logger.InitWithOptions(logger.Options{
+		BufferSize: cfg.Log.BufferSize,
+		Policy:     cfg.Log.OverflowPolicy,
+	})
+	defer logger.Close()
+
+	if err := configureLogger(cfg); err != nil {
+		logger.Fatal("failed to configure logger", err, nil)
+	}
+	slog.SetDefault(logger.Slog())
+
 	logger.Info("configuration loaded successfully", nil)
 
-	repo := repository.CreateTaskRepository()
-	uc := usecase.CreateTaskUsecase(repo)
-	delivery := delivery.CreateTaskDelivery(uc)
+	watcher := config.CreateWatcher(os.Args[1:], cfg)
+	watcher.Subscribe(func(previous, current *config.Config) {
+		if err := reloadLogSettings(previous, current); err != nil {
+			logger.Error("failed to apply log settings", err, nil)
+		}
+	})
 
-	handlerChain := func(h http.Handler) http.Handler {
-		return recovery.RecoveryMiddleware(logging.LoggingMiddleware(h))
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go reloadOnSignal(reloadCtx, watcher)
+	if cfg.Reload.PollInterval > 0 {
+		go watcher.Watch(reloadCtx, cfg.Reload.PollInterval)
 	}
 
-	mux := http.NewServeMux()
+	tracer := tracing.CreateTracer(createTracingExporter(cfg))
+	tracing.SetTracer(tracer)
+
+	repo := repository.CreateTaskRepository()
+	eventBus := events.CreateBus(cfg.Events.ReplaySize)
+	webhookRepo := repository.CreateWebhookRepository()
+	dispatcher := webhooks.CreateDispatcher(webhookRepo, webhooks.Config{
+		Workers:     cfg.Webhooks.Workers,
+		QueueSize:   webhooks.DefaultConfig().QueueSize,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Timeout:     cfg.Webhooks.Timeout,
+		BackoffBase: cfg.Webhooks.BackoffBase,
+		BackoffMax:  cfg.Webhooks.BackoffMax,
+	})
+	relay := events.CreateRelay(repo, cfg.Events.OutboxPollInterval)
+	relay.AddSink("event_bus", eventBus)
+	relay.AddSink("webhooks", dispatcher)
+	relay.Start()
+	registerMetrics(repo)
+	uc := usecase.CreateTaskUsecase(repo)
+	taskDelivery := delivery.CreateTaskDelivery(uc)
+	eventDelivery := delivery.CreateEventDelivery(eventBus, cfg.Events.WebSocketOrigins)
+	webhookDelivery := delivery.CreateWebhookDelivery(usecase.CreateWebhookUsecase(webhookRepo))
+	logLevelDelivery := delivery.CreateLogLevelDelivery()
+	idempotencyStore := idempotency.CreateStore(cfg.Idempotency.TTL)
+	accessLog := logging.CreateAccessLog(logging.Config{
+		Format:        cfg.AccessLog.Format,
+		SlowThreshold: cfg.AccessLog.SlowThreshold,
+		ExcludePaths:  cfg.AccessLog.ExcludePaths,
+	})
+
+	healthRegistry := health.CreateRegistry(cfg.Health.CheckTimeout)
+	healthRegistry.RegisterLiveness("logger", logger.Flush)
+	healthRegistry.RegisterReadiness("storage", repo.Ping)
+	healthRegistry.RegisterReadiness("webhooks", dispatcher.Check)
 
-	mux.Handle("POST /tasks", handlerChain(http.HandlerFunc(delivery.CreateTask)))
-	mux.Handle("GET /tasks/{id}", handlerChain(http.HandlerFunc(delivery.GetTask)))
-	mux.Handle("GET /tasks", handlerChain(http.HandlerFunc(delivery.ListTasks)))
-	mux.Handle("PUT /tasks/{id}", handlerChain(http.HandlerFunc(delivery.UpdateTask)))
-	mux.Handle("DELETE /tasks/{id}", handlerChain(http.HandlerFunc(delivery.DeleteTask)))
-	mux.Handle("GET /health", handlerChain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})))
+	handler := createHandler(handlers{
+		tasks:       taskDelivery,
+		events:      eventDelivery,
+		webhooks:    webhookDelivery,
+		logLevels:   logLevelDelivery,
+		idempotency: idempotencyStore,
+		accessLog:   accessLog,
+		health:      healthRegistry,
+		adminToken:  cfg.Admin.Token,
+	}, cfg.Limits.MaxBodyBytes)
 
-	port := ":" + cfg.ServerPort
+	port := ":" + cfg.Server.Port
 	server := &http.Server{
-		Addr:    port,
-		Handler: mux,
+		Addr:              port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Limits.MaxHeaderBytes,
+	}
+
+	scheme := "http"
+	if cfg.Server.TLS.Enabled() {
+		server.TLSConfig, err = tlsconfig.CreateServerConfig(tlsconfig.Options{
+			CertFile:      cfg.Server.TLS.CertFile,
+			KeyFile:       cfg.Server.TLS.KeyFile,
+			ClientCAFile:  cfg.Server.TLS.ClientCAFile,
+			ClientAuth:    cfg.Server.TLS.ClientAuth,
+			CheckInterval: cfg.Server.TLS.CertCheckInterval,
+		})
+		if err != nil {
+			logger.Fatal("failed to configure TLS", err, nil)
+		}
+		scheme = "https"
 	}
 
+	server.RegisterOnShutdown(eventDelivery.Shutdown)
+	server.RegisterOnShutdown(eventBus.Close)
+
 	serverErr := make(chan error, 1)
 
 	go func() {
 		logger.Info("starting HTTP server", map[string]any{
-			"address": "http://localhost" + port,
+			"address":     scheme + "://localhost" + port,
+			"client_auth": cfg.Server.TLS.ClientAuth,
 		})
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error("server error", err, nil)
+
+		var err error
+		if server.TLSConfig != nil {
+			// The certificate comes from TLSConfig.GetCertificate.
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
 		}
 	}()
 
@@ -71,20 +166,161 @@ func main() {
 	select {
 	case err := <-serverErr:
 		logger.Error("failed to start server", err, nil)
+		logger.Close()
 		os.Exit(1)
 	case sig := <-quit:
 		logger.Info("server is shutting down", map[string]any{
 			"signal": sig.String(),
 		})
+		stopReload()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// Let load balancers see /readyz fail before connections are refused.
+		drain(healthRegistry, cfg.Health.ShutdownDelay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
 
 		if err := server.Shutdown(ctx); err != nil {
 			logger.Error("server shutdown error", err, nil)
+			logger.Close()
 			os.Exit(1)
 		}
 
+		if err := relay.Close(ctx); err != nil {
+			logger.Error("event relay shutdown error", err, nil)
+		}
+
+		if err := dispatcher.Close(ctx); err != nil {
+			logger.Error("webhook dispatcher shutdown error", err, nil)
+		}
+
+		if err := tracer.Close(ctx); err != nil {
+			logger.Error("tracer shutdown error", err, nil)
+		}
+
 		logger.Info("server stopped", nil)
 	}
 }
+
+// reloadOnSignal reloads the configuration on every SIGHUP until ctx is done.
+func reloadOnSignal(ctx context.Context, watcher *config.Watcher) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := watcher.Reload(); err != nil {
+				logger.Error("failed to reload configuration", err, nil)
+			}
+		}
+	}
+}
+
+// drain makes /readyz fail and keeps serving for delay, so load balancers
+// stop routing new requests before the server refuses connections.
+func drain(registry *health.Registry, delay time.Duration) {
+	registry.SetShuttingDown()
+	time.Sleep(delay)
+}
+
+func configureLogger(cfg *config.Config) error {
+	if err := applyLogSettings(cfg); err != nil {
+		return err
+	}
+
+	formatter, err := logger.CreateFormatter(cfg.Log.Format)
+	if err != nil {
+		return err
+	}
+
+	var sink logger.Sink
+	switch cfg.Log.Sink {
+	case logger.SinkFile:
+		sink, err = logger.CreateFileSink(logger.FileSinkConfig{
+			Path:       cfg.Log.File.Path,
+			MaxSize:    int64(cfg.Log.File.MaxSizeMB) << 20,
+			MaxAge:     cfg.Log.File.MaxAge,
+			MaxBackups: cfg.Log.File.MaxBackups,
+			Compress:   cfg.Log.File.Compress,
+		})
+	case logger.SinkSyslog:
+		sink, err = logger.CreateSyslogSink(cfg.Log.Syslog.Address, cfg.Log.Syslog.Tag, logger.FacilityLocal0)
+	default:
+		sink = logger.CreateWriterSink(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	return logger.Configure(formatter, sink)
+}
+
+// applyLogSettings applies the log settings that can change at runtime.
+func applyLogSettings(cfg *config.Config) error {
+	if err := logger.SetLevels(cfg.LevelSettings()); err != nil {
+		return err
+	}
+
+	return applyRedaction(cfg)
+}
+
+// reloadLogSettings applies only the log settings that differ between the
+// previous and the current configuration, so a reload that touches the
+// redaction rules keeps levels changed through PUT /admin/log-level.
+func reloadLogSettings(previous, current *config.Config) error {
+	if !reflect.DeepEqual(previous.LevelSettings(), current.LevelSettings()) {
+		if err := logger.SetLevels(current.LevelSettings()); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(previous.Redaction(), current.Redaction()) {
+		return applyRedaction(current)
+	}
+
+	return nil
+}
+
+func applyRedaction(cfg *config.Config) error {
+	redactor, err := logger.CreateRedactor(cfg.Redaction())
+	if err != nil {
+		return err
+	}
+	logger.SetRedactor(redactor)
+
+	return nil
+}
+
+func createTracingExporter(cfg *config.Config) tracing.Exporter {
+	switch cfg.Tracing.Exporter {
+	case config.TracingExporterStdout:
+		return tracing.CreateStdoutExporter(os.Stdout, cfg.Tracing.ServiceName)
+	case config.TracingExporterOTLP:
+		return tracing.CreateOTLPExporter(cfg.Tracing.OTLPEndpoint, cfg.Tracing.ServiceName, 10*time.Second)
+	default:
+		return nil
+	}
+}
+
+func registerMetrics(repo *repository.TaskRepository) {
+	metrics.Default.NewGaugeVecFunc("tasks", "Number of stored tasks by status.", []string{"status"}, func(emit func(float64, ...string)) {
+		counts := repo.CountTasksByStatus()
+		for _, status := range []models.TaskStatus{models.StatusPending, models.StatusInProgress, models.StatusCompleted} {
+			emit(float64(counts[status]), string(status))
+		}
+	})
+
+	metrics.Default.NewGaugeFunc("logger_queue_length", "Number of log entries waiting to be written.", func() float64 {
+		return float64(logger.Stats().Queued)
+	})
+	metrics.Default.NewGaugeFunc("logger_queue_capacity", "Capacity of the log entry queue.", func() float64 {
+		return float64(logger.Stats().Capacity)
+	})
+	metrics.Default.NewCounterFunc("logger_dropped_entries_total", "Number of log entries dropped because the queue was full.", func() float64 {
+		return float64(logger.Stats().Dropped)
+	})
+}
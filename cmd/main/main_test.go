@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/config"
+	"github.com/supchaser/LO_test_task/internal/utils/health"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+func TestReloadLogSettings(t *testing.T) {
+	initial := logger.Levels()
+	t.Cleanup(func() {
+		logger.SetLevels(initial)
+		defaults := config.Default()
+		applyRedaction(&defaults)
+	})
+
+	previous := config.Default()
+	require.NoError(t, applyLogSettings(&previous))
+
+	// A level changed at runtime through PUT /admin/log-level.
+	runtime := logger.LevelSettings{Global: "DEBUG", Components: map[string]string{}}
+	require.NoError(t, logger.SetLevels(runtime))
+
+	redactOnly := previous
+	redactOnly.Log.Redact.MaxValueLength = 64
+	require.NoError(t, reloadLogSettings(&previous, &redactOnly))
+	assert.Equal(t, runtime, logger.Levels())
+
+	levelChanged := redactOnly
+	levelChanged.Log.Level = "WARN"
+	require.NoError(t, reloadLogSettings(&redactOnly, &levelChanged))
+	assert.Equal(t, "WARN", logger.Levels().Global)
+}
+
+func TestDrain_ReadyzFailsDuringDelay(t *testing.T) {
+	registry := health.CreateRegistry(time.Second)
+	h := newHandlers(t)
+	h.health = registry
+	server := httptest.NewServer(createHandler(h, 1<<20))
+	defer server.Close()
+
+	readyz := func() int {
+		resp, err := http.Get(server.URL + "/readyz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusOK, readyz())
+
+	drained := make(chan struct{})
+	go func() {
+		drain(registry, 200*time.Millisecond)
+		close(drained)
+	}()
+
+	require.Eventually(t, func() bool { return readyz() == http.StatusServiceUnavailable }, time.Second, 10*time.Millisecond)
+	select {
+	case <-drained:
+		t.Fatal("drain returned before the delay elapsed")
+	default:
+	}
+	<-drained
+}
+
+func TestDefaultShutdownDelay(t *testing.T) {
+	assert.Positive(t, config.Default().Health.ShutdownDelay)
+}
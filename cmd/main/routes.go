@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/middleware/adminauth"
+	"github.com/supchaser/LO_test_task/internal/middleware/httpmetrics"
+	"github.com/supchaser/LO_test_task/internal/middleware/httprequestid"
+	"github.com/supchaser/LO_test_task/internal/middleware/httptracing"
+	"github.com/supchaser/LO_test_task/internal/middleware/idempotency"
+	"github.com/supchaser/LO_test_task/internal/middleware/logging"
+	recovery "github.com/supchaser/LO_test_task/internal/middleware/panic"
+	"github.com/supchaser/LO_test_task/internal/utils/health"
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+)
+
+type handlers struct {
+	tasks       *delivery.TaskDelivery
+	events      *delivery.EventDelivery
+	webhooks    *delivery.WebhookDelivery
+	logLevels   *delivery.LogLevelDelivery
+	idempotency *idempotency.Store
+	accessLog   *logging.AccessLog
+	health      *health.Registry
+	adminToken  string
+}
+
+// createHandler registers the routes and wraps the mux in the server-wide
+// middlewares. The body limit is applied per route, inside the mux: the
+// metrics and tracing middlewares read the pattern the mux records on the
+// request, and http.MaxBytesHandler hands a copy of the request on.
+func createHandler(h handlers, maxBodyBytes int64) http.Handler {
+	handlerChain := func(next http.Handler) http.Handler {
+		return recovery.RecoveryMiddleware(http.MaxBytesHandler(next, maxBodyBytes))
+	}
+	adminChain := func(next http.Handler) http.Handler {
+		return handlerChain(adminauth.TokenMiddleware(h.adminToken, next))
+	}
+
+	mux := http.NewServeMux()
+
+	mux.Handle("POST /tasks", handlerChain(h.idempotency.Middleware(http.HandlerFunc(h.tasks.CreateTask))))
+	mux.Handle("POST /tasks:batchCreate", handlerChain(http.HandlerFunc(h.tasks.BatchCreateTasks)))
+	mux.Handle("POST /tasks:batchUpdate", handlerChain(http.HandlerFunc(h.tasks.BatchUpdateTasks)))
+	mux.Handle("POST /tasks:batchDelete", handlerChain(http.HandlerFunc(h.tasks.BatchDeleteTasks)))
+	mux.Handle("GET /tasks/export", handlerChain(http.HandlerFunc(h.tasks.ExportTasks)))
+	mux.Handle("POST /tasks/import", handlerChain(http.HandlerFunc(h.tasks.ImportTasks)))
+	mux.Handle("GET /tasks/events", handlerChain(http.HandlerFunc(h.events.StreamEvents)))
+	mux.Handle("GET /tasks/ws", handlerChain(http.HandlerFunc(h.events.SubscribeWebSocket)))
+	mux.Handle("GET /tasks/{id}", handlerChain(http.HandlerFunc(h.tasks.GetTask)))
+	mux.Handle("GET /tasks", handlerChain(http.HandlerFunc(h.tasks.ListTasks)))
+	mux.Handle("PUT /tasks/{id}", handlerChain(http.HandlerFunc(h.tasks.UpdateTask)))
+	mux.Handle("DELETE /tasks/{id}", handlerChain(http.HandlerFunc(h.tasks.DeleteTask)))
+	mux.Handle("POST /webhooks", handlerChain(http.HandlerFunc(h.webhooks.CreateWebhook)))
+	mux.Handle("GET /webhooks", handlerChain(http.HandlerFunc(h.webhooks.ListWebhooks)))
+	mux.Handle("GET /webhooks/dead-letters", handlerChain(http.HandlerFunc(h.webhooks.ListDeadLetters)))
+	mux.Handle("GET /webhooks/{id}", handlerChain(http.HandlerFunc(h.webhooks.GetWebhook)))
+	mux.Handle("PUT /webhooks/{id}", handlerChain(http.HandlerFunc(h.webhooks.UpdateWebhook)))
+	mux.Handle("DELETE /webhooks/{id}", handlerChain(http.HandlerFunc(h.webhooks.DeleteWebhook)))
+	mux.Handle("GET /webhooks/{id}/deliveries", handlerChain(http.HandlerFunc(h.webhooks.ListDeliveryLogs)))
+	mux.Handle("GET /metrics", handlerChain(metrics.Handler()))
+	mux.Handle("GET /admin/log-level", adminChain(http.HandlerFunc(h.logLevels.GetLogLevels)))
+	mux.Handle("PUT /admin/log-level", adminChain(http.HandlerFunc(h.logLevels.UpdateLogLevels)))
+	mux.Handle("GET /admin/backup", adminChain(http.HandlerFunc(h.tasks.BackupTasks)))
+	mux.Handle("POST /admin/restore", adminChain(http.HandlerFunc(h.tasks.RestoreTasks)))
+	mux.Handle("GET /livez", handlerChain(h.health.LivezHandler()))
+	mux.Handle("GET /readyz", handlerChain(h.health.ReadyzHandler()))
+	mux.Handle("GET /health", handlerChain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})))
+
+	return httprequestid.RequestIDMiddleware(httptracing.TracingMiddleware(h.accessLog.Middleware(httpmetrics.MetricsMiddleware(mux))))
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/app/events"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+	"github.com/supchaser/LO_test_task/internal/app/usecase"
+	"github.com/supchaser/LO_test_task/internal/middleware/idempotency"
+	"github.com/supchaser/LO_test_task/internal/middleware/logging"
+	"github.com/supchaser/LO_test_task/internal/utils/health"
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+type recordingExporter struct {
+	spans []tracing.SpanData
+	mu    sync.Mutex
+}
+
+func (e *recordingExporter) Export(ctx context.Context, spans []tracing.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func newHandler(t *testing.T, maxBodyBytes int64) http.Handler {
+	t.Helper()
+
+	return createHandler(newHandlers(t), maxBodyBytes)
+}
+
+func newHandlers(t *testing.T) handlers {
+	t.Helper()
+
+	repo := repository.CreateTaskRepository()
+	return handlers{
+		tasks:       delivery.CreateTaskDelivery(usecase.CreateTaskUsecase(repo)),
+		events:      delivery.CreateEventDelivery(events.CreateBus(10), nil),
+		webhooks:    delivery.CreateWebhookDelivery(usecase.CreateWebhookUsecase(repository.CreateWebhookRepository())),
+		logLevels:   delivery.CreateLogLevelDelivery(),
+		idempotency: idempotency.CreateStore(time.Minute),
+		accessLog:   logging.CreateAccessLog(logging.Config{}),
+		health:      health.CreateRegistry(time.Second),
+	}
+}
+
+func TestCreateHandler_RecordsRoute(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := tracing.CreateTracer(exporter)
+	previous := tracing.GetTracer()
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(previous)
+
+	handler := newHandler(t, 1<<20)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks/7", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	assert.Contains(t, metrics.Default.Gather(), `http_requests_total{method="GET",route="/tasks/{id}",code="404"} 1`)
+
+	require.NoError(t, tracer.Close(context.Background()))
+	var server []tracing.SpanData
+	for _, span := range exporter.spans {
+		if span.Kind == tracing.SpanKindServer {
+			server = append(server, span)
+		}
+	}
+	require.Len(t, server, 1)
+	assert.Equal(t, "GET /tasks/{id}", server[0].Name)
+	assert.Equal(t, "/tasks/{id}", server[0].Attributes["http.route"])
+}
+
+func TestCreateHandler_LimitsBody(t *testing.T) {
+	handler := newHandler(t, 64)
+
+	body := "[" + strings.Repeat(`{"title":"Some task"},`, 10) + `{"title":"Last task"}]`
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tasks/import", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, metrics.Default.Gather(), `http_requests_total{method="POST",route="/tasks/import",code="413"} 1`)
+}
+
+func TestCreateHandler_AdminRoutesRequireToken(t *testing.T) {
+	h := newHandlers(t)
+	h.adminToken = "admin-secret"
+	handler := createHandler(h, 1<<20)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/admin/log-level"},
+		{http.MethodPut, "/admin/log-level"},
+		{http.MethodGet, "/admin/backup"},
+		{http.MethodPost, "/admin/restore"},
+	}
+	for _, route := range routes {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(route.method, route.path, strings.NewReader("{}")))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, route.method+" "+route.path)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	newHandler(t, 1<<20).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
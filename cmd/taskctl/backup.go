@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+)
+
+func backupCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("FILE", `Save a backup of all tasks to FILE ("-" for standard output). The download is verified before FILE is written.`)
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	if _, err := api.Backup(ctx, &data); err != nil {
+		return err
+	}
+	snapshot, checksum, err := backup.Read(bytes.NewReader(data.Bytes()))
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		_, err := c.stdout.Write(data.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(path, data.Bytes()); err != nil {
+		return err
+	}
+
+	return out.backup(backupInfo{
+		File:        path,
+		Tasks:       len(snapshot.Tasks),
+		TakenAt:     snapshot.TakenAt,
+		LastEventID: snapshot.LastEventID,
+		SHA256:      checksum,
+	})
+}
+
+func restoreCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("FILE", "Verify a backup and load it into the server. The server must have no tasks.")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// A damaged file is rejected here rather than after uploading it.
+	_, checksum, err := backup.Read(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+	result, err := api.Restore(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	return out.backup(backupInfo{
+		File:        path,
+		Tasks:       result.Restored,
+		TakenAt:     result.TakenAt,
+		LastEventID: result.LastEventID,
+		SHA256:      checksum,
+	})
+}
+
+func verifyCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("FILE", "Check the checksum and contents of a backup without contacting the server.")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+
+	out, err := c.printer()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	snapshot, checksum, err := backup.Read(file)
+	if err != nil {
+		return err
+	}
+
+	return out.backup(backupInfo{
+		File:        path,
+		Tasks:       len(snapshot.Tasks),
+		TakenAt:     snapshot.TakenAt,
+		LastEventID: snapshot.LastEventID,
+		SHA256:      checksum,
+	})
+}
+
+// writeFileAtomic makes sure FILE is either the complete backup or untouched.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".taskctl-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
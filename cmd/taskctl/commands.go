@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/pkg/client"
+)
+
+var errUsage = errors.New("invalid command line")
+
+type commandFunc func(ctx context.Context, c *cli, args []string) error
+
+var commands = map[string]commandFunc{
+	"create":  createCommand,
+	"get":     getCommand,
+	"list":    listCommand,
+	"update":  updateCommand,
+	"delete":  deleteCommand,
+	"watch":   watchCommand,
+	"backup":  backupCommand,
+	"restore": restoreCommand,
+	"verify":  verifyCommand,
+}
+
+// cli holds what every command shares: the output streams and the flags for
+// reaching the server.
+type cli struct {
+	name       string
+	stdout     io.Writer
+	stderr     io.Writer
+	flags      settings
+	configPath string
+}
+
+func (c *cli) flagSet(arguments, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet("taskctl "+c.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.flags.Server, "server", "", "API base `URL` (default "+defaultServer+")")
+	fs.StringVar(&c.flags.Token, "token", "", "bearer `token` sent with every request")
+	fs.StringVar(&c.flags.Output, "o", "", "output `format`: table, json or yaml (default table)")
+	fs.StringVar(&c.configPath, "config", "", "config file `path`")
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: taskctl %s [flags] %s\n\n%s\n\nFlags:\n", c.name, arguments, summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse allows flags before and after the positional arguments and checks
+// that exactly want of the latter were given.
+func (c *cli) parse(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != want {
+		fmt.Fprintf(c.stderr, "taskctl %s: expected %d argument(s), got %d\n", c.name, want, len(positional))
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+func (c *cli) connect() (*client.Client, *printer, error) {
+	s, err := loadSettings(c.flags, c.configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []client.Option
+	if s.Token != "" {
+		opts = append(opts, client.WithBearerToken(s.Token))
+	}
+	api, err := client.CreateClient(s.Server, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	return api, &printer{w: c.stdout, format: s.Output}, nil
+}
+
+// printer is for commands that do not talk to the server.
+func (c *cli) printer() (*printer, error) {
+	s, err := loadSettings(c.flags, c.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &printer{w: c.stdout, format: s.Output}, nil
+}
+
+func parseID(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %q", client.ErrInvalidID, value)
+	}
+	return id, nil
+}
+
+func createCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("", "Create a task.")
+	var req client.CreateTaskRequest
+	fs.StringVar(&req.Title, "title", "", "task title")
+	fs.StringVar(&req.Description, "description", "", "task description")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+	task, err := api.CreateTask(ctx, req)
+	if err != nil {
+		return err
+	}
+	return out.task(task)
+}
+
+func getCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("ID", "Show a task.")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+	task, err := api.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return out.task(task)
+}
+
+func listCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("", "List tasks ordered by ID. Without -limit all tasks are listed.")
+	var status string
+	var opts client.ListOptions
+	fs.StringVar(&status, "status", "", "only tasks with this `status`: pending, in_progress or completed")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum number of tasks")
+	fs.IntVar(&opts.Offset, "offset", 0, "number of tasks to skip")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	opts.Status = client.TaskStatus(status)
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	if opts.Limit > 0 {
+		page, err := api.ListTasks(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if err := out.tasks(page.Tasks); err != nil {
+			return err
+		}
+		if out.format == outputTable && page.Offset+len(page.Tasks) < page.Total {
+			fmt.Fprintf(c.stderr, "showing %d-%d of %d tasks\n", page.Offset+1, page.Offset+len(page.Tasks), page.Total)
+		}
+		return nil
+	}
+
+	tasks := []*client.Task{}
+	for task, err := range api.Tasks(ctx, opts) {
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, task)
+	}
+	return out.tasks(tasks)
+}
+
+func updateCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("ID", "Change a task. Fields that are not given keep their values.")
+	var req client.UpdateTaskRequest
+	var status string
+	fs.StringVar(&req.Title, "title", "", "new title")
+	fs.StringVar(&req.Description, "description", "", "new description")
+	fs.StringVar(&status, "status", "", "new `status`: pending, in_progress or completed")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	req.Status = client.TaskStatus(status)
+	if req == (client.UpdateTaskRequest{}) {
+		fmt.Fprintf(c.stderr, "taskctl %s: nothing to update, set -title, -description or -status\n", c.name)
+		return errUsage
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+	task, err := api.UpdateTask(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return out.task(task)
+}
+
+func deleteCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("ID", "Delete a task.")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+	if err := api.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+	return out.deleted(id)
+}
+
+func watchCommand(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("", "Stream task events until interrupted. JSON output has one event per line.")
+	var status string
+	var opts client.WatchOptions
+	fs.StringVar(&status, "status", "", "only events of tasks with this `status`")
+	fs.Func("task-id", "only events of the task with this `ID`; may be repeated", func(value string) error {
+		id, err := parseID(value)
+		if err != nil {
+			return err
+		}
+		opts.TaskIDs = append(opts.TaskIDs, id)
+		return nil
+	})
+	fs.Uint64Var(&opts.LastEventID, "since", 0, "replay the events after this event `ID` first")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	opts.Status = client.TaskStatus(status)
+
+	api, out, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	if err := out.eventHeader(); err != nil {
+		return err
+	}
+	for event, err := range api.WatchEvents(ctx, opts) {
+		if err != nil {
+			return err
+		}
+		if err := out.event(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	defaultServer = "http://localhost:8080"
+
+	envServer = "TASKCTL_SERVER"
+	envToken  = "TASKCTL_TOKEN"
+	envOutput = "TASKCTL_OUTPUT"
+	envConfig = "TASKCTL_CONFIG"
+)
+
+// settings are read from the config file, e.g.
+//
+//	{"server": "https://tasks.example.com", "token": "...", "output": "json"}
+type settings struct {
+	Server string `json:"server"`
+	Token  string `json:"token"`
+	Output string `json:"output"`
+}
+
+// loadSettings applies, in increasing order of precedence, the defaults, the
+// config file, the environment and the flags. The default config file may be
+// missing; one named by -config or TASKCTL_CONFIG must exist.
+func loadSettings(flags settings, configPath string) (settings, error) {
+	result := settings{Server: defaultServer, Output: outputTable}
+
+	explicit := true
+	if configPath == "" {
+		configPath = os.Getenv(envConfig)
+	}
+	if configPath == "" {
+		explicit = false
+		if dir, err := os.UserConfigDir(); err == nil {
+			configPath = filepath.Join(dir, "taskctl", "config.json")
+		}
+	}
+
+	if configPath != "" {
+		file, err := readSettings(configPath)
+		if err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+			return settings{}, err
+		}
+		result.merge(file)
+	}
+
+	result.merge(settings{
+		Server: os.Getenv(envServer),
+		Token:  os.Getenv(envToken),
+		Output: os.Getenv(envOutput),
+	})
+	result.merge(flags)
+
+	if !slices.Contains(outputFormats, result.Output) {
+		return settings{}, fmt.Errorf("%w: unknown output format %q, want one of %v", errUsage, result.Output, outputFormats)
+	}
+
+	return result, nil
+}
+
+func readSettings(path string) (settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return settings{}, fmt.Errorf("read config: %w", err)
+	}
+
+	var file settings
+	if err := json.Unmarshal(data, &file); err != nil {
+		return settings{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// merge overrides the settings that are set in other.
+func (s *settings) merge(other settings) {
+	if other.Server != "" {
+		s.Server = other.Server
+	}
+	if other.Token != "" {
+		s.Token = other.Token
+	}
+	if other.Output != "" {
+		s.Output = other.Output
+	}
+}
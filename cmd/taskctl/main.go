@@ -0,0 +1,124 @@
+// Command taskctl manages tasks through the HTTP API.
+//
+//	taskctl create --title "Write docs" --description "Client SDK"
+//	taskctl list --status pending -o json
+//	taskctl watch --task-id 42
+//	taskctl backup tasks.backup
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+	"github.com/supchaser/LO_test_task/pkg/client"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotFound
+	exitValidation
+	exitCorrupt
+)
+
+const usage = `Usage: taskctl <command> [flags] [arguments]
+
+Commands:
+  create   Create a task
+  get      Show a task
+  list     List tasks
+  update   Change a task
+  delete   Delete a task
+  watch    Stream task events
+  backup   Save a backup of all tasks
+  restore  Load a backup into an empty server
+  verify   Check a backup file
+
+Run "taskctl <command> -h" for the flags of a command.
+
+Settings are taken from flags, then the environment (TASKCTL_SERVER,
+TASKCTL_TOKEN, TASKCTL_OUTPUT), then the config file (-config,
+TASKCTL_CONFIG or <user config dir>/taskctl/config.json).
+
+Exit codes:
+  0  success
+  1  request or server error
+  2  invalid command line
+  3  task not found
+  4  rejected by validation
+  5  backup file is damaged
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "taskctl: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	c := &cli{name: args[0], stdout: stdout, stderr: stderr}
+	err := cmd(ctx, c, args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		// A bare errUsage means the problem has already been reported.
+		if err != errUsage {
+			fmt.Fprintf(stderr, "taskctl %s: %v\n", c.name, err)
+		}
+		return exitUsage
+	}
+
+	fmt.Fprintf(stderr, "taskctl %s: %v\n", c.name, err)
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, client.ErrTaskNotFound):
+		return exitNotFound
+	case errors.Is(err, client.ErrValidation), errors.Is(err, client.ErrInvalidID):
+		return exitValidation
+	case errors.Is(err, backup.ErrInvalid), errors.Is(err, backup.ErrChecksumMismatch):
+		return exitCorrupt
+	}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return exitValidation
+		}
+	}
+
+	return exitError
+}
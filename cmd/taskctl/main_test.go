@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+	"github.com/supchaser/LO_test_task/internal/app/usecase"
+	"github.com/supchaser/LO_test_task/internal/middleware/idempotency"
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+	"github.com/supchaser/LO_test_task/pkg/client"
+)
+
+type testServer struct {
+	*httptest.Server
+	// authorization is the Authorization header of the last request.
+	authorization atomic.Value
+}
+
+func newServer(t *testing.T) *testServer {
+	t.Helper()
+
+	taskDelivery := delivery.CreateTaskDelivery(usecase.CreateTaskUsecase(repository.CreateTaskRepository()))
+	idempotencyStore := idempotency.CreateStore(time.Hour)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /tasks", idempotencyStore.Middleware(http.HandlerFunc(taskDelivery.CreateTask)))
+	mux.HandleFunc("GET /tasks/{id}", taskDelivery.GetTask)
+	mux.HandleFunc("GET /tasks", taskDelivery.ListTasks)
+	mux.HandleFunc("PUT /tasks/{id}", taskDelivery.UpdateTask)
+	mux.HandleFunc("DELETE /tasks/{id}", taskDelivery.DeleteTask)
+	mux.HandleFunc("GET /admin/backup", taskDelivery.BackupTasks)
+	mux.HandleFunc("POST /admin/restore", taskDelivery.RestoreTasks)
+
+	server := &testServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.authorization.Store(r.Header.Get("Authorization"))
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// isolate keeps the settings of the machine running the tests out of the way.
+func isolate(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	for _, name := range []string{envServer, envToken, envOutput, envConfig} {
+		t.Setenv(name, "")
+	}
+}
+
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func createTask(t *testing.T, server *testServer, title string) *client.Task {
+	t.Helper()
+
+	code, stdout, stderr := runCLI(t, "create", "-server", server.URL, "-o", "json", "-title", title, "-description", "From the CLI")
+	require.Equal(t, exitOK, code, stderr)
+
+	var task client.Task
+	require.NoError(t, json.Unmarshal([]byte(stdout), &task))
+	return &task
+}
+
+func TestRun_Commands(t *testing.T) {
+	isolate(t)
+	server := newServer(t)
+
+	task := createTask(t, server, "Write docs")
+	assert.Equal(t, "Write docs", task.Title)
+	createTask(t, server, "Review docs")
+	id := strconv.FormatInt(task.ID, 10)
+
+	code, stdout, _ := runCLI(t, "get", id, "-server", server.URL)
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "Title:        Write docs")
+	assert.Contains(t, stdout, "Status:       pending")
+
+	code, stdout, _ = runCLI(t, "update", "-server", server.URL, "-status", "completed", "-o", "yaml", id)
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, `status: "completed"`)
+	assert.Contains(t, stdout, `title: "Write docs"`)
+
+	code, stdout, _ = runCLI(t, "list", "-server", server.URL)
+	require.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^ID\s+TITLE\s+STATUS\s+CREATED\s+UPDATED$`, lines[0])
+	assert.Regexp(t, `^`+id+`\s+Write docs\s+completed\s`, lines[1])
+
+	code, stdout, stderr := runCLI(t, "list", "-server", server.URL, "-limit", "1", "-offset", "1")
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "Review docs")
+	assert.NotContains(t, stdout, "Write docs")
+	assert.Empty(t, stderr)
+
+	code, _, stderr = runCLI(t, "list", "-server", server.URL, "-limit", "1")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "showing 1-1 of 2 tasks\n", stderr)
+
+	code, stdout, _ = runCLI(t, "list", "-server", server.URL, "-status", "completed", "-o", "json")
+	require.Equal(t, exitOK, code)
+	var tasks []client.Task
+	require.NoError(t, json.Unmarshal([]byte(stdout), &tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.ID, tasks[0].ID)
+
+	code, stdout, _ = runCLI(t, "delete", id, "-server", server.URL)
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "Task "+id+" deleted\n", stdout)
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	isolate(t)
+	server := newServer(t)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{name: "no command", args: nil, code: exitUsage, stderr: "Usage: taskctl"},
+		{name: "help", args: []string{"help"}, code: exitOK},
+		{name: "unknown command", args: []string{"frobnicate"}, code: exitUsage, stderr: `unknown command "frobnicate"`},
+		{name: "command help", args: []string{"get", "-h"}, code: exitOK, stderr: "Usage: taskctl get [flags] ID"},
+		{name: "unknown flag", args: []string{"list", "-verbose"}, code: exitUsage, stderr: "flag provided but not defined"},
+		{name: "missing argument", args: []string{"get"}, code: exitUsage, stderr: "expected 1 argument(s), got 0"},
+		{name: "nothing to update", args: []string{"update", "1", "-server", server.URL}, code: exitUsage, stderr: "nothing to update"},
+		{name: "unknown output format", args: []string{"list", "-server", server.URL, "-o", "xml"}, code: exitUsage, stderr: `unknown output format "xml"`},
+		{name: "not found", args: []string{"get", "42", "-server", server.URL}, code: exitNotFound, stderr: "task not found"},
+		{name: "delete not found", args: []string{"delete", "42", "-server", server.URL}, code: exitNotFound, stderr: "task not found"},
+		{name: "invalid ID", args: []string{"get", "abc", "-server", server.URL}, code: exitValidation, stderr: "invalid task ID"},
+		{name: "validation", args: []string{"create", "-server", server.URL, "-title", "x"}, code: exitValidation, stderr: "validation error"},
+		{name: "server unreachable", args: []string{"list", "-server", "http://127.0.0.1:1"}, code: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tt.args...)
+			assert.Equal(t, tt.code, code, stderr)
+			assert.Contains(t, stdout+stderr, tt.stderr)
+		})
+	}
+}
+
+func TestRun_BackupAndRestore(t *testing.T) {
+	isolate(t)
+	source := newServer(t)
+	first := createTask(t, source, "Backed up")
+	createTask(t, source, "Also backed up")
+
+	path := filepath.Join(t.TempDir(), "tasks.backup")
+	code, stdout, stderr := runCLI(t, "backup", path, "-server", source.URL, "-o", "json")
+	require.Equal(t, exitOK, code, stderr)
+
+	var info backupInfo
+	require.NoError(t, json.Unmarshal([]byte(stdout), &info))
+	assert.Equal(t, path, info.File)
+	assert.Equal(t, 2, info.Tasks)
+	assert.Len(t, info.SHA256, 64)
+
+	code, stdout, stderr = runCLI(t, "verify", path)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, info.SHA256)
+
+	code, stdout, stderr = runCLI(t, "backup", "-", "-server", source.URL)
+	require.Equal(t, exitOK, code, stderr)
+	snapshot, _, err := backup.Read(strings.NewReader(stdout))
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Tasks, 2)
+
+	target := newServer(t)
+	code, stdout, stderr = runCLI(t, "restore", path, "-server", target.URL)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "Tasks:")
+
+	code, stdout, stderr = runCLI(t, "get", strconv.FormatInt(first.ID, 10), "-server", target.URL, "-o", "json")
+	require.Equal(t, exitOK, code, stderr)
+	var restored client.Task
+	require.NoError(t, json.Unmarshal([]byte(stdout), &restored))
+	assert.True(t, first.CreatedAt.Equal(restored.CreatedAt))
+
+	code, _, stderr = runCLI(t, "restore", path, "-server", target.URL)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "task store is not empty")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	damaged := filepath.Join(t.TempDir(), "damaged.backup")
+	require.NoError(t, os.WriteFile(damaged, bytes.Replace(data, []byte("Backed up"), []byte("Backed Up"), 1), 0o600))
+	for _, args := range [][]string{{"verify", damaged}, {"restore", damaged, "-server", target.URL}} {
+		code, _, stderr = runCLI(t, args...)
+		assert.Equal(t, exitCorrupt, code, args)
+		assert.Contains(t, stderr, "backup checksum mismatch")
+	}
+
+	code, _, _ = runCLI(t, "verify", filepath.Join(t.TempDir(), "missing.backup"))
+	assert.Equal(t, exitError, code)
+}
+
+func TestRun_Settings(t *testing.T) {
+	isolate(t)
+	server := newServer(t)
+	task := createTask(t, server, "Configured")
+	id := strconv.FormatInt(task.ID, 10)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"server": "`+server.URL+`", "token": "file-token", "output": "yaml"}`), 0o600))
+
+	t.Run("config file", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t, "get", id, "-config", configPath)
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, `title: "Configured"`)
+		assert.Equal(t, "Bearer file-token", server.authorization.Load())
+	})
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		t.Setenv(envConfig, configPath)
+		t.Setenv(envToken, "env-token")
+		t.Setenv(envOutput, "json")
+
+		code, stdout, stderr := runCLI(t, "get", id)
+		require.Equal(t, exitOK, code, stderr)
+		assert.Equal(t, "Bearer env-token", server.authorization.Load())
+		assert.True(t, json.Valid([]byte(stdout)))
+	})
+
+	t.Run("flags override environment", func(t *testing.T) {
+		t.Setenv(envServer, "http://127.0.0.1:1")
+		t.Setenv(envOutput, "json")
+
+		code, stdout, stderr := runCLI(t, "get", id, "-server", server.URL, "-token", "flag-token", "-o", "table")
+		require.Equal(t, exitOK, code, stderr)
+		assert.Equal(t, "Bearer flag-token", server.authorization.Load())
+		assert.Contains(t, stdout, "Title:        Configured")
+	})
+
+	t.Run("missing explicit config file", func(t *testing.T) {
+		code, _, stderr := runCLI(t, "list", "-config", filepath.Join(t.TempDir(), "missing.json"))
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "read config")
+	})
+}
+
+func TestWriteYAML(t *testing.T) {
+	var b strings.Builder
+	err := writeYAML(&b, map[string]any{
+		"title":  "Say \"hi\"\nthen leave",
+		"count":  2,
+		"tags":   []string{"a", "b"},
+		"items":  []map[string]any{{"id": 1, "done": true}, {"id": 2, "done": false}},
+		"empty":  []int{},
+		"parent": map[string]any{"child": nil},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `count: 2
+empty: []
+items:
+  - done: true
+    id: 1
+  - done: false
+    id: 2
+parent:
+  child: null
+tags:
+  - "a"
+  - "b"
+title: "Say \"hi\"\nthen leave"
+`, b.String())
+}
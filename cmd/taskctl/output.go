@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/supchaser/LO_test_task/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func (p *printer) task(task *client.Task) error {
+	if p.format != outputTable {
+		return p.value(task)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", task.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", task.Title)
+	fmt.Fprintf(tw, "Description:\t%s\n", task.Description)
+	fmt.Fprintf(tw, "Status:\t%s\n", task.Status)
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(task.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatTime(task.UpdatedAt))
+	return tw.Flush()
+}
+
+func (p *printer) tasks(tasks []*client.Task) error {
+	if p.format != outputTable {
+		return p.value(tasks)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tCREATED\tUPDATED")
+	for _, task := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", task.ID, task.Title, task.Status, formatTime(task.CreatedAt), formatTime(task.UpdatedAt))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) deleted(id int64) error {
+	if p.format != outputTable {
+		return p.value(map[string]any{"id": id, "deleted": true})
+	}
+
+	_, err := fmt.Fprintf(p.w, "Task %d deleted\n", id)
+	return err
+}
+
+type backupInfo struct {
+	File        string    `json:"file"`
+	Tasks       int       `json:"tasks"`
+	TakenAt     time.Time `json:"taken_at"`
+	LastEventID uint64    `json:"last_event_id"`
+	SHA256      string    `json:"sha256"`
+}
+
+func (p *printer) backup(info backupInfo) error {
+	if p.format != outputTable {
+		return p.value(info)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "File:\t%s\n", info.File)
+	fmt.Fprintf(tw, "Tasks:\t%d\n", info.Tasks)
+	fmt.Fprintf(tw, "Taken:\t%s\n", formatTime(info.TakenAt))
+	fmt.Fprintf(tw, "Last event ID:\t%d\n", info.LastEventID)
+	fmt.Fprintf(tw, "SHA-256:\t%s\n", info.SHA256)
+	return tw.Flush()
+}
+
+// Events are printed as they arrive, so the table has fixed column widths
+// instead of being aligned over all rows.
+const eventRowFormat = "%-8v %-20v %-14v %-12v %v\n"
+
+func (p *printer) eventHeader() error {
+	if p.format != outputTable {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(p.w, eventRowFormat, "ID", "TYPE", "TASK_ID", "STATUS", "OCCURRED_AT")
+	return err
+}
+
+func (p *printer) event(event *client.TaskEvent) error {
+	switch p.format {
+	case outputJSON:
+		// One event per line, so the stream can be piped into line-based
+		// tools.
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+		return err
+	case outputYAML:
+		if _, err := io.WriteString(p.w, "---\n"); err != nil {
+			return err
+		}
+		return p.value(event)
+	}
+
+	var status client.TaskStatus
+	if event.Task != nil {
+		status = event.Task.Status
+	}
+	_, err := fmt.Fprintf(p.w, eventRowFormat, event.ID, event.Type, event.TaskID, status, formatTime(event.OccurredAt))
+	return err
+}
+
+func (p *printer) value(v any) error {
+	if p.format == outputYAML {
+		return writeYAML(p.w, v)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(p.w, "%s\n", data)
+	return err
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// writeYAML prints the JSON form of v as YAML: keys keep their JSON names and
+// are sorted, and strings are always quoted.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	writeYAMLNode(&b, doc, 0)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func writeYAMLNode(b *strings.Builder, node any, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	switch node := node.(type) {
+	case map[string]any:
+		if len(node) == 0 {
+			b.WriteString(indent + "{}\n")
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(node)) {
+			b.WriteString(indent + yamlKey(key) + ":")
+			if isYAMLBlock(node[key]) {
+				b.WriteString("\n")
+				writeYAMLNode(b, node[key], depth+1)
+			} else {
+				b.WriteString(" " + yamlScalar(node[key]) + "\n")
+			}
+		}
+	case []any:
+		if len(node) == 0 {
+			b.WriteString(indent + "[]\n")
+			return
+		}
+		for _, item := range node {
+			// Render the item one level deeper and put the dash into the
+			// indentation of its first line.
+			var nested strings.Builder
+			writeYAMLNode(&nested, item, depth+1)
+			b.WriteString(indent + "- " + strings.TrimPrefix(nested.String(), indent+"  "))
+		}
+	default:
+		b.WriteString(indent + yamlScalar(node) + "\n")
+	}
+}
+
+func isYAMLBlock(node any) bool {
+	switch node := node.(type) {
+	case map[string]any:
+		return len(node) > 0
+	case []any:
+		return len(node) > 0
+	}
+	return false
+}
+
+func yamlKey(key string) string {
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
+
+func yamlScalar(node any) string {
+	switch node := node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(node)
+	case json.Number:
+		return node.String()
+	case string:
+		return strconv.Quote(node)
+	case map[string]any:
+		return "{}"
+	case []any:
+		return "[]"
+	}
+	return fmt.Sprint(node)
+}
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+// BackupTasks streams a consistent snapshot of the store in the backup file
+// format. If writing fails midway the file lacks its checksum line, so a
+// truncated download is detected on restore.
+func (d *TaskDelivery) BackupTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.BackupTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	snapshot, err := d.taskUsecase.Snapshot(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to take snapshot", err, map[string]any{
+			"method": funcName,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	filename := "tasks-" + snapshot.TakenAt.UTC().Format("20060102T150405Z") + ".backup"
+	w.Header().Set("Content-Type", backup.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(len(snapshot.Tasks)))
+
+	checksum, err := backup.Write(w, snapshot)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to write backup", err, map[string]any{
+			"method": funcName,
+		})
+		return
+	}
+
+	logger.InfoContext(ctx, "backup written", map[string]any{
+		"method":        funcName,
+		"count":         len(snapshot.Tasks),
+		"last_event_id": snapshot.LastEventID,
+		"sha256":        checksum,
+	})
+}
+
+// RestoreTasks loads a backup into the store, which must be empty: restoring
+// is meant for a freshly started service, not for merging into live data.
+func (d *TaskDelivery) RestoreTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.RestoreTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	snapshot, checksum, err := backup.Read(r.Body)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to read backup", err, map[string]any{
+			"method": funcName,
+		})
+		respondWithReadError(w, r, err)
+		return
+	}
+
+	if err := d.taskUsecase.RestoreSnapshot(ctx, snapshot); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to restore backup", err, map[string]any{
+			"method": funcName,
+			"count":  len(snapshot.Tasks),
+			"sha256": checksum,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(models.RestoreResponse{
+		Restored:    len(snapshot.Tasks),
+		TakenAt:     snapshot.TakenAt,
+		LastEventID: snapshot.LastEventID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
+			"method": funcName,
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+var testSnapshot = &models.Snapshot{
+	TakenAt:     time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	LastEventID: 42,
+	Tasks: []*models.Task{
+		{ID: 1, Title: "First task", Status: models.StatusPending},
+		{ID: 2, Title: "Second task", Status: models.StatusCompleted},
+	},
+}
+
+func TestTaskDelivery_BackupTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase.EXPECT().Snapshot(gomock.Any()).Return(testSnapshot, nil)
+
+		w := httptest.NewRecorder()
+		delivery.BackupTasks(w, httptest.NewRequest(http.MethodGet, "/admin/backup", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, backup.ContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="tasks-20240601T120000Z.backup"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "2", w.Header().Get(HeaderTotalCount))
+
+		snapshot, _, err := backup.Read(w.Body)
+		require.NoError(t, err)
+		assert.Equal(t, testSnapshot.LastEventID, snapshot.LastEventID)
+		assert.Equal(t, testSnapshot.Tasks, snapshot.Tasks)
+	})
+
+	t.Run("Usecase Error", func(t *testing.T) {
+		mockUsecase.EXPECT().Snapshot(gomock.Any()).Return(nil, errors.New("storage error"))
+
+		w := httptest.NewRecorder()
+		delivery.BackupTasks(w, httptest.NewRequest(http.MethodGet, "/admin/backup", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestTaskDelivery_RestoreTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	var file bytes.Buffer
+	_, err := backup.Write(&file, testSnapshot)
+	require.NoError(t, err)
+	valid := file.String()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: valid,
+			mockSetup: func() {
+				mockUsecase.EXPECT().RestoreSnapshot(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, snapshot *models.Snapshot) error {
+						assert.Equal(t, testSnapshot.Tasks, snapshot.Tasks)
+						return nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Store Not Empty",
+			body: valid,
+			mockSetup: func() {
+				mockUsecase.EXPECT().RestoreSnapshot(gomock.Any(), gomock.Any()).Return(errs.ErrStoreNotEmpty)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Invalid Task",
+			body: valid,
+			mockSetup: func() {
+				mockUsecase.EXPECT().RestoreSnapshot(gomock.Any(), gomock.Any()).Return(errs.ErrValidation)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Checksum Mismatch",
+			body:           strings.Replace(valid, "First task", "Other task", 1),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Truncated",
+			body:           valid[:len(valid)/2],
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			delivery.RestoreTasks(w, httptest.NewRequest(http.MethodPost, "/admin/restore", strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp models.RestoreResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, models.RestoreResponse{Restored: 2, TakenAt: testSnapshot.TakenAt, LastEventID: 42}, resp)
+		})
+	}
+}
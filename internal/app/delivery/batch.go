@@ -0,0 +1,136 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+func (d *TaskDelivery) BatchCreateTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.BatchCreateTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	var req models.BatchCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := d.taskUsecase.BatchCreateTasks(ctx, req.Items, req.Atomic)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to create tasks", err, map[string]any{
+			"method": funcName,
+			"count":  len(req.Items),
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithBatch(w, r, funcName, results, http.StatusCreated)
+}
+
+func (d *TaskDelivery) BatchUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.BatchUpdateTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	var req models.BatchUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := d.taskUsecase.BatchUpdateTasks(ctx, req.Items, req.Atomic)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update tasks", err, map[string]any{
+			"method": funcName,
+			"count":  len(req.Items),
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithBatch(w, r, funcName, results, http.StatusOK)
+}
+
+func (d *TaskDelivery) BatchDeleteTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.BatchDeleteTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	var req models.BatchDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := d.taskUsecase.BatchDeleteTasks(ctx, req.IDs, req.Atomic)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to delete tasks", err, map[string]any{
+			"method": funcName,
+			"count":  len(req.IDs),
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithBatch(w, r, funcName, results, http.StatusNoContent)
+}
+
+func respondWithBatch(w http.ResponseWriter, r *http.Request, funcName string, results []models.BatchItemResult, successStatus int) {
+	resp := models.BatchResponse{
+		Results: make([]models.BatchItemResponse, len(results)),
+	}
+
+	for i, result := range results {
+		item := models.BatchItemResponse{
+			Index:  result.Index,
+			ID:     result.ID,
+			Status: successStatus,
+			Task:   result.Task,
+		}
+
+		if result.Err != nil {
+			item.Status = errorStatus(result.Err)
+			item.Error = result.Err.Error()
+			if item.Status == http.StatusInternalServerError {
+				item.Error = "internal server error"
+			}
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+
+		resp.Results[i] = item
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.ErrorContext(r.Context(), "failed to encode response", err, map[string]any{
+			"method": funcName,
+		})
+	}
+}
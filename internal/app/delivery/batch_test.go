@@ -0,0 +1,167 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestTaskDelivery_BatchCreateTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	tests := []struct {
+		name             string
+		requestBody      interface{}
+		mockSetup        func()
+		expectedStatus   int
+		expectedStatuses []int
+	}{
+		{
+			name: "Per Item Results",
+			requestBody: models.BatchCreateRequest{
+				Items: []models.CreateTaskRequest{{Title: "Task One"}, {Title: ""}},
+			},
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					BatchCreateTasks(gomock.Any(), gomock.Len(2), false).
+					Return([]models.BatchItemResult{
+						{Index: 0, ID: 1, Task: &models.Task{ID: 1, Title: "Task One"}},
+						{Index: 1, Err: errs.ErrValidation},
+					}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []int{http.StatusCreated, http.StatusBadRequest},
+		},
+		{
+			name: "Atomic Aborted",
+			requestBody: models.BatchCreateRequest{
+				Items:  []models.CreateTaskRequest{{Title: "Task One"}, {Title: ""}},
+				Atomic: true,
+			},
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					BatchCreateTasks(gomock.Any(), gomock.Len(2), true).
+					Return([]models.BatchItemResult{
+						{Index: 0, Err: errs.ErrBatchAborted},
+						{Index: 1, Err: errs.ErrValidation},
+					}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusBadRequest},
+		},
+		{
+			name: "Batch Too Large",
+			requestBody: models.BatchCreateRequest{
+				Items: []models.CreateTaskRequest{{Title: "Task One"}},
+			},
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					BatchCreateTasks(gomock.Any(), gomock.Any(), false).
+					Return(nil, errs.ErrBatchTooLarge)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Invalid Request Body",
+			requestBody:    "invalid",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/tasks:batchCreate", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			delivery.BatchCreateTasks(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatuses != nil {
+				assertBatchStatuses(t, w, tt.expectedStatuses)
+			}
+		})
+	}
+}
+
+func TestTaskDelivery_BatchUpdateTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	mockUsecase.EXPECT().
+		BatchUpdateTasks(gomock.Any(), []models.BatchUpdateItem{{ID: 1, Status: models.StatusCompleted}}, false).
+		Return([]models.BatchItemResult{
+			{Index: 0, ID: 1, Task: &models.Task{ID: 1, Status: models.StatusCompleted}},
+		}, nil)
+
+	body, _ := json.Marshal(models.BatchUpdateRequest{
+		Items: []models.BatchUpdateItem{{ID: 1, Status: models.StatusCompleted}},
+	})
+	req := httptest.NewRequest("POST", "/tasks:batchUpdate", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	delivery.BatchUpdateTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertBatchStatuses(t, w, []int{http.StatusOK})
+}
+
+func TestTaskDelivery_BatchDeleteTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	mockUsecase.EXPECT().
+		BatchDeleteTasks(gomock.Any(), []int64{1, 2}, true).
+		Return([]models.BatchItemResult{
+			{Index: 0, ID: 1},
+			{Index: 1, ID: 2},
+		}, nil)
+
+	body, _ := json.Marshal(models.BatchDeleteRequest{IDs: []int64{1, 2}, Atomic: true})
+	req := httptest.NewRequest("POST", "/tasks:batchDelete", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	delivery.BatchDeleteTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertBatchStatuses(t, w, []int{http.StatusNoContent, http.StatusNoContent})
+}
+
+func assertBatchStatuses(t *testing.T, w *httptest.ResponseRecorder, expected []int) {
+	t.Helper()
+
+	var resp models.BatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Results, len(expected))
+
+	failed := 0
+	for i, status := range expected {
+		assert.Equal(t, status, resp.Results[i].Status)
+		if status >= http.StatusBadRequest {
+			failed++
+		}
+	}
+	assert.Equal(t, failed, resp.Failed)
+	assert.Equal(t, len(expected)-failed, resp.Succeeded)
+}
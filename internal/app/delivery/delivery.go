@@ -1,8 +1,11 @@
 package delivery
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"iter"
 	"net/http"
 	"strconv"
 
@@ -10,6 +13,17 @@ import (
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+	"github.com/supchaser/LO_test_task/internal/utils/taskcodec"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+const (
+	// HeaderTotalCount carries the number of tasks matching a list request
+	// before limit and offset are applied.
+	HeaderTotalCount = "X-Total-Count"
+
+	MaxPageSize = 1000
 )
 
 type TaskDelivery struct {
@@ -25,29 +39,38 @@ func CreateTaskDelivery(taskUsecase app.TaskUsecase) *TaskDelivery {
 func (d *TaskDelivery) CreateTask(w http.ResponseWriter, r *http.Request) {
 	const funcName = "Delivery.CreateTask"
 
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
 	var req models.CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error("failed to decode request", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
 			"method": funcName,
 		})
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	task, err := d.taskUsecase.CreateTask(r.Context(), req.Title, req.Description)
+	format, ok := responseFormat(ctx, span, w, r, funcName)
+	if !ok {
+		return
+	}
+
+	task, err := d.taskUsecase.CreateTask(ctx, req.Title, req.Description)
 	if err != nil {
-		logger.Error("failed to create task", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to create task", err, map[string]any{
 			"method": funcName,
 			"title":  req.Title,
 		})
-		respondWithError(w, err)
+		respondWithError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		logger.Error("failed to encode response", err, map[string]any{
+	if err := writeTask(w, format, http.StatusCreated, task); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
 			"method":  funcName,
 			"task_id": task.ID,
 		})
@@ -57,124 +80,275 @@ func (d *TaskDelivery) CreateTask(w http.ResponseWriter, r *http.Request) {
 func (d *TaskDelivery) GetTask(w http.ResponseWriter, r *http.Request) {
 	const funcName = "Delivery.GetTask"
 
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		logger.Error("invalid task ID", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid task ID", err, map[string]any{
 			"method": funcName,
 			"id":     idStr,
 		})
-		http.Error(w, "invalid task ID", http.StatusBadRequest)
+		respond.Error(w, r, "invalid task ID", http.StatusBadRequest)
 		return
 	}
 
-	task, err := d.taskUsecase.GetTask(r.Context(), id)
+	format, ok := responseFormat(ctx, span, w, r, funcName)
+	if !ok {
+		return
+	}
+
+	task, err := d.taskUsecase.GetTask(ctx, id)
 	if err != nil {
-		logger.Error("failed to get task", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to get task", err, map[string]any{
 			"method": funcName,
 			"id":     id,
 		})
-		respondWithError(w, err)
+		respondWithError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(task)
+	if err := writeTask(w, format, http.StatusOK, task); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
+			"method":  funcName,
+			"task_id": task.ID,
+		})
+	}
 }
 
 func (d *TaskDelivery) ListTasks(w http.ResponseWriter, r *http.Request) {
 	const funcName = "Delivery.ListTasks"
 
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
 	statusFilter := models.TaskStatus(r.URL.Query().Get("status"))
 
-	tasks, err := d.taskUsecase.ListTasks(r.Context(), statusFilter)
+	limit, offset, err := parsePagination(r)
 	if err != nil {
-		logger.Error("failed to list tasks", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid pagination parameters", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format, ok := responseFormat(ctx, span, w, r, funcName)
+	if !ok {
+		return
+	}
+
+	total, err := d.taskUsecase.CountTasks(ctx, statusFilter)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to count tasks", err, map[string]any{
 			"method": funcName,
 			"status": statusFilter,
 		})
-		respondWithError(w, err)
+		respondWithError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tasks)
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(total))
+
+	if _, err := writeTasks(w, format, d.taskUsecase.ListTasks(ctx, statusFilter, offset, limit)); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to write tasks", err, map[string]any{
+			"method": funcName,
+		})
+	}
+}
+
+// parsePagination reads the optional limit and offset query parameters; a
+// zero limit means no limit.
+func parsePagination(r *http.Request) (int, int, error) {
+	query := r.URL.Query()
+
+	var limit, offset int
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return 0, 0, fmt.Errorf("invalid limit %q: must be between 1 and %d", value, MaxPageSize)
+		}
+		limit = n
+	}
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q: must not be negative", value)
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
 }
 
 func (d *TaskDelivery) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	const funcName = "Delivery.UpdateTask"
 
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		logger.Error("invalid task ID", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid task ID", err, map[string]any{
 			"method": funcName,
 			"id":     idStr,
 		})
-		http.Error(w, "invalid task ID", http.StatusBadRequest)
+		respond.Error(w, r, "invalid task ID", http.StatusBadRequest)
 		return
 	}
 
 	req := models.UpdateTaskRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error("failed to decode request", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
 			"method": funcName,
 			"id":     id,
 		})
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	task, err := d.taskUsecase.UpdateTask(r.Context(), id, req.Title, req.Description, req.Status)
+	format, ok := responseFormat(ctx, span, w, r, funcName)
+	if !ok {
+		return
+	}
+
+	task, err := d.taskUsecase.UpdateTask(ctx, id, req.Title, req.Description, req.Status)
 	if err != nil {
-		logger.Error("failed to update task", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update task", err, map[string]any{
 			"method": funcName,
 			"id":     id,
 		})
-		respondWithError(w, err)
+		respondWithError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(task)
+	if err := writeTask(w, format, http.StatusOK, task); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
+			"method":  funcName,
+			"task_id": task.ID,
+		})
+	}
 }
 
 func (d *TaskDelivery) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	const funcName = "Delivery.DeleteTask"
 
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		logger.Error("invalid task ID", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid task ID", err, map[string]any{
 			"method": funcName,
 			"id":     idStr,
 		})
-		http.Error(w, "invalid task ID", http.StatusBadRequest)
+		respond.Error(w, r, "invalid task ID", http.StatusBadRequest)
 		return
 	}
 
-	if err := d.taskUsecase.DeleteTask(r.Context(), id); err != nil {
-		logger.Error("failed to delete task", err, map[string]any{
+	if err := d.taskUsecase.DeleteTask(ctx, id); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to delete task", err, map[string]any{
 			"method": funcName,
 			"id":     id,
 		})
-		respondWithError(w, err)
+		respondWithError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func respondWithError(w http.ResponseWriter, err error) {
+// responseFormat picks the format of a task response from the Accept header
+// and answers 406 itself when none of the accepted types is supported. It is
+// called once the request is validated and before the usecase, so a 406 never
+// follows a change. Errors are always written as JSON.
+func responseFormat(ctx context.Context, span *tracing.Span, w http.ResponseWriter, r *http.Request, funcName string) (taskcodec.Format, bool) {
+	format, err := taskcodec.FormatForAccept(r.Header.Get("Accept"))
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "unsupported response format", err, map[string]any{
+			"method": funcName,
+			"accept": r.Header.Get("Accept"),
+		})
+		respond.Error(w, r, err.Error(), http.StatusNotAcceptable)
+		return "", false
+	}
+
+	return format, true
+}
+
+func writeTask(w http.ResponseWriter, format taskcodec.Format, status int, task *models.Task) error {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+
+	return taskcodec.WriteTask(w, format, task)
+}
+
+// writeTasks encodes the tasks one at a time as the sequence yields them, so a
+// large list is never held in memory. An error from the sequence stops the
+// output; by then the status has already been sent.
+func writeTasks(w http.ResponseWriter, format taskcodec.Format, tasks iter.Seq2[*models.Task, error]) (int, error) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Add("Vary", "Accept")
+
+	encoder := taskcodec.CreateEncoder(w, format)
+	written := 0
+	for task, err := range tasks {
+		if err != nil {
+			return written, err
+		}
+		if err := encoder.Encode(task); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, encoder.Close()
+}
+
+func errorStatus(err error) int {
 	switch {
 	case errors.Is(err, errs.ErrTaskNotFound):
-		http.Error(w, err.Error(), http.StatusNotFound)
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrWebhookNotFound):
+		return http.StatusNotFound
 	case errors.Is(err, errs.ErrInvalidID):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		return http.StatusBadRequest
 	case errors.Is(err, errs.ErrValidation):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrBatchTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, errs.ErrBatchAborted):
+		return http.StatusFailedDependency
+	case errors.Is(err, errs.ErrStoreNotEmpty):
+		return http.StatusConflict
 	default:
-		logger.Error("unhandled error", err, nil)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
 }
+
+func respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.ErrorContext(r.Context(), "unhandled error", err, nil)
+		respond.Error(w, r, "internal server error", status)
+		return
+	}
+
+	respond.Error(w, r, err.Error(), status)
+}
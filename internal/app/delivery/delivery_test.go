@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"iter"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,8 +16,21 @@ import (
 	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
 )
 
+// taskSeq stands in for the listing the usecase reads page by page.
+func taskSeq(tasks []*models.Task) iter.Seq2[*models.Task, error] {
+	return func(yield func(*models.Task, error) bool) {
+		for _, task := range tasks {
+			if !yield(task, nil) {
+				return
+			}
+		}
+	}
+}
+
 func TestTaskDelivery_CreateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -193,16 +208,17 @@ func TestTaskDelivery_ListTasks(t *testing.T) {
 			name:         "Success - No Filter",
 			statusFilter: "",
 			mockSetup: func(statusFilter models.TaskStatus) {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), statusFilter).Return(1, nil)
 				mockUsecase.EXPECT().
-					ListTasks(gomock.Any(), statusFilter).
-					Return([]*models.Task{
+					ListTasks(gomock.Any(), statusFilter, 0, 0).
+					Return(taskSeq([]*models.Task{
 						{
 							ID:          1,
 							Title:       "Task 1",
 							Description: "Description 1",
 							Status:      models.StatusPending,
 						},
-					}, nil)
+					}))
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -210,16 +226,17 @@ func TestTaskDelivery_ListTasks(t *testing.T) {
 			name:         "Success - With Filter",
 			statusFilter: "pending",
 			mockSetup: func(statusFilter models.TaskStatus) {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), statusFilter).Return(1, nil)
 				mockUsecase.EXPECT().
-					ListTasks(gomock.Any(), statusFilter).
-					Return([]*models.Task{
+					ListTasks(gomock.Any(), statusFilter, 0, 0).
+					Return(taskSeq([]*models.Task{
 						{
 							ID:          1,
 							Title:       "Task 1",
 							Description: "Description 1",
 							Status:      models.StatusPending,
 						},
-					}, nil)
+					}))
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -228,8 +245,8 @@ func TestTaskDelivery_ListTasks(t *testing.T) {
 			statusFilter: "",
 			mockSetup: func(statusFilter models.TaskStatus) {
 				mockUsecase.EXPECT().
-					ListTasks(gomock.Any(), statusFilter).
-					Return(nil, errors.New("internal error"))
+					CountTasks(gomock.Any(), statusFilter).
+					Return(0, errors.New("internal error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -250,6 +267,225 @@ func TestTaskDelivery_ListTasks(t *testing.T) {
 	}
 }
 
+func TestTaskDelivery_ListTasksPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	tasks := []*models.Task{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	tests := []struct {
+		name           string
+		query          string
+		offset, limit  int
+		expectedStatus int
+		expectedIDs    []int64
+	}{
+		{name: "No Pagination", query: "", expectedStatus: http.StatusOK, expectedIDs: []int64{1, 2, 3}},
+		{name: "First Page", query: "?limit=2", limit: 2, expectedStatus: http.StatusOK, expectedIDs: []int64{1, 2}},
+		{name: "Last Page", query: "?limit=2&offset=2", offset: 2, limit: 2, expectedStatus: http.StatusOK, expectedIDs: []int64{3}},
+		{name: "Offset Past End", query: "?offset=10", offset: 10, expectedStatus: http.StatusOK, expectedIDs: []int64{}},
+		{name: "Zero Limit", query: "?limit=0", expectedStatus: http.StatusBadRequest},
+		{name: "Limit Too Large", query: "?limit=1001", expectedStatus: http.StatusBadRequest},
+		{name: "Negative Offset", query: "?offset=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedStatus == http.StatusOK {
+				page := tasks[min(tt.offset, len(tasks)):]
+				if tt.limit > 0 {
+					page = page[:min(tt.limit, len(page))]
+				}
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(len(tasks), nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), tt.offset, tt.limit).Return(taskSeq(page))
+			}
+
+			req := httptest.NewRequest("GET", "/tasks"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			delivery.ListTasks(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var got []*models.Task
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			ids := []int64{}
+			for _, task := range got {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, "3", w.Header().Get(HeaderTotalCount))
+		})
+	}
+}
+
+func TestTaskDelivery_ResponseFormats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	task := &models.Task{ID: 1, Title: "First task", Status: models.StatusPending}
+	tasks := []*models.Task{task, {ID: 2, Title: "Second task", Status: models.StatusCompleted}}
+
+	tests := []struct {
+		name                string
+		method              string
+		url                 string
+		body                string
+		accept              string
+		mockSetup           func()
+		handler             func(w http.ResponseWriter, r *http.Request)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:   "Get As CSV",
+			method: http.MethodGet,
+			url:    "/tasks/1",
+			accept: "text/csv",
+			mockSetup: func() {
+				mockUsecase.EXPECT().GetTask(gomock.Any(), int64(1)).Return(task, nil)
+			},
+			handler:             delivery.GetTask,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,title,description,status,created_at,updated_at\n1,First task,,pending,,\n",
+		},
+		{
+			name:   "Get As XML",
+			method: http.MethodGet,
+			url:    "/tasks/1",
+			accept: "text/html, application/xml;q=0.9",
+			mockSetup: func() {
+				mockUsecase.EXPECT().GetTask(gomock.Any(), int64(1)).Return(task, nil)
+			},
+			handler:             delivery.GetTask,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				"<task><id>1</id><title>First task</title><description></description><status>pending</status><created_at></created_at><updated_at></updated_at></task>\n",
+		},
+		{
+			name:   "Create As NDJSON",
+			method: http.MethodPost,
+			url:    "/tasks",
+			body:   `{"title":"First task"}`,
+			accept: "application/x-ndjson",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CreateTask(gomock.Any(), "First task", "").Return(task, nil)
+			},
+			handler:             delivery.CreateTask,
+			expectedStatus:      http.StatusCreated,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"id":1,"title":"First task","description":"","status":"pending","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:   "List As CSV",
+			method: http.MethodGet,
+			url:    "/tasks?limit=1&offset=1",
+			accept: "text/*",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(len(tasks), nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), 1, 1).Return(taskSeq(tasks[1:2]))
+			},
+			handler:             delivery.ListTasks,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,title,description,status,created_at,updated_at\n2,Second task,,completed,,\n",
+		},
+		{
+			name:   "List As XML",
+			method: http.MethodGet,
+			url:    "/tasks",
+			accept: "application/xml",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(0, nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), 0, 0).Return(taskSeq(nil))
+			},
+			handler:             delivery.ListTasks,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n<tasks>\n</tasks>\n",
+		},
+		{
+			name:   "Update As JSON By Wildcard",
+			method: http.MethodPut,
+			url:    "/tasks/1",
+			body:   `{"status":"pending"}`,
+			accept: "*/*",
+			mockSetup: func() {
+				mockUsecase.EXPECT().UpdateTask(gomock.Any(), int64(1), "", "", models.StatusPending).Return(task, nil)
+			},
+			handler:             delivery.UpdateTask,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"id":1,"title":"First task","description":"","status":"pending","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:                "Get Not Acceptable",
+			method:              http.MethodGet,
+			url:                 "/tasks/1",
+			accept:              "text/html",
+			mockSetup:           func() {},
+			handler:             delivery.GetTask,
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "Create Not Acceptable",
+			method:              http.MethodPost,
+			url:                 "/tasks",
+			body:                `{"title":"First task"}`,
+			accept:              "image/png",
+			mockSetup:           func() {},
+			handler:             delivery.CreateTask,
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+		},
+		{
+			name:   "Error Stays JSON",
+			method: http.MethodGet,
+			url:    "/tasks/1",
+			accept: "text/csv",
+			mockSetup: func() {
+				mockUsecase.EXPECT().GetTask(gomock.Any(), int64(1)).Return(nil, errs.ErrTaskNotFound)
+			},
+			handler:             delivery.GetTask,
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			tt.handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
 func TestTaskDelivery_UpdateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -416,32 +652,44 @@ func TestTaskDelivery_DeleteTask(t *testing.T) {
 
 func TestRespondWithError(t *testing.T) {
 	tests := []struct {
-		name           string
-		err            error
-		expectedStatus int
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
 	}{
 		{
-			name:           "Task Not Found",
-			err:            errs.ErrTaskNotFound,
-			expectedStatus: http.StatusNotFound,
+			name:            "Task Not Found",
+			err:             errs.ErrTaskNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: errs.ErrTaskNotFound.Error(),
 		},
 		{
-			name:           "Validation Error",
-			err:            errs.ErrValidation,
-			expectedStatus: http.StatusBadRequest,
+			name:            "Validation Error",
+			err:             errs.ErrValidation,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: errs.ErrValidation.Error(),
 		},
 		{
-			name:           "Internal Server Error",
-			err:            errors.New("internal error"),
-			expectedStatus: http.StatusInternalServerError,
+			name:            "Internal Server Error",
+			err:             errors.New("internal error"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal server error",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/tasks/1", nil)
+			req = req.WithContext(requestid.NewContext(req.Context(), "req-123"))
 			w := httptest.NewRecorder()
-			respondWithError(w, tt.err)
+
+			respondWithError(w, req, tt.err)
+
+			var body respond.ErrorBody
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, respond.ErrorBody{Error: tt.expectedMessage, RequestID: "req-123"}, body)
 		})
 	}
 }
@@ -0,0 +1,191 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetryMillis       = 3000
+)
+
+type EventDelivery struct {
+	eventStream       app.EventStream
+	heartbeatInterval time.Duration
+	pingInterval      time.Duration
+	allowedOrigins    []string
+	shutdown          chan struct{}
+	shutdownOnce      sync.Once
+}
+
+// CreateEventDelivery serves the SSE and WebSocket streams. Browser pages may
+// open WebSocket connections from the server's own origin and from
+// allowedOrigins only.
+func CreateEventDelivery(eventStream app.EventStream, allowedOrigins []string) *EventDelivery {
+	return &EventDelivery{
+		eventStream:       eventStream,
+		heartbeatInterval: sseHeartbeatInterval,
+		pingInterval:      wsPingInterval,
+		allowedOrigins:    allowedOrigins,
+		shutdown:          make(chan struct{}),
+	}
+}
+
+func (d *EventDelivery) Shutdown() {
+	d.shutdownOnce.Do(func() {
+		close(d.shutdown)
+	})
+}
+
+func (d *EventDelivery) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.StreamEvents"
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "invalid event filter", err, map[string]any{
+			"method": funcName,
+			"query":  r.URL.RawQuery,
+		})
+		respond.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "invalid last event ID", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid last event ID", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := d.eventStream.Subscribe(filter, lastEventID)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "failed to subscribe to events", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "event stream is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	if err := rc.Flush(); err != nil {
+		logger.ErrorContext(r.Context(), "streaming is not supported", err, map[string]any{
+			"method": funcName,
+		})
+		return
+	}
+
+	logger.InfoContext(r.Context(), "event stream opened", map[string]any{
+		"method":        funcName,
+		"last_event_id": lastEventID,
+		"status_filter": filter.Status,
+	})
+
+	heartbeat := time.NewTicker(d.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.InfoContext(r.Context(), "event stream closed by client", map[string]any{
+				"method": funcName,
+			})
+			return
+		case <-d.shutdown:
+			logger.InfoContext(r.Context(), "event stream closed by server", map[string]any{
+				"method": funcName,
+			})
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				logger.InfoContext(r.Context(), "event stream closed by server", map[string]any{
+					"method": funcName,
+				})
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				logger.ErrorContext(r.Context(), "failed to write event", err, map[string]any{
+					"method":   funcName,
+					"event_id": event.ID,
+				})
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event models.TaskEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func parseEventFilter(r *http.Request) (models.EventFilter, error) {
+	query := r.URL.Query()
+	// Tasks have no project, so a project filter cannot be honoured; it is
+	// rejected rather than ignored, which would stream every project.
+	if query.Has("project") {
+		return models.EventFilter{}, errors.New("project filter is not supported: tasks have no project")
+	}
+
+	filter := models.EventFilter{
+		Status: models.TaskStatus(query.Get("status")),
+	}
+
+	for _, value := range query["task_id"] {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return models.EventFilter{}, fmt.Errorf("invalid task_id %q", value)
+		}
+		filter.TaskIDs = append(filter.TaskIDs, id)
+	}
+
+	return filter, nil
+}
+
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get(LastEventIDHeader)
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,110 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+func TestEventDelivery_StreamEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStream := mock_app.NewMockEventStream(ctrl)
+	mockSub := mock_app.NewMockEventSubscription(ctrl)
+	delivery := CreateEventDelivery(mockStream, nil)
+
+	events := make(chan models.TaskEvent, 2)
+	events <- models.TaskEvent{ID: 8, Type: models.EventTaskCreated, TaskID: 1, Task: &models.Task{ID: 1, Status: models.StatusPending}}
+	events <- models.TaskEvent{ID: 9, Type: models.EventTaskDeleted, TaskID: 1}
+	close(events)
+
+	mockStream.EXPECT().
+		Subscribe(models.EventFilter{Status: models.StatusPending, TaskIDs: []int64{1, 2}}, uint64(7)).
+		Return(mockSub, nil)
+	mockSub.EXPECT().Events().Return(events).AnyTimes()
+	mockSub.EXPECT().Close()
+
+	req := httptest.NewRequest("GET", "/tasks/events?status=pending&task_id=1&task_id=2", nil)
+	req.Header.Set(LastEventIDHeader, "7")
+	w := httptest.NewRecorder()
+
+	delivery.StreamEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "retry: "))
+	assert.Contains(t, body, "id: 8\nevent: task.created\ndata: {\"id\":8,")
+	assert.Contains(t, body, "id: 9\nevent: task.deleted\n")
+	assert.True(t, w.Flushed)
+}
+
+func TestEventDelivery_StreamEventsErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStream := mock_app.NewMockEventStream(ctrl)
+	delivery := CreateEventDelivery(mockStream, nil)
+
+	tests := []struct {
+		name           string
+		url            string
+		lastEventID    string
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name:           "Invalid Task ID",
+			url:            "/tasks/events?task_id=abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Project Filter",
+			url:            "/tasks/events?project=backend",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Last Event ID",
+			url:            "/tasks/events",
+			lastEventID:    "-1",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Stream Closed",
+			url:  "/tasks/events",
+			mockSetup: func() {
+				mockStream.EXPECT().
+					Subscribe(gomock.Any(), uint64(0)).
+					Return(nil, errors.New("closed"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set(LastEventIDHeader, tt.lastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			delivery.StreamEvents(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
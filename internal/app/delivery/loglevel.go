@@ -0,0 +1,73 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+type LogLevelDelivery struct{}
+
+func CreateLogLevelDelivery() *LogLevelDelivery {
+	return &LogLevelDelivery{}
+}
+
+func (d *LogLevelDelivery) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.GetLogLevels"
+
+	_, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logger.Levels())
+}
+
+// UpdateLogLevels changes thresholds without a restart. An omitted "global"
+// keeps the current global level and omitted "components" keep the current
+// component levels; a present "components" object replaces them all.
+func (d *LogLevelDelivery) UpdateLogLevels(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.UpdateLogLevels"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	var req logger.LevelSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings := logger.Levels()
+	if req.Global != "" {
+		settings.Global = req.Global
+	}
+	if req.Components != nil {
+		settings.Components = req.Components
+	}
+
+	if err := logger.SetLevels(settings); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update log levels", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	settings = logger.Levels()
+	logger.InfoContext(ctx, "log levels updated", map[string]any{
+		"method":     funcName,
+		"global":     settings.Global,
+		"components": settings.Components,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
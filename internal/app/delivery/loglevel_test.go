@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+func TestLogLevelDelivery_UpdateLogLevels(t *testing.T) {
+	delivery := CreateLogLevelDelivery()
+
+	tests := []struct {
+		name           string
+		initial        logger.LevelSettings
+		body           string
+		expectedStatus int
+		expected       logger.LevelSettings
+	}{
+		{
+			name:           "Change Global Keeps Components",
+			initial:        logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{"Repository.*": logger.LevelWarn}},
+			body:           `{"global":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expected:       logger.LevelSettings{Global: logger.LevelDebug, Components: map[string]string{"Repository.*": logger.LevelWarn}},
+		},
+		{
+			name:           "Replace Components",
+			initial:        logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{"Repository.*": logger.LevelWarn}},
+			body:           `{"components":{"Usecase.*":"trace"}}`,
+			expectedStatus: http.StatusOK,
+			expected:       logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{"Usecase.*": logger.LevelTrace}},
+		},
+		{
+			name:           "Clear Components",
+			initial:        logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{"Repository.*": logger.LevelWarn}},
+			body:           `{"components":{}}`,
+			expectedStatus: http.StatusOK,
+			expected:       logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{}},
+		},
+		{
+			name:           "Unknown Level",
+			initial:        logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{}},
+			body:           `{"global":"loud"}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{}},
+		},
+		{
+			name:           "Invalid Body",
+			initial:        logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{}},
+			body:           `invalid`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       logger.LevelSettings{Global: logger.LevelInfo, Components: map[string]string{}},
+		},
+	}
+
+	previous := logger.Levels()
+	defer logger.SetLevels(previous)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, logger.SetLevels(tt.initial))
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			delivery.UpdateLogLevels(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expected, logger.Levels())
+
+			if tt.expectedStatus == http.StatusOK {
+				var got logger.LevelSettings
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogLevelDelivery_GetLogLevels(t *testing.T) {
+	delivery := CreateLogLevelDelivery()
+
+	previous := logger.Levels()
+	defer logger.SetLevels(previous)
+	require.NoError(t, logger.SetLevels(logger.LevelSettings{Global: "warn", Components: map[string]string{"Repository.*": "error"}}))
+
+	w := httptest.NewRecorder()
+	delivery.GetLogLevels(w, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"global":"WARN","components":{"Repository.*":"ERROR"}}`, w.Body.String())
+}
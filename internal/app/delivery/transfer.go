@@ -0,0 +1,240 @@
+package delivery
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/backup"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+	"github.com/supchaser/LO_test_task/internal/utils/taskcodec"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+// ExportTasks streams all tasks, optionally filtered by status, in any of the
+// taskcodec formats. The format comes from the format query parameter or, without
+// it, from the Accept header.
+func (d *TaskDelivery) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.ExportTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	format, status, err := exportFormat(r)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "unsupported export format", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, err.Error(), status)
+		return
+	}
+
+	statusFilter := models.TaskStatus(r.URL.Query().Get("status"))
+	total, err := d.taskUsecase.CountTasks(ctx, statusFilter)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to count tasks", err, map[string]any{
+			"method": funcName,
+			"status": statusFilter,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(total))
+
+	count, err := writeTasks(w, format, d.taskUsecase.ListTasks(ctx, statusFilter, 0, 0))
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to write export", err, map[string]any{
+			"method": funcName,
+		})
+		return
+	}
+
+	logger.InfoContext(ctx, "tasks exported", map[string]any{
+		"method": funcName,
+		"format": format,
+		"count":  count,
+	})
+}
+
+// exportFormat also returns the status to answer with if the format cannot be
+// served.
+func exportFormat(r *http.Request) (taskcodec.Format, int, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := taskcodec.ParseFormat(name)
+		return format, http.StatusBadRequest, err
+	}
+
+	format, err := taskcodec.FormatForAccept(r.Header.Get("Accept"))
+	return format, http.StatusNotAcceptable, err
+}
+
+// ImportTasks reads tasks in any export format, chosen by the format query
+// parameter or the Content-Type header. Records that cannot be decoded are
+// reported per row next to the results of the import itself.
+func (d *TaskDelivery) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.ImportTasks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	format, status, err := importFormat(r)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "unsupported import format", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, err.Error(), status)
+		return
+	}
+
+	opts, err := parseImportOptions(r)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid import options", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var items []models.ImportItem
+	var failed []models.ImportItemResult
+	decoder := taskcodec.CreateDecoder(r.Body, format)
+	for {
+		record, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			span.RecordError(err)
+			logger.ErrorContext(ctx, "failed to read import", err, map[string]any{
+				"method": funcName,
+				"format": format,
+			})
+			respondWithReadError(w, r, err)
+			return
+		}
+
+		if record.Err != nil {
+			failed = append(failed, models.ImportItemResult{
+				Row:    record.Row,
+				ID:     record.Task.ID,
+				Action: models.ImportFailed,
+				Err:    record.Err,
+			})
+			continue
+		}
+		items = append(items, models.ImportItem{Row: record.Row, Task: record.Task})
+	}
+
+	results, err := d.taskUsecase.ImportTasks(ctx, items, opts)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to import tasks", err, map[string]any{
+			"method": funcName,
+			"count":  len(items),
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	results = append(results, failed...)
+	slices.SortFunc(results, func(a, b models.ImportItemResult) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+
+	resp := models.ImportResponse{
+		DryRun:  opts.DryRun,
+		Results: make([]models.ImportItemResponse, len(results)),
+	}
+	for i, result := range results {
+		item := models.ImportItemResponse{Row: result.Row, ID: result.ID, Action: result.Action}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+			if errorStatus(result.Err) == http.StatusInternalServerError {
+				item.Error = "internal server error"
+			}
+		}
+		resp.Results[i] = item
+
+		switch result.Action {
+		case models.ImportCreated:
+			resp.Created++
+		case models.ImportUpdated:
+			resp.Updated++
+		case models.ImportSkipped:
+			resp.Skipped++
+		case models.ImportFailed:
+			resp.Failed++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
+			"method": funcName,
+		})
+	}
+}
+
+func importFormat(r *http.Request) (taskcodec.Format, int, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := taskcodec.ParseFormat(name)
+		return format, http.StatusBadRequest, err
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return taskcodec.FormatJSON, 0, nil
+	}
+	format, err := taskcodec.FormatForMediaType(contentType)
+	return format, http.StatusUnsupportedMediaType, err
+}
+
+func parseImportOptions(r *http.Request) (models.ImportOptions, error) {
+	query := r.URL.Query()
+	opts := models.ImportOptions{OnConflict: models.ImportConflictPolicy(query.Get("on_conflict"))}
+
+	switch opts.OnConflict {
+	case "":
+		opts.OnConflict = models.ImportSkip
+	case models.ImportSkip, models.ImportUpsert:
+	default:
+		return opts, fmt.Errorf("invalid on_conflict %q: must be %s or %s", opts.OnConflict, models.ImportSkip, models.ImportUpsert)
+	}
+
+	if value := query.Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid dry_run %q: must be true or false", value)
+		}
+		opts.DryRun = dryRun
+	}
+
+	return opts, nil
+}
+
+func respondWithReadError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		respond.Error(w, r, "request body too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, taskcodec.ErrMalformed), errors.Is(err, backup.ErrInvalid), errors.Is(err, backup.ErrChecksumMismatch):
+		respond.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		respond.Error(w, r, "failed to read request body", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,289 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestTaskDelivery_ExportTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	tasks := []*models.Task{
+		{ID: 1, Title: "First task", Status: models.StatusPending},
+		{ID: 2, Title: "Second task", Status: models.StatusPending},
+	}
+
+	tests := []struct {
+		name                string
+		url                 string
+		accept              string
+		mockSetup           func()
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:   "CSV By Accept",
+			url:    "/tasks/export?status=pending",
+			accept: "text/csv",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.StatusPending).Return(len(tasks), nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.StatusPending, 0, 0).Return(taskSeq(tasks))
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,title,description,status,created_at,updated_at\n1,First task,,pending,,\n2,Second task,,pending,,\n",
+		},
+		{
+			name:   "NDJSON By Query",
+			url:    "/tasks/export?format=ndjson",
+			accept: "text/csv",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(1, nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), 0, 0).Return(taskSeq(tasks[:1]))
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"id":1,"title":"First task","description":"","status":"pending","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name: "JSON By Default",
+			url:  "/tasks/export",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(0, nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), 0, 0).Return(taskSeq(nil))
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        "[]\n",
+		},
+		{
+			name:   "XML By Accept",
+			url:    "/tasks/export",
+			accept: "application/xml",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(1, nil)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), models.TaskStatus(""), 0, 0).Return(taskSeq(tasks[1:]))
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n<tasks>\n" +
+				"<task><id>2</id><title>Second task</title><description></description><status>pending</status><created_at></created_at><updated_at></updated_at></task>\n</tasks>\n",
+		},
+		{
+			name:           "Unknown Format",
+			url:            "/tasks/export?format=yaml",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not Acceptable",
+			url:            "/tasks/export",
+			accept:         "text/html",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name: "Usecase Error",
+			url:  "/tasks/export",
+			mockSetup: func() {
+				mockUsecase.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			delivery.ExportTasks(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskDelivery_ImportTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockTaskUsecase(ctrl)
+	delivery := CreateTaskDelivery(mockUsecase)
+
+	tests := []struct {
+		name            string
+		url             string
+		contentType     string
+		body            string
+		mockSetup       func()
+		expectedStatus  int
+		expectedActions []models.ImportAction
+		expectedDryRun  bool
+	}{
+		{
+			name:        "CSV With Decode Errors",
+			url:         "/tasks/import?on_conflict=upsert&dry_run=true",
+			contentType: "text/csv",
+			body:        "id,title\n1,First task\nabc,Bad id\n,New task\n",
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					ImportTasks(gomock.Any(), []models.ImportItem{
+						{Row: 1, Task: models.Task{ID: 1, Title: "First task"}},
+						{Row: 3, Task: models.Task{Title: "New task"}},
+					}, models.ImportOptions{OnConflict: models.ImportUpsert, DryRun: true}).
+					Return([]models.ImportItemResult{
+						{Row: 1, ID: 1, Action: models.ImportUpdated},
+						{Row: 3, Action: models.ImportCreated},
+					}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedActions: []models.ImportAction{models.ImportUpdated, models.ImportFailed, models.ImportCreated},
+			expectedDryRun:  true,
+		},
+		{
+			name: "JSON By Default",
+			url:  "/tasks/import",
+			body: `[{"id":1,"title":"First task"},{"id":2,"title":"x"}]`,
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					ImportTasks(gomock.Any(), gomock.Len(2), models.ImportOptions{OnConflict: models.ImportSkip}).
+					Return([]models.ImportItemResult{
+						{Row: 1, ID: 1, Action: models.ImportSkipped},
+						{Row: 2, ID: 2, Action: models.ImportFailed, Err: errs.ErrValidation},
+					}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedActions: []models.ImportAction{models.ImportSkipped, models.ImportFailed},
+		},
+		{
+			name:        "NDJSON By Query",
+			url:         "/tasks/import?format=ndjson",
+			contentType: "text/plain",
+			body:        "{\"title\":\"First task\"}\n",
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					ImportTasks(gomock.Any(), gomock.Len(1), gomock.Any()).
+					Return([]models.ImportItemResult{{Row: 1, ID: 5, Action: models.ImportCreated}}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedActions: []models.ImportAction{models.ImportCreated},
+		},
+		{
+			name:           "Unsupported Media Type",
+			url:            "/tasks/import",
+			contentType:    "text/plain",
+			body:           "title\n",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "Invalid Conflict Policy",
+			url:            "/tasks/import?on_conflict=merge",
+			body:           "[]",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Dry Run",
+			url:            "/tasks/import?dry_run=maybe",
+			body:           "[]",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed File",
+			url:            "/tasks/import",
+			body:           `[{"title":"First task"},`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Usecase Error",
+			url:  "/tasks/import",
+			body: "[]",
+			mockSetup: func() {
+				mockUsecase.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			delivery.ImportTasks(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp models.ImportResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedDryRun, resp.DryRun)
+
+			var actions []models.ImportAction
+			failed := 0
+			for i, result := range resp.Results {
+				assert.Equal(t, i+1, result.Row)
+				actions = append(actions, result.Action)
+				if result.Action == models.ImportFailed {
+					failed++
+					assert.NotEmpty(t, result.Error)
+				}
+			}
+			assert.Equal(t, tt.expectedActions, actions)
+			assert.Equal(t, failed, resp.Failed)
+		})
+	}
+}
+
+func TestTaskDelivery_ImportTasksBodyTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delivery := CreateTaskDelivery(mock_app.NewMockTaskUsecase(ctrl))
+	handler := http.MaxBytesHandler(http.HandlerFunc(delivery.ImportTasks), 64)
+
+	body := "[" + strings.Repeat(`{"title":"Some task"},`, 10) + `{"title":"Last task"}]`
+	req := httptest.NewRequest(http.MethodPost, "/tasks/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,229 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+type WebhookDelivery struct {
+	webhookUsecase app.WebhookUsecase
+}
+
+func CreateWebhookDelivery(webhookUsecase app.WebhookUsecase) *WebhookDelivery {
+	return &WebhookDelivery{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+func (d *WebhookDelivery) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.CreateWebhook"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	var req models.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := d.webhookUsecase.CreateWebhook(ctx, req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to create webhook", err, map[string]any{
+			"method": funcName,
+			"url":    req.URL,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to encode response", err, map[string]any{
+			"method":     funcName,
+			"webhook_id": webhook.ID,
+		})
+	}
+}
+
+func (d *WebhookDelivery) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.GetWebhook"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	id, ok := parseWebhookID(w, r, funcName)
+	if !ok {
+		return
+	}
+
+	webhook, err := d.webhookUsecase.GetWebhook(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to get webhook", err, map[string]any{
+			"method": funcName,
+			"id":     id,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+func (d *WebhookDelivery) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.ListWebhooks"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	webhooks, err := d.webhookUsecase.ListWebhooks(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list webhooks", err, map[string]any{
+			"method": funcName,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+func (d *WebhookDelivery) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.UpdateWebhook"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	id, ok := parseWebhookID(w, r, funcName)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to decode request", err, map[string]any{
+			"method": funcName,
+			"id":     id,
+		})
+		respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := d.webhookUsecase.UpdateWebhook(ctx, id, req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update webhook", err, map[string]any{
+			"method": funcName,
+			"id":     id,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+func (d *WebhookDelivery) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.DeleteWebhook"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	id, ok := parseWebhookID(w, r, funcName)
+	if !ok {
+		return
+	}
+
+	if err := d.webhookUsecase.DeleteWebhook(ctx, id); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to delete webhook", err, map[string]any{
+			"method": funcName,
+			"id":     id,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *WebhookDelivery) ListDeliveryLogs(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.ListDeliveryLogs"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	id, ok := parseWebhookID(w, r, funcName)
+	if !ok {
+		return
+	}
+
+	logs, err := d.webhookUsecase.ListDeliveryLogs(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list delivery logs", err, map[string]any{
+			"method": funcName,
+			"id":     id,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
+
+func (d *WebhookDelivery) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.ListDeadLetters"
+
+	ctx, span := tracing.Start(r.Context(), funcName)
+	defer span.End()
+
+	letters, err := d.webhookUsecase.ListDeadLetters(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list dead letters", err, map[string]any{
+			"method": funcName,
+		})
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(letters)
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request, funcName string) (int64, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "invalid webhook ID", err, map[string]any{
+			"method": funcName,
+			"id":     idStr,
+		})
+		respond.Error(w, r, "invalid webhook ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,132 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestWebhookDelivery_CreateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockWebhookUsecase(ctrl)
+	delivery := CreateWebhookDelivery(mockUsecase)
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			requestBody: models.CreateWebhookRequest{
+				URL:        "https://example.com/hook",
+				EventTypes: []models.TaskEventType{models.EventTaskCreated},
+				Secret:     "0123456789abcdef",
+			},
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					CreateWebhook(gomock.Any(), "https://example.com/hook", []models.TaskEventType{models.EventTaskCreated}, "0123456789abcdef").
+					Return(&models.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "0123456789abcdef"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid Body",
+			requestBody:    "invalid",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Validation Error",
+			requestBody: models.CreateWebhookRequest{URL: "bad"},
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					CreateWebhook(gomock.Any(), "bad", gomock.Any(), "").
+					Return(nil, errs.ErrValidation)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			delivery.CreateWebhook(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "0123456789abcdef")
+		})
+	}
+}
+
+func TestWebhookDelivery_ListDeliveryLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mock_app.NewMockWebhookUsecase(ctrl)
+	delivery := CreateWebhookDelivery(mockUsecase)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					ListDeliveryLogs(gomock.Any(), int64(1)).
+					Return([]models.WebhookDeliveryLog{{WebhookID: 1, Attempt: 1, Success: true}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid ID",
+			id:             "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not Found",
+			id:   "2",
+			mockSetup: func() {
+				mockUsecase.EXPECT().
+					ListDeliveryLogs(gomock.Any(), int64(2)).
+					Return(nil, errs.ErrWebhookNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest("GET", "/webhooks/"+tt.id+"/deliveries", nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			delivery.ListDeliveryLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/websocket"
+)
+
+const (
+	wsPingInterval        = 30 * time.Second
+	wsMaxMessageSize      = 64 << 10
+	wsMaxSubscriptions    = 100
+	wsActionSubscribe     = "subscribe"
+	wsActionUnsubscribe   = "unsubscribe"
+	wsMessageSubscribed   = "subscribed"
+	wsMessageUnsubscribed = "unsubscribed"
+	wsMessageClosed       = "subscription_closed"
+	wsMessageEvent        = "event"
+	wsMessageError        = "error"
+)
+
+type wsSession struct {
+	conn          *websocket.Conn
+	eventStream   app.EventStream
+	subscriptions map[string]app.EventSubscription
+	forwarders    sync.WaitGroup
+	mu            sync.Mutex
+}
+
+func (d *EventDelivery) SubscribeWebSocket(w http.ResponseWriter, r *http.Request) {
+	const funcName = "Delivery.SubscribeWebSocket"
+
+	conn, err := websocket.Upgrade(w, r, d.allowedOrigins)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "failed to upgrade connection", err, map[string]any{
+			"method": funcName,
+		})
+		return
+	}
+	conn.SetMaxMessageSize(wsMaxMessageSize)
+
+	session := &wsSession{
+		conn:          conn,
+		eventStream:   d.eventStream,
+		subscriptions: make(map[string]app.EventSubscription),
+	}
+
+	logger.InfoContext(r.Context(), "websocket connection opened", map[string]any{
+		"method":      funcName,
+		"remote_addr": conn.RemoteAddr().String(),
+	})
+
+	done := make(chan struct{})
+	go d.keepAlive(conn, done)
+
+	err = session.readLoop(d.pingInterval)
+	close(done)
+	// Closing the connection first fails any send a forwarder is stuck in,
+	// so closeAll does not wait on a client that stopped reading.
+	conn.Close(websocket.CloseNormalClosure, "")
+	session.closeAll()
+
+	fields := map[string]any{
+		"method": funcName,
+	}
+	var closeErr *websocket.CloseError
+	if err != nil && !errors.As(err, &closeErr) {
+		fields["error"] = err.Error()
+	}
+	logger.InfoContext(r.Context(), "websocket connection closed", fields)
+}
+
+func (d *EventDelivery) keepAlive(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(d.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-d.shutdown:
+			conn.Close(websocket.CloseGoingAway, "server shutting down")
+			return
+		case <-ticker.C:
+			if err := conn.Ping(nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) readLoop(pingInterval time.Duration) error {
+	pongWait := 2 * pingInterval
+	s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func([]byte) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		s.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		if messageType != websocket.TextMessage {
+			s.send(models.WebSocketMessage{Type: wsMessageError, Error: "only text messages are supported"})
+			continue
+		}
+
+		var req models.WebSocketRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.send(models.WebSocketMessage{Type: wsMessageError, Error: "invalid message format"})
+			continue
+		}
+
+		if err := s.handle(req); err != nil {
+			s.send(models.WebSocketMessage{Type: wsMessageError, ID: req.ID, Error: err.Error()})
+		}
+	}
+}
+
+func (s *wsSession) handle(req models.WebSocketRequest) error {
+	if req.ID == "" {
+		return errors.New("subscription id is required")
+	}
+
+	switch req.Action {
+	case wsActionSubscribe:
+		return s.subscribe(req)
+	case wsActionUnsubscribe:
+		return s.unsubscribe(req.ID)
+	default:
+		return fmt.Errorf("unknown action %q", req.Action)
+	}
+}
+
+func (s *wsSession) subscribe(req models.WebSocketRequest) error {
+	s.mu.Lock()
+	if _, exists := s.subscriptions[req.ID]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("subscription %q already exists", req.ID)
+	}
+	if len(s.subscriptions) >= wsMaxSubscriptions {
+		s.mu.Unlock()
+		return fmt.Errorf("too many subscriptions, limit is %d", wsMaxSubscriptions)
+	}
+
+	filter := models.EventFilter{
+		Status:  req.Status,
+		TaskIDs: req.TaskIDs,
+	}
+	sub, err := s.eventStream.Subscribe(filter, req.LastEventID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.subscriptions[req.ID] = sub
+	s.forwarders.Add(1)
+	s.mu.Unlock()
+
+	// Sent without the lock, so a slow write does not hold up the forwarders
+	// and closeAll, and before forwarding starts, so the confirmation comes
+	// before any event.
+	s.send(models.WebSocketMessage{Type: wsMessageSubscribed, ID: req.ID})
+	go s.forward(req.ID, sub)
+
+	return nil
+}
+
+func (s *wsSession) unsubscribe(id string) error {
+	s.mu.Lock()
+	sub, exists := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	s.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("subscription %q not found", id)
+	}
+
+	sub.Close()
+	s.send(models.WebSocketMessage{Type: wsMessageUnsubscribed, ID: id})
+
+	return nil
+}
+
+func (s *wsSession) forward(id string, sub app.EventSubscription) {
+	defer s.forwarders.Done()
+
+	for event := range sub.Events() {
+		if err := s.send(models.WebSocketMessage{Type: wsMessageEvent, ID: id, Event: &event}); err != nil {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	_, active := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	s.mu.Unlock()
+
+	if active {
+		s.send(models.WebSocketMessage{Type: wsMessageClosed, ID: id})
+	}
+}
+
+func (s *wsSession) send(msg models.WebSocketMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (s *wsSession) closeAll() {
+	s.mu.Lock()
+	subscriptions := s.subscriptions
+	s.subscriptions = make(map[string]app.EventSubscription)
+	s.mu.Unlock()
+
+	for _, sub := range subscriptions {
+		sub.Close()
+	}
+	s.forwarders.Wait()
+}
@@ -0,0 +1,126 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/websocket"
+)
+
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) models.WebSocketMessage {
+	t.Helper()
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var msg models.WebSocketMessage
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+func sendWebSocketRequest(t *testing.T, conn *websocket.Conn, req models.WebSocketRequest) {
+	t.Helper()
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, data))
+}
+
+func TestEventDelivery_SubscribeWebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStream := mock_app.NewMockEventStream(ctrl)
+	mockSub := mock_app.NewMockEventSubscription(ctrl)
+	delivery := CreateEventDelivery(mockStream, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(delivery.SubscribeWebSocket))
+	defer server.Close()
+
+	events := make(chan models.TaskEvent, 1)
+	mockStream.EXPECT().
+		Subscribe(models.EventFilter{TaskIDs: []int64{1}}, uint64(3)).
+		Return(mockSub, nil)
+	mockSub.EXPECT().Events().Return(events).AnyTimes()
+	mockSub.EXPECT().Close().Do(func() { close(events) })
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close(websocket.CloseNormalClosure, "")
+
+	sendWebSocketRequest(t, conn, models.WebSocketRequest{Action: "subscribe", ID: "s1", TaskIDs: []int64{1}, LastEventID: 3})
+	msg := readWebSocketMessage(t, conn)
+	assert.Equal(t, "subscribed", msg.Type)
+	assert.Equal(t, "s1", msg.ID)
+
+	events <- models.TaskEvent{ID: 4, Type: models.EventTaskUpdated, TaskID: 1}
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, "event", msg.Type)
+	assert.Equal(t, "s1", msg.ID)
+	assert.Equal(t, uint64(4), msg.Event.ID)
+
+	sendWebSocketRequest(t, conn, models.WebSocketRequest{Action: "subscribe", ID: "s1"})
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, "error", msg.Type)
+
+	sendWebSocketRequest(t, conn, models.WebSocketRequest{Action: "unsubscribe", ID: "s1"})
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, "unsubscribed", msg.Type)
+
+	sendWebSocketRequest(t, conn, models.WebSocketRequest{Action: "explode", ID: "s2"})
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Contains(t, msg.Error, "unknown action")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, "invalid message format", msg.Error)
+
+	delivery.Shutdown()
+
+	var closeErr *websocket.CloseError
+	_, _, err = conn.ReadMessage()
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, websocket.CloseGoingAway, closeErr.Code)
+}
+
+func TestEventDelivery_SubscribeWebSocketRequiresUpgrade(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delivery := CreateEventDelivery(mock_app.NewMockEventStream(ctrl), nil)
+
+	req := httptest.NewRequest("GET", "/tasks/ws", nil)
+	w := httptest.NewRecorder()
+
+	delivery.SubscribeWebSocket(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEventDelivery_SubscribeWebSocketChecksOrigin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delivery := CreateEventDelivery(mock_app.NewMockEventStream(ctrl), []string{"https://app.example"})
+	server := httptest.NewServer(http.HandlerFunc(delivery.SubscribeWebSocket))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, resp, err := websocket.Dial(context.Background(), url, http.Header{"Origin": {"https://evil.example"}})
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, _, err := websocket.Dial(context.Background(), url, http.Header{"Origin": {"https://app.example"}})
+	require.NoError(t, err)
+	conn.Close(websocket.CloseNormalClosure, "")
+}
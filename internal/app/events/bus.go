@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+const subscriberBufferSize = 64
+
+type Bus struct {
+	replay      []models.TaskEvent
+	replaySize  int
+	lastID      uint64
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+func CreateBus(replaySize int) *Bus {
+	return &Bus{
+		replaySize:  replaySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *Bus) Deliver(ctx context.Context, event models.TaskEvent) error {
+	const funcName = "EventBus.Deliver"
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	if event.ID == 0 {
+		event.ID = b.lastID + 1
+	}
+	if event.ID <= b.lastID {
+		// Redelivery from the outbox relay; subscribers already have it.
+		return nil
+	}
+	b.lastID = event.ID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.replay = append(b.replay, event)
+	if len(b.replay) > b.replaySize {
+		b.replay = b.replay[len(b.replay)-b.replaySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			logger.Warn("dropping slow event subscriber", map[string]any{
+				"method":   funcName,
+				"event_id": event.ID,
+			})
+			b.detach(sub)
+		}
+	}
+
+	return nil
+}
+
+func (b *Bus) Subscribe(filter models.EventFilter, lastEventID uint64) (app.EventSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errs.ErrStreamClosed
+	}
+
+	var backlog []models.TaskEvent
+	if lastEventID > 0 {
+		for _, event := range b.replay {
+			if event.ID > lastEventID && filter.Matches(event) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		bus:    b,
+		filter: filter,
+		events: make(chan models.TaskEvent, len(backlog)+subscriberBufferSize),
+	}
+	for _, event := range backlog {
+		sub.events <- event
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for sub := range b.subscribers {
+		b.detach(sub)
+	}
+}
+
+func (b *Bus) detach(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+type Subscription struct {
+	bus    *Bus
+	filter models.EventFilter
+	events chan models.TaskEvent
+}
+
+func (s *Subscription) Events() <-chan models.TaskEvent {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.detach(s)
+}
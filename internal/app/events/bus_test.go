@@ -0,0 +1,122 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func receive(t *testing.T, sub app.EventSubscription) models.TaskEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed unexpectedly")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return models.TaskEvent{}
+	}
+}
+
+func TestBus_DeliverAssignsSequentialIDs(t *testing.T) {
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{}, 0)
+	require.NoError(t, err)
+
+	bus.Deliver(context.Background(), models.TaskEvent{Type: models.EventTaskCreated, TaskID: 1})
+	bus.Deliver(context.Background(), models.TaskEvent{Type: models.EventTaskDeleted, TaskID: 1})
+
+	first := receive(t, sub)
+	second := receive(t, sub)
+
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, models.EventTaskCreated, first.Type)
+	assert.False(t, first.OccurredAt.IsZero())
+	assert.Equal(t, uint64(2), second.ID)
+}
+
+func TestBus_Filters(t *testing.T) {
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{Status: models.StatusCompleted}, 0)
+	require.NoError(t, err)
+
+	bus.Deliver(context.Background(), models.TaskEvent{TaskID: 1, Task: &models.Task{ID: 1, Status: models.StatusPending}})
+	bus.Deliver(context.Background(), models.TaskEvent{TaskID: 2, Task: &models.Task{ID: 2, Status: models.StatusCompleted}})
+	bus.Deliver(context.Background(), models.TaskEvent{Type: models.EventTaskDeleted, TaskID: 3})
+
+	assert.Equal(t, int64(2), receive(t, sub).TaskID)
+	assert.Equal(t, int64(3), receive(t, sub).TaskID)
+	assert.Len(t, sub.Events(), 0)
+}
+
+func TestBus_ReplaysFromLastEventID(t *testing.T) {
+	bus := CreateBus(3)
+	for i := range 5 {
+		bus.Deliver(context.Background(), models.TaskEvent{TaskID: int64(i + 1)})
+	}
+
+	sub, err := bus.Subscribe(models.EventFilter{}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(3), receive(t, sub).ID)
+	assert.Equal(t, uint64(4), receive(t, sub).ID)
+	assert.Equal(t, uint64(5), receive(t, sub).ID)
+
+	bus.Deliver(context.Background(), models.TaskEvent{TaskID: 6})
+	assert.Equal(t, uint64(6), receive(t, sub).ID)
+}
+
+func TestBus_SlowSubscriberIsDropped(t *testing.T) {
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{}, 0)
+	require.NoError(t, err)
+
+	for range subscriberBufferSize + 1 {
+		bus.Deliver(context.Background(), models.TaskEvent{TaskID: 1})
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBufferSize, received)
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{}, 0)
+	require.NoError(t, err)
+
+	bus.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+
+	_, err = bus.Subscribe(models.EventFilter{}, 0)
+	assert.ErrorIs(t, err, errs.ErrStreamClosed)
+
+	sub.Close()
+	bus.Deliver(context.Background(), models.TaskEvent{TaskID: 1})
+}
+
+func TestBus_IgnoresRedeliveredEvents(t *testing.T) {
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Deliver(context.Background(), models.TaskEvent{ID: 10}))
+	require.NoError(t, bus.Deliver(context.Background(), models.TaskEvent{ID: 4}))
+	require.NoError(t, bus.Deliver(context.Background(), models.TaskEvent{ID: 10}))
+	require.NoError(t, bus.Deliver(context.Background(), models.TaskEvent{ID: 11}))
+
+	assert.Equal(t, uint64(10), receive(t, sub).ID)
+	assert.Equal(t, uint64(11), receive(t, sub).ID)
+	assert.Len(t, sub.Events(), 0)
+}
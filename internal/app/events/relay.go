@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+const relayBatchSize = 100
+
+type relaySink struct {
+	name      string
+	sink      app.EventSink
+	delivered uint64
+}
+
+// Relay moves committed events from the outbox to the sinks. An event is
+// acknowledged only after every sink accepted it, so delivery is at least
+// once: a failing sink gets the event again on the next pass, while sinks that
+// already accepted it are skipped for as long as the process runs.
+type Relay struct {
+	outbox       app.OutboxRepository
+	sinks        []*relaySink
+	pollInterval time.Duration
+	stop         chan struct{}
+	done         chan struct{}
+	stopOnce     sync.Once
+}
+
+func CreateRelay(outbox app.OutboxRepository, pollInterval time.Duration) *Relay {
+	return &Relay{
+		outbox:       outbox,
+		pollInterval: pollInterval,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// AddSink registers a sink; all sinks must be added before Start.
+func (r *Relay) AddSink(name string, sink app.EventSink) {
+	r.sinks = append(r.sinks, &relaySink{name: name, sink: sink})
+}
+
+func (r *Relay) Start() {
+	go r.run()
+}
+
+func (r *Relay) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.relayPending()
+
+		select {
+		case <-r.stop:
+			r.relayPending()
+			return
+		case <-r.outbox.EventsAvailable():
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayPending() {
+	const funcName = "EventRelay.relayPending"
+
+	ctx := context.Background()
+	for {
+		events, err := r.outbox.GetPendingEvents(ctx, relayBatchSize)
+		if err != nil {
+			logger.Error("failed to read outbox", err, map[string]any{
+				"method": funcName,
+			})
+			return
+		}
+
+		for _, event := range events {
+			for _, s := range r.sinks {
+				if s.delivered >= event.ID {
+					continue
+				}
+				if err := s.sink.Deliver(ctx, event); err != nil {
+					logger.Warn("event sink failed, will retry", map[string]any{
+						"method":   funcName,
+						"sink":     s.name,
+						"event_id": event.ID,
+						"error":    err.Error(),
+					})
+					return
+				}
+				s.delivered = event.ID
+			}
+
+			if err := r.outbox.AckEvents(ctx, event.ID); err != nil {
+				logger.Error("failed to acknowledge event", err, map[string]any{
+					"method":   funcName,
+					"event_id": event.ID,
+				})
+				return
+			}
+		}
+
+		if len(events) < relayBatchSize {
+			return
+		}
+	}
+}
+
+// Close makes a final pass over the outbox and stops the relay.
+func (r *Relay) Close(ctx context.Context) error {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
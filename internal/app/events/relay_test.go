@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+)
+
+type recordingSink struct {
+	failures int
+	events   []models.TaskEvent
+	mu       sync.Mutex
+}
+
+func (s *recordingSink) Deliver(ctx context.Context, event models.TaskEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) received() []models.TaskEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.TaskEvent(nil), s.events...)
+}
+
+func commitEvent(t *testing.T, repo *repository.TaskRepository, taskID int64) {
+	t.Helper()
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		return tx.AddEvent(context.Background(), models.TaskEvent{Type: models.EventTaskDeleted, TaskID: taskID})
+	})
+	require.NoError(t, err)
+}
+
+func TestRelay_DeliversCommittedEventsInOrder(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	sink := &recordingSink{}
+
+	relay := CreateRelay(repo, time.Hour)
+	relay.AddSink("recording", sink)
+	relay.Start()
+
+	commitEvent(t, repo, 1)
+	commitEvent(t, repo, 2)
+
+	require.Eventually(t, func() bool {
+		return len(sink.received()) == 2
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, relay.Close(context.Background()))
+
+	events := sink.received()
+	assert.Equal(t, uint64(1), events[0].ID)
+	assert.Equal(t, uint64(2), events[1].ID)
+
+	pending, err := repo.GetPendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelay_RetriesFailedSinkWithoutAcking(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	healthy := &recordingSink{}
+	flaky := &recordingSink{failures: 2}
+
+	relay := CreateRelay(repo, 10*time.Millisecond)
+	relay.AddSink("healthy", healthy)
+	relay.AddSink("flaky", flaky)
+	relay.Start()
+
+	commitEvent(t, repo, 1)
+
+	require.Eventually(t, func() bool {
+		return len(flaky.received()) == 1
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, relay.Close(context.Background()))
+
+	assert.Len(t, healthy.received(), 1, "sinks that accepted an event are not redelivered to")
+
+	pending, err := repo.GetPendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelay_CloseLeavesUndeliveredEventsInOutbox(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	broken := &recordingSink{failures: 1 << 30}
+
+	relay := CreateRelay(repo, time.Hour)
+	relay.AddSink("broken", broken)
+	relay.Start()
+
+	commitEvent(t, repo, 1)
+	require.NoError(t, relay.Close(context.Background()))
+
+	pending, err := repo.GetPendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestRelay_BusSink(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	bus := CreateBus(10)
+	sub, err := bus.Subscribe(models.EventFilter{}, 0)
+	require.NoError(t, err)
+
+	relay := CreateRelay(repo, time.Hour)
+	relay.AddSink("event_bus", bus)
+	relay.Start()
+	defer relay.Close(context.Background())
+
+	commitEvent(t, repo, 7)
+
+	event := receive(t, sub)
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, int64(7), event.TaskID)
+}
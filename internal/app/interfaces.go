@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"iter"
 
 	"github.com/supchaser/LO_test_task/internal/app/models"
 )
@@ -9,17 +10,86 @@ import (
 //go:generate mockgen -source=interfaces.go -destination=mocks/mock.go
 
 type TaskRepository interface {
+	CreateTask(ctx context.Context, task *models.Task) (*models.Task, error)
+	GetTaskByID(ctx context.Context, id int64) (*models.Task, error)
+	// GetTasksPage returns up to limit tasks with IDs above afterID in ID
+	// order, after passing over the first skip of them.
+	GetTasksPage(ctx context.Context, statusFilter models.TaskStatus, afterID int64, skip, limit int) ([]*models.Task, error)
+	CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error)
+	UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error)
+	DeleteTask(ctx context.Context, id int64) error
+	WithTx(ctx context.Context, fn func(tx TaskTx) error) error
+	Snapshot(ctx context.Context) (*models.Snapshot, error)
+	// Restore fails with errs.ErrStoreNotEmpty unless the store has no tasks.
+	Restore(ctx context.Context, snapshot *models.Snapshot) error
+}
+
+type TaskTx interface {
 	CreateTask(ctx context.Context, task *models.Task) (*models.Task, error)
 	GetTaskByID(ctx context.Context, id int64) (*models.Task, error)
 	GetAllTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error)
 	UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error)
 	DeleteTask(ctx context.Context, id int64) error
+	// RestoreTask stores the task as given, replacing any task with the same
+	// ID and keeping its timestamps.
+	RestoreTask(ctx context.Context, task *models.Task) (*models.Task, error)
+	AddEvent(ctx context.Context, event models.TaskEvent) error
+}
+
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]models.TaskEvent, error)
+	AckEvents(ctx context.Context, upToID uint64) error
+	EventsAvailable() <-chan struct{}
 }
 
 type TaskUsecase interface {
 	CreateTask(ctx context.Context, title, description string) (*models.Task, error)
 	GetTask(ctx context.Context, id int64) (*models.Task, error)
-	ListTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error)
+	// ListTasks yields the tasks in ID order, reading them from the repository
+	// page by page as the sequence is consumed; a zero limit means no limit.
+	ListTasks(ctx context.Context, statusFilter models.TaskStatus, offset, limit int) iter.Seq2[*models.Task, error]
+	CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error)
 	UpdateTask(ctx context.Context, id int64, newTitle, newDescription string, status models.TaskStatus) (*models.Task, error)
 	DeleteTask(ctx context.Context, id int64) error
+	BatchCreateTasks(ctx context.Context, items []models.CreateTaskRequest, atomic bool) ([]models.BatchItemResult, error)
+	BatchUpdateTasks(ctx context.Context, items []models.BatchUpdateItem, atomic bool) ([]models.BatchItemResult, error)
+	BatchDeleteTasks(ctx context.Context, ids []int64, atomic bool) ([]models.BatchItemResult, error)
+	ImportTasks(ctx context.Context, items []models.ImportItem, opts models.ImportOptions) ([]models.ImportItemResult, error)
+	Snapshot(ctx context.Context) (*models.Snapshot, error)
+	RestoreSnapshot(ctx context.Context, snapshot *models.Snapshot) error
+}
+
+type EventSink interface {
+	Deliver(ctx context.Context, event models.TaskEvent) error
+}
+
+type EventStream interface {
+	Subscribe(filter models.EventFilter, lastEventID uint64) (EventSubscription, error)
+}
+
+type EventSubscription interface {
+	Events() <-chan models.TaskEvent
+	Close()
+}
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error)
+	GetAllWebhooks(ctx context.Context) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	AddDeliveryLog(ctx context.Context, log models.WebhookDeliveryLog) error
+	GetDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error)
+	AddDeadLetter(ctx context.Context, letter models.DeadLetter) error
+	GetDeadLetters(ctx context.Context) ([]models.DeadLetter, error)
+}
+
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error)
+	ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error)
 }
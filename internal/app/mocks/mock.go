@@ -6,9 +6,11 @@ package mock_app
 
 import (
 	context "context"
+	iter "iter"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	app "github.com/supchaser/LO_test_task/internal/app"
 	models "github.com/supchaser/LO_test_task/internal/app/models"
 )
 
@@ -35,6 +37,21 @@ func (m *MockTaskRepository) EXPECT() *MockTaskRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountTasks mocks base method.
+func (m *MockTaskRepository) CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasks", ctx, statusFilter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasks indicates an expected call of CountTasks.
+func (mr *MockTaskRepositoryMockRecorder) CountTasks(ctx, statusFilter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasks", reflect.TypeOf((*MockTaskRepository)(nil).CountTasks), ctx, statusFilter)
+}
+
 // CreateTask mocks base method.
 func (m *MockTaskRepository) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -64,8 +81,162 @@ func (mr *MockTaskRepositoryMockRecorder) DeleteTask(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskRepository)(nil).DeleteTask), ctx, id)
 }
 
+// GetTaskByID mocks base method.
+func (m *MockTaskRepository) GetTaskByID(ctx context.Context, id int64) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskByID indicates an expected call of GetTaskByID.
+func (mr *MockTaskRepositoryMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskRepository)(nil).GetTaskByID), ctx, id)
+}
+
+// GetTasksPage mocks base method.
+func (m *MockTaskRepository) GetTasksPage(ctx context.Context, statusFilter models.TaskStatus, afterID int64, skip, limit int) ([]*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksPage", ctx, statusFilter, afterID, skip, limit)
+	ret0, _ := ret[0].([]*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksPage indicates an expected call of GetTasksPage.
+func (mr *MockTaskRepositoryMockRecorder) GetTasksPage(ctx, statusFilter, afterID, skip, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksPage", reflect.TypeOf((*MockTaskRepository)(nil).GetTasksPage), ctx, statusFilter, afterID, skip, limit)
+}
+
+// Restore mocks base method.
+func (m *MockTaskRepository) Restore(ctx context.Context, snapshot *models.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTaskRepositoryMockRecorder) Restore(ctx, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTaskRepository)(nil).Restore), ctx, snapshot)
+}
+
+// Snapshot mocks base method.
+func (m *MockTaskRepository) Snapshot(ctx context.Context) (*models.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(*models.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockTaskRepositoryMockRecorder) Snapshot(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockTaskRepository)(nil).Snapshot), ctx)
+}
+
+// UpdateTask mocks base method.
+func (m *MockTaskRepository) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, task)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockTaskRepositoryMockRecorder) UpdateTask(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskRepository)(nil).UpdateTask), ctx, task)
+}
+
+// WithTx mocks base method.
+func (m *MockTaskRepository) WithTx(ctx context.Context, fn func(tx app.TaskTx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTaskRepositoryMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTaskRepository)(nil).WithTx), ctx, fn)
+}
+
+// MockTaskTx is a mock of TaskTx interface.
+type MockTaskTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskTxMockRecorder
+}
+
+// MockTaskTxMockRecorder is the mock recorder for MockTaskTx.
+type MockTaskTxMockRecorder struct {
+	mock *MockTaskTx
+}
+
+// NewMockTaskTx creates a new mock instance.
+func NewMockTaskTx(ctrl *gomock.Controller) *MockTaskTx {
+	mock := &MockTaskTx{ctrl: ctrl}
+	mock.recorder = &MockTaskTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskTx) EXPECT() *MockTaskTxMockRecorder {
+	return m.recorder
+}
+
+// AddEvent mocks base method.
+func (m *MockTaskTx) AddEvent(ctx context.Context, event models.TaskEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *MockTaskTxMockRecorder) AddEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockTaskTx)(nil).AddEvent), ctx, event)
+}
+
+// CreateTask mocks base method.
+func (m *MockTaskTx) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTask", ctx, task)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTask indicates an expected call of CreateTask.
+func (mr *MockTaskTxMockRecorder) CreateTask(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTaskTx)(nil).CreateTask), ctx, task)
+}
+
+// DeleteTask mocks base method.
+func (m *MockTaskTx) DeleteTask(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTaskTxMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskTx)(nil).DeleteTask), ctx, id)
+}
+
 // GetAllTasks mocks base method.
-func (m *MockTaskRepository) GetAllTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
+func (m *MockTaskTx) GetAllTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllTasks", ctx, statusFilter)
 	ret0, _ := ret[0].([]*models.Task)
@@ -74,13 +245,13 @@ func (m *MockTaskRepository) GetAllTasks(ctx context.Context, statusFilter model
 }
 
 // GetAllTasks indicates an expected call of GetAllTasks.
-func (mr *MockTaskRepositoryMockRecorder) GetAllTasks(ctx, statusFilter interface{}) *gomock.Call {
+func (mr *MockTaskTxMockRecorder) GetAllTasks(ctx, statusFilter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockTaskRepository)(nil).GetAllTasks), ctx, statusFilter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockTaskTx)(nil).GetAllTasks), ctx, statusFilter)
 }
 
 // GetTaskByID mocks base method.
-func (m *MockTaskRepository) GetTaskByID(ctx context.Context, id int64) (*models.Task, error) {
+func (m *MockTaskTx) GetTaskByID(ctx context.Context, id int64) (*models.Task, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
 	ret0, _ := ret[0].(*models.Task)
@@ -89,13 +260,28 @@ func (m *MockTaskRepository) GetTaskByID(ctx context.Context, id int64) (*models
 }
 
 // GetTaskByID indicates an expected call of GetTaskByID.
-func (mr *MockTaskRepositoryMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.Call {
+func (mr *MockTaskTxMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskRepository)(nil).GetTaskByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskTx)(nil).GetTaskByID), ctx, id)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskTx) RestoreTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, task)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskTxMockRecorder) RestoreTask(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskTx)(nil).RestoreTask), ctx, task)
 }
 
 // UpdateTask mocks base method.
-func (m *MockTaskRepository) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+func (m *MockTaskTx) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateTask", ctx, task)
 	ret0, _ := ret[0].(*models.Task)
@@ -104,9 +290,75 @@ func (m *MockTaskRepository) UpdateTask(ctx context.Context, task *models.Task)
 }
 
 // UpdateTask indicates an expected call of UpdateTask.
-func (mr *MockTaskRepositoryMockRecorder) UpdateTask(ctx, task interface{}) *gomock.Call {
+func (mr *MockTaskTxMockRecorder) UpdateTask(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskRepository)(nil).UpdateTask), ctx, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskTx)(nil).UpdateTask), ctx, task)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AckEvents mocks base method.
+func (m *MockOutboxRepository) AckEvents(ctx context.Context, upToID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AckEvents", ctx, upToID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckEvents indicates an expected call of AckEvents.
+func (mr *MockOutboxRepositoryMockRecorder) AckEvents(ctx, upToID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckEvents", reflect.TypeOf((*MockOutboxRepository)(nil).AckEvents), ctx, upToID)
+}
+
+// EventsAvailable mocks base method.
+func (m *MockOutboxRepository) EventsAvailable() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsAvailable")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// EventsAvailable indicates an expected call of EventsAvailable.
+func (mr *MockOutboxRepositoryMockRecorder) EventsAvailable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsAvailable", reflect.TypeOf((*MockOutboxRepository)(nil).EventsAvailable))
+}
+
+// GetPendingEvents mocks base method.
+func (m *MockOutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]models.TaskEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingEvents", ctx, limit)
+	ret0, _ := ret[0].([]models.TaskEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingEvents indicates an expected call of GetPendingEvents.
+func (mr *MockOutboxRepositoryMockRecorder) GetPendingEvents(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingEvents", reflect.TypeOf((*MockOutboxRepository)(nil).GetPendingEvents), ctx, limit)
 }
 
 // MockTaskUsecase is a mock of TaskUsecase interface.
@@ -132,6 +384,66 @@ func (m *MockTaskUsecase) EXPECT() *MockTaskUsecaseMockRecorder {
 	return m.recorder
 }
 
+// BatchCreateTasks mocks base method.
+func (m *MockTaskUsecase) BatchCreateTasks(ctx context.Context, items []models.CreateTaskRequest, atomic bool) ([]models.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreateTasks", ctx, items, atomic)
+	ret0, _ := ret[0].([]models.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreateTasks indicates an expected call of BatchCreateTasks.
+func (mr *MockTaskUsecaseMockRecorder) BatchCreateTasks(ctx, items, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateTasks", reflect.TypeOf((*MockTaskUsecase)(nil).BatchCreateTasks), ctx, items, atomic)
+}
+
+// BatchDeleteTasks mocks base method.
+func (m *MockTaskUsecase) BatchDeleteTasks(ctx context.Context, ids []int64, atomic bool) ([]models.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteTasks", ctx, ids, atomic)
+	ret0, _ := ret[0].([]models.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteTasks indicates an expected call of BatchDeleteTasks.
+func (mr *MockTaskUsecaseMockRecorder) BatchDeleteTasks(ctx, ids, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteTasks", reflect.TypeOf((*MockTaskUsecase)(nil).BatchDeleteTasks), ctx, ids, atomic)
+}
+
+// BatchUpdateTasks mocks base method.
+func (m *MockTaskUsecase) BatchUpdateTasks(ctx context.Context, items []models.BatchUpdateItem, atomic bool) ([]models.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpdateTasks", ctx, items, atomic)
+	ret0, _ := ret[0].([]models.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpdateTasks indicates an expected call of BatchUpdateTasks.
+func (mr *MockTaskUsecaseMockRecorder) BatchUpdateTasks(ctx, items, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdateTasks", reflect.TypeOf((*MockTaskUsecase)(nil).BatchUpdateTasks), ctx, items, atomic)
+}
+
+// CountTasks mocks base method.
+func (m *MockTaskUsecase) CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasks", ctx, statusFilter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasks indicates an expected call of CountTasks.
+func (mr *MockTaskUsecaseMockRecorder) CountTasks(ctx, statusFilter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasks", reflect.TypeOf((*MockTaskUsecase)(nil).CountTasks), ctx, statusFilter)
+}
+
 // CreateTask mocks base method.
 func (m *MockTaskUsecase) CreateTask(ctx context.Context, title, description string) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -176,19 +488,62 @@ func (mr *MockTaskUsecaseMockRecorder) GetTask(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockTaskUsecase)(nil).GetTask), ctx, id)
 }
 
-// ListTasks mocks base method.
-func (m *MockTaskUsecase) ListTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
+// ImportTasks mocks base method.
+func (m *MockTaskUsecase) ImportTasks(ctx context.Context, items []models.ImportItem, opts models.ImportOptions) ([]models.ImportItemResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTasks", ctx, statusFilter)
-	ret0, _ := ret[0].([]*models.Task)
+	ret := m.ctrl.Call(m, "ImportTasks", ctx, items, opts)
+	ret0, _ := ret[0].([]models.ImportItemResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ImportTasks indicates an expected call of ImportTasks.
+func (mr *MockTaskUsecaseMockRecorder) ImportTasks(ctx, items, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasks", reflect.TypeOf((*MockTaskUsecase)(nil).ImportTasks), ctx, items, opts)
+}
+
+// ListTasks mocks base method.
+func (m *MockTaskUsecase) ListTasks(ctx context.Context, statusFilter models.TaskStatus, offset, limit int) iter.Seq2[*models.Task, error] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", ctx, statusFilter, offset, limit)
+	ret0, _ := ret[0].(iter.Seq2[*models.Task, error])
+	return ret0
+}
+
 // ListTasks indicates an expected call of ListTasks.
-func (mr *MockTaskUsecaseMockRecorder) ListTasks(ctx, statusFilter interface{}) *gomock.Call {
+func (mr *MockTaskUsecaseMockRecorder) ListTasks(ctx, statusFilter, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskUsecase)(nil).ListTasks), ctx, statusFilter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskUsecase)(nil).ListTasks), ctx, statusFilter, offset, limit)
+}
+
+// RestoreSnapshot mocks base method.
+func (m *MockTaskUsecase) RestoreSnapshot(ctx context.Context, snapshot *models.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockTaskUsecaseMockRecorder) RestoreSnapshot(ctx, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockTaskUsecase)(nil).RestoreSnapshot), ctx, snapshot)
+}
+
+// Snapshot mocks base method.
+func (m *MockTaskUsecase) Snapshot(ctx context.Context) (*models.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(*models.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockTaskUsecaseMockRecorder) Snapshot(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockTaskUsecase)(nil).Snapshot), ctx)
 }
 
 // UpdateTask mocks base method.
@@ -205,3 +560,409 @@ func (mr *MockTaskUsecaseMockRecorder) UpdateTask(ctx, id, newTitle, newDescript
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskUsecase)(nil).UpdateTask), ctx, id, newTitle, newDescription, status)
 }
+
+// MockEventSink is a mock of EventSink interface.
+type MockEventSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventSinkMockRecorder
+}
+
+// MockEventSinkMockRecorder is the mock recorder for MockEventSink.
+type MockEventSinkMockRecorder struct {
+	mock *MockEventSink
+}
+
+// NewMockEventSink creates a new mock instance.
+func NewMockEventSink(ctrl *gomock.Controller) *MockEventSink {
+	mock := &MockEventSink{ctrl: ctrl}
+	mock.recorder = &MockEventSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventSink) EXPECT() *MockEventSinkMockRecorder {
+	return m.recorder
+}
+
+// Deliver mocks base method.
+func (m *MockEventSink) Deliver(ctx context.Context, event models.TaskEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliver", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deliver indicates an expected call of Deliver.
+func (mr *MockEventSinkMockRecorder) Deliver(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliver", reflect.TypeOf((*MockEventSink)(nil).Deliver), ctx, event)
+}
+
+// MockEventStream is a mock of EventStream interface.
+type MockEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamMockRecorder
+}
+
+// MockEventStreamMockRecorder is the mock recorder for MockEventStream.
+type MockEventStreamMockRecorder struct {
+	mock *MockEventStream
+}
+
+// NewMockEventStream creates a new mock instance.
+func NewMockEventStream(ctrl *gomock.Controller) *MockEventStream {
+	mock := &MockEventStream{ctrl: ctrl}
+	mock.recorder = &MockEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStream) EXPECT() *MockEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEventStream) Subscribe(filter models.EventFilter, lastEventID uint64) (app.EventSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", filter, lastEventID)
+	ret0, _ := ret[0].(app.EventSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventStreamMockRecorder) Subscribe(filter, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventStream)(nil).Subscribe), filter, lastEventID)
+}
+
+// MockEventSubscription is a mock of EventSubscription interface.
+type MockEventSubscription struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventSubscriptionMockRecorder
+}
+
+// MockEventSubscriptionMockRecorder is the mock recorder for MockEventSubscription.
+type MockEventSubscriptionMockRecorder struct {
+	mock *MockEventSubscription
+}
+
+// NewMockEventSubscription creates a new mock instance.
+func NewMockEventSubscription(ctrl *gomock.Controller) *MockEventSubscription {
+	mock := &MockEventSubscription{ctrl: ctrl}
+	mock.recorder = &MockEventSubscriptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventSubscription) EXPECT() *MockEventSubscriptionMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockEventSubscription) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockEventSubscriptionMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEventSubscription)(nil).Close))
+}
+
+// Events mocks base method.
+func (m *MockEventSubscription) Events() <-chan models.TaskEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].(<-chan models.TaskEvent)
+	return ret0
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockEventSubscriptionMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockEventSubscription)(nil).Events))
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDeadLetter mocks base method.
+func (m *MockWebhookRepository) AddDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeadLetter", ctx, letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeadLetter indicates an expected call of AddDeadLetter.
+func (mr *MockWebhookRepositoryMockRecorder) AddDeadLetter(ctx, letter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeadLetter", reflect.TypeOf((*MockWebhookRepository)(nil).AddDeadLetter), ctx, letter)
+}
+
+// AddDeliveryLog mocks base method.
+func (m *MockWebhookRepository) AddDeliveryLog(ctx context.Context, log models.WebhookDeliveryLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeliveryLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeliveryLog indicates an expected call of AddDeliveryLog.
+func (mr *MockWebhookRepositoryMockRecorder) AddDeliveryLog(ctx, log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeliveryLog", reflect.TypeOf((*MockWebhookRepository)(nil).AddDeliveryLog), ctx, log)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetAllWebhooks mocks base method.
+func (m *MockWebhookRepository) GetAllWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWebhooks", ctx)
+	ret0, _ := ret[0].([]*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWebhooks indicates an expected call of GetAllWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) GetAllWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).GetAllWebhooks), ctx)
+}
+
+// GetDeadLetters mocks base method.
+func (m *MockWebhookRepository) GetDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters", ctx)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeadLetters), ctx)
+}
+
+// GetDeliveryLogs mocks base method.
+func (m *MockWebhookRepository) GetDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryLogs", ctx, webhookID)
+	ret0, _ := ret[0].([]models.WebhookDeliveryLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryLogs indicates an expected call of GetDeliveryLogs.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeliveryLogs(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryLogs", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeliveryLogs), ctx, webhookID)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockWebhookRepository) GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, id)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhookByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhookByID), ctx, id)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateWebhook), ctx, webhook)
+}
+
+// MockWebhookUsecase is a mock of WebhookUsecase interface.
+type MockWebhookUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUsecaseMockRecorder
+}
+
+// MockWebhookUsecaseMockRecorder is the mock recorder for MockWebhookUsecase.
+type MockWebhookUsecaseMockRecorder struct {
+	mock *MockWebhookUsecase
+}
+
+// NewMockWebhookUsecase creates a new mock instance.
+func NewMockWebhookUsecase(ctrl *gomock.Controller) *MockWebhookUsecase {
+	mock := &MockWebhookUsecase{ctrl: ctrl}
+	mock.recorder = &MockWebhookUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUsecase) EXPECT() *MockWebhookUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookUsecase) CreateWebhook(ctx context.Context, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, url, eventTypes, secret)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookUsecaseMockRecorder) CreateWebhook(ctx, url, eventTypes, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookUsecase)(nil).CreateWebhook), ctx, url, eventTypes, secret)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookUsecase) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookUsecaseMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookUsecase)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookUsecase) GetWebhook(ctx context.Context, id int64) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookUsecaseMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookUsecase)(nil).GetWebhook), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockWebhookUsecase) ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockWebhookUsecaseMockRecorder) ListDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockWebhookUsecase)(nil).ListDeadLetters), ctx)
+}
+
+// ListDeliveryLogs mocks base method.
+func (m *MockWebhookUsecase) ListDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveryLogs", ctx, webhookID)
+	ret0, _ := ret[0].([]models.WebhookDeliveryLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveryLogs indicates an expected call of ListDeliveryLogs.
+func (mr *MockWebhookUsecaseMockRecorder) ListDeliveryLogs(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveryLogs", reflect.TypeOf((*MockWebhookUsecase)(nil).ListDeliveryLogs), ctx, webhookID)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookUsecase) ListWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookUsecaseMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookUsecase)(nil).ListWebhooks), ctx)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookUsecase) UpdateWebhook(ctx context.Context, id int64, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, url, eventTypes, secret)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookUsecaseMockRecorder) UpdateWebhook(ctx, id, url, eventTypes, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookUsecase)(nil).UpdateWebhook), ctx, id, url, eventTypes, secret)
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type TaskStatus string
 
@@ -29,3 +32,210 @@ type UpdateTaskRequest struct {
 	Description string     `json:"description"`
 	Status      TaskStatus `json:"status"`
 }
+
+type BatchUpdateItem struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+}
+
+type BatchCreateRequest struct {
+	Items  []CreateTaskRequest `json:"items"`
+	Atomic bool                `json:"atomic"`
+}
+
+type BatchUpdateRequest struct {
+	Items  []BatchUpdateItem `json:"items"`
+	Atomic bool              `json:"atomic"`
+}
+
+type BatchDeleteRequest struct {
+	IDs    []int64 `json:"ids"`
+	Atomic bool    `json:"atomic"`
+}
+
+type BatchItemResult struct {
+	Index int
+	ID    int64
+	Task  *Task
+	Err   error
+}
+
+type BatchItemResponse struct {
+	Index  int    `json:"index"`
+	ID     int64  `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Task   *Task  `json:"task,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Results   []BatchItemResponse `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// ImportConflictPolicy decides what an import does with a task whose ID
+// already exists.
+type ImportConflictPolicy string
+
+const (
+	ImportSkip   ImportConflictPolicy = "skip"
+	ImportUpsert ImportConflictPolicy = "upsert"
+)
+
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportSkipped ImportAction = "skipped"
+	ImportFailed  ImportAction = "failed"
+)
+
+type ImportOptions struct {
+	OnConflict ImportConflictPolicy
+	DryRun     bool
+}
+
+// ImportItem is a task read from an import file; Row is its 1-based position
+// among the file's records.
+type ImportItem struct {
+	Row  int
+	Task Task
+}
+
+type ImportItemResult struct {
+	Row    int
+	ID     int64
+	Action ImportAction
+	Err    error
+}
+
+type ImportItemResponse struct {
+	Row    int          `json:"row"`
+	ID     int64        `json:"id,omitempty"`
+	Action ImportAction `json:"action"`
+	Error  string       `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Results []ImportItemResponse `json:"results"`
+}
+
+// Snapshot is the state of the task store at one moment: the tasks ordered by
+// ID and the ID of the last event committed before it was taken.
+type Snapshot struct {
+	TakenAt     time.Time
+	LastEventID uint64
+	Tasks       []*Task
+}
+
+type RestoreResponse struct {
+	Restored    int       `json:"restored"`
+	TakenAt     time.Time `json:"taken_at"`
+	LastEventID uint64    `json:"last_event_id"`
+}
+
+type TaskEventType string
+
+const (
+	EventTaskCreated TaskEventType = "task.created"
+	EventTaskUpdated TaskEventType = "task.updated"
+	EventTaskDeleted TaskEventType = "task.deleted"
+
+	EventTaskStatusChanged TaskEventType = "task.status_changed"
+)
+
+type TaskEvent struct {
+	ID             uint64        `json:"id"`
+	Type           TaskEventType `json:"type"`
+	TaskID         int64         `json:"task_id"`
+	Task           *Task         `json:"task,omitempty"`
+	PreviousStatus TaskStatus    `json:"previous_status,omitempty"`
+	OccurredAt     time.Time     `json:"occurred_at"`
+}
+
+type EventFilter struct {
+	Status  TaskStatus
+	TaskIDs []int64
+}
+
+func (f EventFilter) Matches(event TaskEvent) bool {
+	if len(f.TaskIDs) > 0 && !slices.Contains(f.TaskIDs, event.TaskID) {
+		return false
+	}
+
+	if f.Status != "" && event.Task != nil && event.Task.Status != f.Status {
+		return false
+	}
+
+	return true
+}
+
+type WebSocketRequest struct {
+	Action      string     `json:"action"`
+	ID          string     `json:"id"`
+	TaskIDs     []int64    `json:"task_ids,omitempty"`
+	Status      TaskStatus `json:"status,omitempty"`
+	LastEventID uint64     `json:"last_event_id,omitempty"`
+}
+
+type WebSocketMessage struct {
+	Type  string     `json:"type"`
+	ID    string     `json:"id,omitempty"`
+	Event *TaskEvent `json:"event,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+type Webhook struct {
+	ID         int64           `json:"id"`
+	URL        string          `json:"url"`
+	EventTypes []TaskEventType `json:"event_types"`
+	Secret     string          `json:"-"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func (w *Webhook) Accepts(eventType TaskEventType) bool {
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+}
+
+type CreateWebhookRequest struct {
+	URL        string          `json:"url"`
+	EventTypes []TaskEventType `json:"event_types"`
+	Secret     string          `json:"secret"`
+}
+
+type UpdateWebhookRequest struct {
+	URL        string          `json:"url"`
+	EventTypes []TaskEventType `json:"event_types"`
+	Secret     string          `json:"secret"`
+}
+
+type WebhookDeliveryLog struct {
+	WebhookID   int64         `json:"webhook_id"`
+	EventID     uint64        `json:"event_id"`
+	EventType   TaskEventType `json:"event_type"`
+	Attempt     int           `json:"attempt"`
+	StatusCode  int           `json:"status_code,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Success     bool          `json:"success"`
+	Duration    time.Duration `json:"duration_ns"`
+	AttemptedAt time.Time     `json:"attempted_at"`
+}
+
+type DeadLetter struct {
+	WebhookID int64     `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Event     TaskEvent `json:"event"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
@@ -1,92 +1,157 @@
 package repository
 
 import (
+	"cmp"
 	"context"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/supchaser/LO_test_task/internal/app"
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
 )
 
+var operationDuration = metrics.NewHistogramVec(
+	"task_repository_operation_duration_seconds",
+	"Task repository operation latency by operation.",
+	metrics.DefaultBuckets,
+	"operation",
+)
+
+func observeOperation(operation string, started time.Time) {
+	operationDuration.WithLabelValues(operation).Observe(time.Since(started).Seconds())
+}
+
 type TaskRepository struct {
 	tasks map[int64]*models.Task
-	mu    sync.RWMutex
+	// ids holds the keys of tasks in ascending order, so listings can be read
+	// page by page without sorting the whole store for every page.
+	ids             []int64
+	outbox          []models.TaskEvent
+	lastEventID     uint64
+	eventsAvailable chan struct{}
+	mu              sync.RWMutex
 }
 
 func CreateTaskRepository() *TaskRepository {
 	return &TaskRepository{
-		tasks: make(map[int64]*models.Task),
+		tasks:           make(map[int64]*models.Task),
+		eventsAvailable: make(chan struct{}, 1),
 	}
 }
 
-func (r *TaskRepository) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
-	const funcName = "Repository.CreateTask"
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if task.ID < 0 {
-		logger.Error("invalid task ID", errs.ErrInvalidID, map[string]any{
-			"task_id": task.ID,
-			"method":  funcName,
-		})
-		return nil, errs.ErrInvalidID
-	}
-
-	now := time.Now()
-	task.CreatedAt = now
-	task.UpdatedAt = now
+func cloneTask(task *models.Task) *models.Task {
+	clone := *task
+	return &clone
+}
 
-	r.tasks[task.ID] = task
+func (r *TaskRepository) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	defer observeOperation("create_task", time.Now())
 
-	logger.Info("task created", map[string]any{
-		"task_id": task.ID,
-		"method":  funcName,
+	var created *models.Task
+	err := r.write(ctx, func(tx *taskTx) error {
+		var err error
+		created, err = tx.CreateTask(ctx, task)
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return task, nil
+	return created, nil
 }
 
 func (r *TaskRepository) GetTaskByID(ctx context.Context, id int64) (*models.Task, error) {
 	const funcName = "Repository.GetTaskByID"
+	defer observeOperation("get_task", time.Now())
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	task, exists := r.tasks[id]
 	if !exists {
-		logger.Error("task not found", errs.ErrTaskNotFound, map[string]any{
+		span.RecordError(errs.ErrTaskNotFound)
+		logger.ErrorContext(ctx, "task not found", errs.ErrTaskNotFound, map[string]any{
 			"task_id": id,
 			"method":  funcName,
 		})
 		return nil, errs.ErrTaskNotFound
 	}
 
-	logger.Info("task retrieved", map[string]any{
+	logger.DebugContext(ctx, "task retrieved", map[string]any{
 		"task_id": id,
 		"method":  funcName,
 	})
 
-	return task, nil
+	return cloneTask(task), nil
+}
+
+// put and remove keep the ID index in step with the tasks; callers hold the
+// write lock.
+func (r *TaskRepository) put(task *models.Task) {
+	if _, exists := r.tasks[task.ID]; !exists {
+		i, _ := slices.BinarySearch(r.ids, task.ID)
+		r.ids = slices.Insert(r.ids, i, task.ID)
+	}
+	r.tasks[task.ID] = task
+}
+
+func (r *TaskRepository) remove(id int64) {
+	if i, found := slices.BinarySearch(r.ids, id); found {
+		r.ids = slices.Delete(r.ids, i, i+1)
+	}
+	delete(r.tasks, id)
 }
 
-func (r *TaskRepository) GetAllTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
-	const funcName = "Repository.GetAllTasks"
+// GetTasksPage returns up to limit tasks with IDs above afterID in ID order,
+// passing over the first skip of them. Only the returned tasks are copied, so
+// a listing read page by page never holds the whole store at once.
+func (r *TaskRepository) GetTasksPage(ctx context.Context, statusFilter models.TaskStatus, afterID int64, skip, limit int) ([]*models.Task, error) {
+	const funcName = "Repository.GetTasksPage"
+	defer observeOperation("list_tasks", time.Now())
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	start, found := slices.BinarySearch(r.ids, afterID)
+	if found {
+		start++
+	}
+
 	tasks := []*models.Task{}
-	for _, task := range r.tasks {
-		if statusFilter == "" || task.Status == statusFilter {
-			tasks = append(tasks, task)
+	for _, id := range r.ids[start:] {
+		if len(tasks) == limit {
+			break
 		}
+		task := r.tasks[id]
+		if statusFilter != "" && task.Status != statusFilter {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		tasks = append(tasks, cloneTask(task))
 	}
 
-	logger.Info("tasks list retrieved", map[string]any{
+	logger.DebugContext(ctx, "tasks page retrieved", map[string]any{
 		"count":         len(tasks),
+		"after_id":      afterID,
 		"status_filter": statusFilter,
 		"method":        funcName,
 	})
@@ -94,54 +159,480 @@ func (r *TaskRepository) GetAllTasks(ctx context.Context, statusFilter models.Ta
 	return tasks, nil
 }
 
+func (r *TaskRepository) CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if statusFilter == "" {
+		return len(r.tasks), nil
+	}
+
+	count := 0
+	for _, task := range r.tasks {
+		if task.Status == statusFilter {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func (r *TaskRepository) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
-	const funcName = "Repository.UpdateTask"
+	defer observeOperation("update_task", time.Now())
+
+	var updated *models.Task
+	err := r.write(ctx, func(tx *taskTx) error {
+		var err error
+		updated, err = tx.UpdateTask(ctx, task)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (r *TaskRepository) DeleteTask(ctx context.Context, id int64) error {
+	defer observeOperation("delete_task", time.Now())
+
+	return r.write(ctx, func(tx *taskTx) error {
+		return tx.DeleteTask(ctx, id)
+	})
+}
+
+func (r *TaskRepository) WithTx(ctx context.Context, fn func(tx app.TaskTx) error) error {
+	const funcName = "Repository.WithTx"
+	defer observeOperation("transaction", time.Now())
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	var changes int
+	err := r.write(ctx, func(tx *taskTx) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		changes = len(tx.staged)
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "transaction rolled back", err, map[string]any{
+			"method": funcName,
+		})
+		return err
+	}
+
+	logger.InfoContext(ctx, "transaction committed", map[string]any{
+		"changes": changes,
+		"method":  funcName,
+	})
+
+	return nil
+}
+
+// write runs fn against a staged view of the store while holding the write
+// lock, so transactions are serialized and only successful ones become visible.
+// Events added during the transaction land in the outbox in the same commit.
+func (r *TaskRepository) write(ctx context.Context, fn func(tx *taskTx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &taskTx{
+		repo:   r,
+		staged: make(map[int64]*models.Task),
+	}
+	defer func() {
+		tx.done = true
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for id, task := range tx.staged {
+		if task == nil {
+			r.remove(id)
+		} else {
+			r.put(task)
+		}
+	}
+
+	if len(tx.events) > 0 {
+		now := time.Now()
+		for _, event := range tx.events {
+			r.lastEventID++
+			event.ID = r.lastEventID
+			event.OccurredAt = now
+			r.outbox = append(r.outbox, event)
+		}
+
+		select {
+		case r.eventsAvailable <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Snapshot copies the store under the read lock, so it sees either all or
+// none of the changes of any transaction. Stored tasks are never modified in
+// place, which keeps the copy cheap and writers blocked only briefly.
+func (r *TaskRepository) Snapshot(ctx context.Context) (*models.Snapshot, error) {
+	const funcName = "Repository.Snapshot"
+	defer observeOperation("snapshot", time.Now())
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	snapshot := &models.Snapshot{
+		TakenAt:     time.Now(),
+		LastEventID: r.lastEventID,
+		Tasks:       slices.Collect(maps.Values(r.tasks)),
+	}
+	r.mu.RUnlock()
+
+	for i, task := range snapshot.Tasks {
+		snapshot.Tasks[i] = cloneTask(task)
+	}
+	slices.SortFunc(snapshot.Tasks, func(a, b *models.Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	logger.InfoContext(ctx, "snapshot taken", map[string]any{
+		"count":         len(snapshot.Tasks),
+		"last_event_id": snapshot.LastEventID,
+		"method":        funcName,
+	})
+
+	return snapshot, nil
+}
+
+// Restore loads a snapshot into an empty store. Event IDs continue after the
+// snapshot's last one, so clients resuming a stream do not see them reused.
+func (r *TaskRepository) Restore(ctx context.Context, snapshot *models.Snapshot) error {
+	const funcName = "Repository.Restore"
+	defer observeOperation("restore", time.Now())
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tasks := make(map[int64]*models.Task, len(snapshot.Tasks))
+	for _, task := range snapshot.Tasks {
+		if task.ID <= 0 {
+			span.RecordError(errs.ErrInvalidID)
+			logger.ErrorContext(ctx, "invalid task ID", errs.ErrInvalidID, map[string]any{
+				"task_id": task.ID,
+				"method":  funcName,
+			})
+			return errs.ErrInvalidID
+		}
+		tasks[task.ID] = cloneTask(task)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.tasks) > 0 {
+		span.RecordError(errs.ErrStoreNotEmpty)
+		logger.ErrorContext(ctx, "cannot restore into a non-empty store", errs.ErrStoreNotEmpty, map[string]any{
+			"count":  len(r.tasks),
+			"method": funcName,
+		})
+		return errs.ErrStoreNotEmpty
+	}
+
+	r.tasks = tasks
+	r.ids = slices.Sorted(maps.Keys(tasks))
+	r.lastEventID = max(r.lastEventID, snapshot.LastEventID)
+
+	logger.InfoContext(ctx, "snapshot restored", map[string]any{
+		"count":         len(tasks),
+		"last_event_id": r.lastEventID,
+		"method":        funcName,
+	})
+
+	return nil
+}
+
+// Ping reports whether the storage can be read, i.e. its lock can be taken.
+func (r *TaskRepository) Ping(ctx context.Context) error {
+	r.mu.RLock()
+	r.mu.RUnlock()
+
+	return ctx.Err()
+}
+
+func (r *TaskRepository) CountTasksByStatus() map[models.TaskStatus]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[models.TaskStatus]int)
+	for _, task := range r.tasks {
+		counts[task.Status]++
+	}
+
+	return counts
+}
+
+func (r *TaskRepository) GetPendingEvents(ctx context.Context, limit int) ([]models.TaskEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]models.TaskEvent, min(limit, len(r.outbox)))
+	copy(events, r.outbox)
+
+	return events, nil
+}
 
+func (r *TaskRepository) AckEvents(ctx context.Context, upToID uint64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	existingTask, exists := r.tasks[task.ID]
+	acked := 0
+	for acked < len(r.outbox) && r.outbox[acked].ID <= upToID {
+		acked++
+	}
+	r.outbox = slices.Delete(r.outbox, 0, acked)
+
+	return nil
+}
+
+func (r *TaskRepository) EventsAvailable() <-chan struct{} {
+	return r.eventsAvailable
+}
+
+type taskTx struct {
+	repo   *TaskRepository
+	staged map[int64]*models.Task
+	events []models.TaskEvent
+	done   bool
+}
+
+func (tx *taskTx) lookup(id int64) (*models.Task, bool) {
+	if task, ok := tx.staged[id]; ok {
+		return task, task != nil
+	}
+
+	task, ok := tx.repo.tasks[id]
+	return task, ok
+}
+
+func (tx *taskTx) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	const funcName = "Repository.CreateTask"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if tx.done {
+		return nil, errs.ErrTxDone
+	}
+
+	if task.ID < 0 {
+		span.RecordError(errs.ErrInvalidID)
+		logger.ErrorContext(ctx, "invalid task ID", errs.ErrInvalidID, map[string]any{
+			"task_id": task.ID,
+			"method":  funcName,
+		})
+		return nil, errs.ErrInvalidID
+	}
+
+	created := cloneTask(task)
+	now := time.Now()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	tx.staged[created.ID] = created
+
+	logger.InfoContext(ctx, "task created", map[string]any{
+		"task_id": created.ID,
+		"method":  funcName,
+	})
+
+	return cloneTask(created), nil
+}
+
+func (tx *taskTx) GetTaskByID(ctx context.Context, id int64) (*models.Task, error) {
+	const funcName = "Repository.GetTaskByID"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if tx.done {
+		return nil, errs.ErrTxDone
+	}
+
+	task, exists := tx.lookup(id)
+	if !exists {
+		span.RecordError(errs.ErrTaskNotFound)
+		logger.ErrorContext(ctx, "task not found", errs.ErrTaskNotFound, map[string]any{
+			"task_id": id,
+			"method":  funcName,
+		})
+		return nil, errs.ErrTaskNotFound
+	}
+
+	return cloneTask(task), nil
+}
+
+func (tx *taskTx) GetAllTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
+	if tx.done {
+		return nil, errs.ErrTxDone
+	}
+
+	tasks := []*models.Task{}
+	for id, task := range tx.repo.tasks {
+		if _, staged := tx.staged[id]; staged {
+			continue
+		}
+		if statusFilter == "" || task.Status == statusFilter {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+	for _, task := range tx.staged {
+		if task != nil && (statusFilter == "" || task.Status == statusFilter) {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+
+	return tasks, nil
+}
+
+func (tx *taskTx) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	const funcName = "Repository.UpdateTask"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if tx.done {
+		return nil, errs.ErrTxDone
+	}
+
+	existingTask, exists := tx.lookup(task.ID)
 	if !exists {
-		logger.Error("task not found for update", errs.ErrTaskNotFound, map[string]any{
+		span.RecordError(errs.ErrTaskNotFound)
+		logger.ErrorContext(ctx, "task not found for update", errs.ErrTaskNotFound, map[string]any{
 			"task_id": task.ID,
 			"method":  funcName,
 		})
 		return nil, errs.ErrTaskNotFound
 	}
 
-	existingTask.Title = task.Title
-	existingTask.Description = task.Description
-	existingTask.Status = task.Status
-	existingTask.UpdatedAt = time.Now()
+	updated := cloneTask(existingTask)
+	updated.Title = task.Title
+	updated.Description = task.Description
+	updated.Status = task.Status
+	updated.UpdatedAt = time.Now()
+
+	tx.staged[updated.ID] = updated
 
-	logger.Info("task updated", map[string]any{
+	logger.InfoContext(ctx, "task updated", map[string]any{
 		"task_id": task.ID,
 		"method":  funcName,
 	})
 
-	return existingTask, nil
+	return cloneTask(updated), nil
 }
 
-func (r *TaskRepository) DeleteTask(ctx context.Context, id int64) error {
+func (tx *taskTx) DeleteTask(ctx context.Context, id int64) error {
 	const funcName = "Repository.DeleteTask"
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if tx.done {
+		return errs.ErrTxDone
+	}
 
-	if _, exists := r.tasks[id]; !exists {
-		logger.Error("task not found for deletion", errs.ErrTaskNotFound, map[string]any{
+	if _, exists := tx.lookup(id); !exists {
+		span.RecordError(errs.ErrTaskNotFound)
+		logger.ErrorContext(ctx, "task not found for deletion", errs.ErrTaskNotFound, map[string]any{
 			"task_id": id,
 			"method":  funcName,
 		})
 		return errs.ErrTaskNotFound
 	}
 
-	delete(r.tasks, id)
+	tx.staged[id] = nil
 
-	logger.Info("task deleted", map[string]any{
+	logger.InfoContext(ctx, "task deleted", map[string]any{
 		"task_id": id,
 		"method":  funcName,
 	})
 
 	return nil
 }
+
+func (tx *taskTx) RestoreTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	const funcName = "Repository.RestoreTask"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if tx.done {
+		return nil, errs.ErrTxDone
+	}
+
+	if task.ID <= 0 {
+		span.RecordError(errs.ErrInvalidID)
+		logger.ErrorContext(ctx, "invalid task ID", errs.ErrInvalidID, map[string]any{
+			"task_id": task.ID,
+			"method":  funcName,
+		})
+		return nil, errs.ErrInvalidID
+	}
+
+	restored := cloneTask(task)
+	now := time.Now()
+	if restored.CreatedAt.IsZero() {
+		restored.CreatedAt = now
+	}
+	if restored.UpdatedAt.IsZero() {
+		restored.UpdatedAt = restored.CreatedAt
+	}
+
+	tx.staged[restored.ID] = restored
+
+	logger.DebugContext(ctx, "task restored", map[string]any{
+		"task_id": restored.ID,
+		"method":  funcName,
+	})
+
+	return cloneTask(restored), nil
+}
+
+func (tx *taskTx) AddEvent(ctx context.Context, event models.TaskEvent) error {
+	if tx.done {
+		return errs.ErrTxDone
+	}
+
+	if event.Task != nil {
+		event.Task = cloneTask(event.Task)
+	}
+	tx.events = append(tx.events, event)
+
+	return nil
+}
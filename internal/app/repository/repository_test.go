@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app"
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
 )
@@ -62,41 +65,88 @@ func TestGetTaskByID_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, errs.ErrTaskNotFound)
 }
 
-func TestGetAllTasks_NoFilter(t *testing.T) {
+func TestGetTasksPage(t *testing.T) {
 	repo := CreateTaskRepository()
-	tasks := []*models.Task{
+	for _, task := range []*models.Task{
+		{ID: 5, Status: models.StatusPending},
 		{ID: 1, Status: models.StatusPending},
-		{ID: 2, Status: models.StatusInProgress},
 		{ID: 3, Status: models.StatusCompleted},
+		{ID: 4, Status: models.StatusPending},
+		{ID: 2, Status: models.StatusInProgress},
+	} {
+		repo.put(task)
 	}
-	for _, task := range tasks {
-		repo.tasks[task.ID] = task
+
+	ids := func(tasks []*models.Task) []int64 {
+		result := []int64{}
+		for _, task := range tasks {
+			result = append(result, task.ID)
+		}
+		return result
 	}
 
-	result, err := repo.GetAllTasks(context.Background(), "")
+	tests := []struct {
+		name         string
+		statusFilter models.TaskStatus
+		afterID      int64
+		skip, limit  int
+		expectedIDs  []int64
+	}{
+		{name: "First Page", limit: 2, expectedIDs: []int64{1, 2}},
+		{name: "After ID", afterID: 2, limit: 2, expectedIDs: []int64{3, 4}},
+		{name: "Skip", skip: 3, limit: 10, expectedIDs: []int64{4, 5}},
+		{name: "With Filter", statusFilter: models.StatusPending, afterID: 1, limit: 10, expectedIDs: []int64{4, 5}},
+		{name: "Skip With Filter", statusFilter: models.StatusPending, skip: 1, limit: 1, expectedIDs: []int64{4}},
+		{name: "Past End", afterID: 5, limit: 10, expectedIDs: []int64{}},
+	}
 
-	assert.NoError(t, err)
-	assert.Len(t, result, 3)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.GetTasksPage(context.Background(), tt.statusFilter, tt.afterID, tt.skip, tt.limit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIDs, ids(result))
+		})
+	}
 }
 
-func TestGetAllTasks_WithFilter(t *testing.T) {
+func TestGetTasksPage_FollowsWrites(t *testing.T) {
 	repo := CreateTaskRepository()
-	tasks := []*models.Task{
-		{ID: 1, Status: models.StatusPending},
-		{ID: 2, Status: models.StatusInProgress},
-		{ID: 3, Status: models.StatusPending},
+	for _, id := range []int64{30, 10, 20} {
+		_, err := repo.CreateTask(context.Background(), &models.Task{ID: id})
+		assert.NoError(t, err)
 	}
-	for _, task := range tasks {
-		repo.tasks[task.ID] = task
-	}
-
-	result, err := repo.GetAllTasks(context.Background(), models.StatusPending)
+	assert.NoError(t, repo.DeleteTask(context.Background(), 20))
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		if _, err := tx.CreateTask(context.Background(), &models.Task{ID: 15}); err != nil {
+			return err
+		}
+		return tx.DeleteTask(context.Background(), 30)
+	})
+	assert.NoError(t, err)
 
+	result, err := repo.GetTasksPage(context.Background(), "", 0, 0, 10)
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
-	for _, task := range result {
-		assert.Equal(t, models.StatusPending, task.Status)
-	}
+	assert.Equal(t, int64(10), result[0].ID)
+	assert.Equal(t, int64(15), result[1].ID)
+	assert.Equal(t, []int64{10, 15}, repo.ids)
+}
+
+func TestCountTasks(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Status: models.StatusPending}
+	repo.tasks[2] = &models.Task{ID: 2, Status: models.StatusInProgress}
+	repo.tasks[3] = &models.Task{ID: 3, Status: models.StatusPending}
+
+	count, err := repo.CountTasks(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = repo.CountTasks(context.Background(), models.StatusPending)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
 }
 
 func TestUpdateTask_Success(t *testing.T) {
@@ -181,7 +231,319 @@ func TestConcurrentAccess(t *testing.T) {
 
 	wg.Wait()
 
-	tasks, err := repo.GetAllTasks(context.Background(), "")
+	tasks, err := repo.GetTasksPage(context.Background(), "", 0, 0, count+1)
 	assert.NoError(t, err)
 	assert.Len(t, tasks, count)
+	assert.Len(t, repo.ids, count)
+}
+
+func TestWithTx_Commit(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Title: "Existing", Status: models.StatusPending}
+	repo.tasks[2] = &models.Task{ID: 2, Title: "To Delete"}
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		if _, err := tx.CreateTask(context.Background(), &models.Task{ID: 3, Title: "Created"}); err != nil {
+			return err
+		}
+		if _, err := tx.UpdateTask(context.Background(), &models.Task{ID: 1, Title: "Updated", Status: models.StatusCompleted}); err != nil {
+			return err
+		}
+		return tx.DeleteTask(context.Background(), 2)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Created", repo.tasks[3].Title)
+	assert.Equal(t, models.StatusCompleted, repo.tasks[1].Status)
+	_, exists := repo.tasks[2]
+	assert.False(t, exists)
+}
+
+func TestWithTx_RollbackOnError(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Title: "Existing"}
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		if _, err := tx.CreateTask(context.Background(), &models.Task{ID: 2, Title: "Created"}); err != nil {
+			return err
+		}
+		if _, err := tx.UpdateTask(context.Background(), &models.Task{ID: 1, Title: "Updated"}); err != nil {
+			return err
+		}
+		return tx.DeleteTask(context.Background(), 999)
+	})
+
+	assert.ErrorIs(t, err, errs.ErrTaskNotFound)
+	assert.Len(t, repo.tasks, 1)
+	assert.Equal(t, "Existing", repo.tasks[1].Title)
+}
+
+func TestWithTx_SeesOwnChanges(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Status: models.StatusPending}
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		_, err := tx.CreateTask(context.Background(), &models.Task{ID: 2, Status: models.StatusPending})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.DeleteTask(context.Background(), 1))
+
+		_, err = tx.GetTaskByID(context.Background(), 1)
+		assert.ErrorIs(t, err, errs.ErrTaskNotFound)
+
+		tasks, err := tx.GetAllTasks(context.Background(), models.StatusPending)
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, int64(2), tasks[0].ID)
+		return nil
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestWithTx_RejectsUseAfterCompletion(t *testing.T) {
+	repo := CreateTaskRepository()
+
+	var leaked app.TaskTx
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		leaked = tx
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = leaked.CreateTask(context.Background(), &models.Task{ID: 1})
+	assert.ErrorIs(t, err, errs.ErrTxDone)
+	assert.Empty(t, repo.tasks)
+}
+
+func TestWithTx_CancelledContext(t *testing.T) {
+	repo := CreateTaskRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := repo.WithTx(ctx, func(tx app.TaskTx) error {
+		_, err := tx.CreateTask(ctx, &models.Task{ID: 1})
+		cancel()
+		return err
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, repo.tasks)
+}
+
+func TestWithTx_SerializesReadModifyWrite(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Description: ""}
+	count := 50
+
+	var wg sync.WaitGroup
+	wg.Add(count)
+	for range count {
+		go func() {
+			defer wg.Done()
+			err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+				task, err := tx.GetTaskByID(context.Background(), 1)
+				if err != nil {
+					return err
+				}
+				task.Description += "x"
+				_, err = tx.UpdateTask(context.Background(), task)
+				return err
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, repo.tasks[1].Description, count)
+}
+
+func TestGetTaskByID_ReturnsCopy(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Title: "Original"}
+
+	task, err := repo.GetTaskByID(context.Background(), 1)
+	assert.NoError(t, err)
+	task.Title = "Mutated"
+
+	assert.Equal(t, "Original", repo.tasks[1].Title)
+}
+
+func TestOutbox_EventsCommitWithTransaction(t *testing.T) {
+	repo := CreateTaskRepository()
+	ctx := context.Background()
+
+	err := repo.WithTx(ctx, func(tx app.TaskTx) error {
+		task, err := tx.CreateTask(ctx, &models.Task{ID: 1, Title: "Task"})
+		if err != nil {
+			return err
+		}
+		return tx.AddEvent(ctx, models.TaskEvent{Type: models.EventTaskCreated, TaskID: 1, Task: task})
+	})
+	require.NoError(t, err)
+
+	err = repo.WithTx(ctx, func(tx app.TaskTx) error {
+		if err := tx.AddEvent(ctx, models.TaskEvent{Type: models.EventTaskDeleted, TaskID: 1}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	select {
+	case <-repo.EventsAvailable():
+	default:
+		t.Fatal("expected outbox notification")
+	}
+
+	events, err := repo.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint64(1), events[0].ID)
+	assert.Equal(t, models.EventTaskCreated, events[0].Type)
+	assert.False(t, events[0].OccurredAt.IsZero())
+}
+
+func TestOutbox_AckEvents(t *testing.T) {
+	repo := CreateTaskRepository()
+	ctx := context.Background()
+
+	for i := range 3 {
+		err := repo.WithTx(ctx, func(tx app.TaskTx) error {
+			return tx.AddEvent(ctx, models.TaskEvent{Type: models.EventTaskDeleted, TaskID: int64(i)})
+		})
+		require.NoError(t, err)
+	}
+
+	events, err := repo.GetPendingEvents(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	require.NoError(t, repo.AckEvents(ctx, 2))
+	events, err = repo.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint64(3), events[0].ID)
+
+	var done app.TaskTx
+	require.NoError(t, repo.WithTx(ctx, func(tx app.TaskTx) error {
+		done = tx
+		return nil
+	}))
+	assert.ErrorIs(t, done.AddEvent(ctx, models.TaskEvent{}), errs.ErrTxDone)
+}
+
+func TestCountTasksByStatus(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Status: models.StatusPending}
+	repo.tasks[2] = &models.Task{ID: 2, Status: models.StatusPending}
+	repo.tasks[3] = &models.Task{ID: 3, Status: models.StatusCompleted}
+
+	assert.Equal(t, map[models.TaskStatus]int{
+		models.StatusPending:   2,
+		models.StatusCompleted: 1,
+	}, repo.CountTasksByStatus())
+}
+
+func TestWithTx_RestoreTask(t *testing.T) {
+	repo := CreateTaskRepository()
+	repo.tasks[1] = &models.Task{ID: 1, Title: "Existing", Status: models.StatusPending}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := repo.WithTx(context.Background(), func(tx app.TaskTx) error {
+		if _, err := tx.RestoreTask(context.Background(), &models.Task{ID: 1, Title: "Replaced", Status: models.StatusCompleted, CreatedAt: createdAt}); err != nil {
+			return err
+		}
+		if _, err := tx.RestoreTask(context.Background(), &models.Task{ID: 5, Title: "Restored"}); err != nil {
+			return err
+		}
+		_, err := tx.RestoreTask(context.Background(), &models.Task{ID: 0, Title: "No ID"})
+		assert.ErrorIs(t, err, errs.ErrInvalidID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Replaced", repo.tasks[1].Title)
+	assert.Equal(t, createdAt, repo.tasks[1].CreatedAt)
+	assert.Equal(t, createdAt, repo.tasks[1].UpdatedAt)
+	assert.WithinDuration(t, time.Now(), repo.tasks[5].CreatedAt, time.Second)
+	assert.Len(t, repo.tasks, 2)
+}
+
+func TestSnapshot_ConsistentDuringWrites(t *testing.T) {
+	repo := CreateTaskRepository()
+	ctx := context.Background()
+
+	// Every transaction adds two tasks, so a consistent snapshot never holds
+	// an odd number of them.
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				id := int64(i*1000 + j*2 + 1)
+				err := repo.WithTx(ctx, func(tx app.TaskTx) error {
+					if _, err := tx.CreateTask(ctx, &models.Task{ID: id, Title: "First"}); err != nil {
+						return err
+					}
+					_, err := tx.CreateTask(ctx, &models.Task{ID: id + 1, Title: "Second"})
+					return err
+				})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	for range 50 {
+		snapshot, err := repo.Snapshot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, len(snapshot.Tasks)%2)
+		for i := 1; i < len(snapshot.Tasks); i++ {
+			assert.Less(t, snapshot.Tasks[i-1].ID, snapshot.Tasks[i].ID)
+		}
+	}
+	wg.Wait()
+
+	snapshot, err := repo.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Tasks, 400)
+
+	snapshot.Tasks[0].Title = "Changed"
+	assert.Equal(t, "First", repo.tasks[snapshot.Tasks[0].ID].Title)
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	source := CreateTaskRepository()
+	_, err := source.CreateTask(ctx, &models.Task{ID: 1, Title: "First"})
+	require.NoError(t, err)
+	err = source.WithTx(ctx, func(tx app.TaskTx) error {
+		return tx.AddEvent(ctx, models.TaskEvent{Type: models.EventTaskCreated, TaskID: 1})
+	})
+	require.NoError(t, err)
+
+	snapshot, err := source.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), snapshot.LastEventID)
+
+	target := CreateTaskRepository()
+	require.NoError(t, target.Restore(ctx, snapshot))
+
+	task, err := target.GetTaskByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Tasks[0], task)
+	page, err := target.GetTasksPage(ctx, "", 0, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Tasks, page)
+
+	err = target.WithTx(ctx, func(tx app.TaskTx) error {
+		return tx.AddEvent(ctx, models.TaskEvent{Type: models.EventTaskDeleted, TaskID: 1})
+	})
+	require.NoError(t, err)
+	events, err := target.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint64(2), events[0].ID)
+
+	assert.ErrorIs(t, target.Restore(ctx, snapshot), errs.ErrStoreNotEmpty)
+	assert.ErrorIs(t, CreateTaskRepository().Restore(ctx, &models.Snapshot{Tasks: []*models.Task{{ID: 0}}}), errs.ErrInvalidID)
 }
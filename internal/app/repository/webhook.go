@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+const (
+	MaxDeliveryLogsPerWebhook = 100
+	MaxDeadLetters            = 1000
+)
+
+type WebhookRepository struct {
+	webhooks     map[int64]*models.Webhook
+	deliveryLogs map[int64][]models.WebhookDeliveryLog
+	deadLetters  []models.DeadLetter
+	mu           sync.RWMutex
+}
+
+func CreateWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:     make(map[int64]*models.Webhook),
+		deliveryLogs: make(map[int64][]models.WebhookDeliveryLog),
+	}
+}
+
+func cloneWebhook(webhook *models.Webhook) *models.Webhook {
+	clone := *webhook
+	clone.EventTypes = slices.Clone(webhook.EventTypes)
+	return &clone
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	const funcName = "Repository.CreateWebhook"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if webhook.ID < 0 {
+		span.RecordError(errs.ErrInvalidID)
+		logger.ErrorContext(ctx, "invalid webhook ID", errs.ErrInvalidID, map[string]any{
+			"webhook_id": webhook.ID,
+			"method":     funcName,
+		})
+		return nil, errs.ErrInvalidID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := cloneWebhook(webhook)
+	now := time.Now()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	r.webhooks[created.ID] = created
+
+	logger.InfoContext(ctx, "webhook created", map[string]any{
+		"webhook_id": created.ID,
+		"method":     funcName,
+	})
+
+	return cloneWebhook(created), nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	const funcName = "Repository.GetWebhookByID"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		span.RecordError(errs.ErrWebhookNotFound)
+		logger.ErrorContext(ctx, "webhook not found", errs.ErrWebhookNotFound, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	return cloneWebhook(webhook), nil
+}
+
+func (r *WebhookRepository) GetAllWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := []*models.Webhook{}
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, cloneWebhook(webhook))
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	const funcName = "Repository.UpdateWebhook"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.webhooks[webhook.ID]
+	if !exists {
+		span.RecordError(errs.ErrWebhookNotFound)
+		logger.ErrorContext(ctx, "webhook not found for update", errs.ErrWebhookNotFound, map[string]any{
+			"webhook_id": webhook.ID,
+			"method":     funcName,
+		})
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	updated := cloneWebhook(webhook)
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+
+	r.webhooks[updated.ID] = updated
+
+	logger.InfoContext(ctx, "webhook updated", map[string]any{
+		"webhook_id": updated.ID,
+		"method":     funcName,
+	})
+
+	return cloneWebhook(updated), nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	const funcName = "Repository.DeleteWebhook"
+
+	_, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[id]; !exists {
+		span.RecordError(errs.ErrWebhookNotFound)
+		logger.ErrorContext(ctx, "webhook not found for deletion", errs.ErrWebhookNotFound, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return errs.ErrWebhookNotFound
+	}
+
+	delete(r.webhooks, id)
+	delete(r.deliveryLogs, id)
+
+	logger.InfoContext(ctx, "webhook deleted", map[string]any{
+		"webhook_id": id,
+		"method":     funcName,
+	})
+
+	return nil
+}
+
+func (r *WebhookRepository) AddDeliveryLog(ctx context.Context, log models.WebhookDeliveryLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[log.WebhookID]; !exists {
+		return errs.ErrWebhookNotFound
+	}
+
+	logs := append(r.deliveryLogs[log.WebhookID], log)
+	if len(logs) > MaxDeliveryLogsPerWebhook {
+		logs = logs[len(logs)-MaxDeliveryLogsPerWebhook:]
+	}
+	r.deliveryLogs[log.WebhookID] = logs
+
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.webhooks[webhookID]; !exists {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	logs := make([]models.WebhookDeliveryLog, len(r.deliveryLogs[webhookID]))
+	copy(logs, r.deliveryLogs[webhookID])
+
+	return logs, nil
+}
+
+func (r *WebhookRepository) AddDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deadLetters = append(r.deadLetters, letter)
+	if len(r.deadLetters) > MaxDeadLetters {
+		r.deadLetters = r.deadLetters[len(r.deadLetters)-MaxDeadLetters:]
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	letters := make([]models.DeadLetter, len(r.deadLetters))
+	copy(letters, r.deadLetters)
+
+	return letters, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestWebhookRepository_CRUD(t *testing.T) {
+	repo := CreateWebhookRepository()
+	ctx := context.Background()
+
+	created, err := repo.CreateWebhook(ctx, &models.Webhook{
+		ID:         1,
+		URL:        "http://example.com/hook",
+		EventTypes: []models.TaskEventType{models.EventTaskCreated},
+		Secret:     "0123456789abcdef",
+	})
+	require.NoError(t, err)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	created.EventTypes[0] = models.EventTaskDeleted
+	stored, err := repo.GetWebhookByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TaskEventType{models.EventTaskCreated}, stored.EventTypes)
+
+	stored.URL = "https://example.com/other"
+	updated, err := repo.UpdateWebhook(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", updated.URL)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+
+	webhooks, err := repo.GetAllWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+
+	require.NoError(t, repo.DeleteWebhook(ctx, 1))
+	_, err = repo.GetWebhookByID(ctx, 1)
+	assert.ErrorIs(t, err, errs.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.DeleteWebhook(ctx, 1), errs.ErrWebhookNotFound)
+	_, err = repo.UpdateWebhook(ctx, stored)
+	assert.ErrorIs(t, err, errs.ErrWebhookNotFound)
+}
+
+func TestWebhookRepository_DeliveryLogsAreBounded(t *testing.T) {
+	repo := CreateWebhookRepository()
+	ctx := context.Background()
+
+	_, err := repo.CreateWebhook(ctx, &models.Webhook{ID: 1, URL: "http://example.com"})
+	require.NoError(t, err)
+
+	for i := range MaxDeliveryLogsPerWebhook + 5 {
+		require.NoError(t, repo.AddDeliveryLog(ctx, models.WebhookDeliveryLog{WebhookID: 1, EventID: uint64(i + 1)}))
+	}
+
+	logs, err := repo.GetDeliveryLogs(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, logs, MaxDeliveryLogsPerWebhook)
+	assert.Equal(t, uint64(6), logs[0].EventID)
+
+	assert.ErrorIs(t, repo.AddDeliveryLog(ctx, models.WebhookDeliveryLog{WebhookID: 2}), errs.ErrWebhookNotFound)
+	_, err = repo.GetDeliveryLogs(ctx, 2)
+	assert.ErrorIs(t, err, errs.ErrWebhookNotFound)
+
+	require.NoError(t, repo.DeleteWebhook(ctx, 1))
+	_, err = repo.CreateWebhook(ctx, &models.Webhook{ID: 1, URL: "http://example.com"})
+	require.NoError(t, err)
+	logs, err = repo.GetDeliveryLogs(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestWebhookRepository_DeadLetters(t *testing.T) {
+	repo := CreateWebhookRepository()
+	ctx := context.Background()
+
+	for i := range MaxDeadLetters + 1 {
+		require.NoError(t, repo.AddDeadLetter(ctx, models.DeadLetter{Event: models.TaskEvent{ID: uint64(i + 1)}}))
+	}
+
+	letters, err := repo.GetDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Len(t, letters, MaxDeadLetters)
+	assert.Equal(t, uint64(2), letters[0].Event.ID)
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+func (u *TaskUsecase) Snapshot(ctx context.Context) (*models.Snapshot, error) {
+	const funcName = "Usecase.Snapshot"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	snapshot, err := u.taskRepository.Snapshot(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to take snapshot", err, map[string]any{
+			"method": funcName,
+		})
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot checks every task like ImportTasks does and loads the
+// snapshot only if all of them pass. Unlike an import it records no events:
+// the tasks are not new, they are brought back.
+func (u *TaskUsecase) RestoreSnapshot(ctx context.Context, snapshot *models.Snapshot) error {
+	const funcName = "Usecase.RestoreSnapshot"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := validateSnapshot(snapshot); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid snapshot", err, map[string]any{
+			"method": funcName,
+		})
+		return err
+	}
+
+	if err := u.taskRepository.Restore(ctx, snapshot); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to restore snapshot", err, map[string]any{
+			"method": funcName,
+		})
+		return err
+	}
+
+	for _, task := range snapshot.Tasks {
+		u.reserveTaskID(task.ID)
+	}
+
+	logger.InfoContext(ctx, "snapshot restored", map[string]any{
+		"method":   funcName,
+		"count":    len(snapshot.Tasks),
+		"taken_at": snapshot.TakenAt,
+	})
+
+	return nil
+}
+
+func validateSnapshot(snapshot *models.Snapshot) error {
+	seen := make(map[int64]bool, len(snapshot.Tasks))
+	for _, task := range snapshot.Tasks {
+		if task.ID == 0 {
+			return fmt.Errorf("%w: task without ID", errs.ErrInvalidID)
+		}
+		if err := validateImportedTask(task); err != nil {
+			return fmt.Errorf("task %d: %w", task.ID, err)
+		}
+		if seen[task.ID] {
+			return fmt.Errorf("%w: duplicate task ID %d", errs.ErrValidation, task.ID)
+		}
+		seen[task.ID] = true
+	}
+
+	return nil
+}
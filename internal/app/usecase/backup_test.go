@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestTaskUsecase_Snapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	usecase := CreateTaskUsecase(mockRepo)
+
+	snapshot := &models.Snapshot{TakenAt: time.Now(), LastEventID: 3}
+	mockRepo.EXPECT().Snapshot(gomock.Any()).Return(snapshot, nil)
+	result, err := usecase.Snapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, result)
+
+	storageErr := errors.New("storage unavailable")
+	mockRepo.EXPECT().Snapshot(gomock.Any()).Return(nil, storageErr)
+	_, err = usecase.Snapshot(context.Background())
+	assert.ErrorIs(t, err, storageErr)
+}
+
+func TestTaskUsecase_RestoreSnapshot(t *testing.T) {
+	valid := []*models.Task{
+		{ID: 7, Title: "First task", Status: models.StatusPending},
+		{ID: 1_900_000_000_000_000, Title: "Second task", Status: models.StatusCompleted},
+	}
+
+	tests := []struct {
+		name          string
+		tasks         []*models.Task
+		restoreErr    error
+		expectRestore bool
+		expectedErr   error
+	}{
+		{
+			name:          "Success",
+			tasks:         valid,
+			expectRestore: true,
+		},
+		{
+			name:          "Store Not Empty",
+			tasks:         valid,
+			restoreErr:    errs.ErrStoreNotEmpty,
+			expectRestore: true,
+			expectedErr:   errs.ErrStoreNotEmpty,
+		},
+		{
+			name:        "Missing ID",
+			tasks:       []*models.Task{{Title: "No ID", Status: models.StatusPending}},
+			expectedErr: errs.ErrInvalidID,
+		},
+		{
+			name:        "Invalid Status",
+			tasks:       []*models.Task{{ID: 1, Title: "Some task", Status: "done"}},
+			expectedErr: errs.ErrValidation,
+		},
+		{
+			name:        "Invalid Title",
+			tasks:       []*models.Task{{ID: 1, Title: "x", Status: models.StatusPending}},
+			expectedErr: errs.ErrValidation,
+		},
+		{
+			name:        "Duplicate ID",
+			tasks:       []*models.Task{valid[0], valid[0]},
+			expectedErr: errs.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			usecase := CreateTaskUsecase(mockRepo)
+			snapshot := &models.Snapshot{Tasks: tt.tasks}
+			if tt.expectRestore {
+				mockRepo.EXPECT().Restore(gomock.Any(), snapshot).Return(tt.restoreErr)
+			}
+
+			err := usecase.RestoreSnapshot(context.Background(), snapshot)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// IDs handed out after the restore do not collide with restored ones.
+			assert.Greater(t, usecase.nextTaskID(time.Now()), valid[1].ID)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+	"github.com/supchaser/LO_test_task/internal/utils/validate"
+)
+
+const MaxBatchSize = 1000
+
+func checkBatchSize(size int) error {
+	if size == 0 {
+		return fmt.Errorf("%w: batch cannot be empty", errs.ErrValidation)
+	}
+
+	if size > MaxBatchSize {
+		return fmt.Errorf("%w: got %d items, limit is %d", errs.ErrBatchTooLarge, size, MaxBatchSize)
+	}
+
+	return nil
+}
+
+func (u *TaskUsecase) BatchCreateTasks(ctx context.Context, items []models.CreateTaskRequest, atomic bool) ([]models.BatchItemResult, error) {
+	const funcName = "Usecase.BatchCreateTasks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := checkBatchSize(len(items)); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid batch size", err, map[string]any{
+			"method": funcName,
+			"count":  len(items),
+		})
+		return nil, err
+	}
+
+	results := make([]models.BatchItemResult, len(items))
+
+	if !atomic {
+		for i, item := range items {
+			task, err := u.CreateTask(ctx, item.Title, item.Description)
+			results[i] = batchResult(i, task, err)
+		}
+		logBatchResults(ctx, funcName, results, atomic)
+		return results, nil
+	}
+
+	for i, item := range items {
+		if err := validateNewTask(item.Title, item.Description); err != nil {
+			return abortBatch(ctx, funcName, results, i, err), nil
+		}
+	}
+
+	return u.runAtomicBatch(ctx, funcName, results, func(tx app.TaskTx, i int) (*models.Task, error) {
+		return createInTx(ctx, tx, u.newTask(items[i].Title, items[i].Description))
+	}), nil
+}
+
+func (u *TaskUsecase) BatchUpdateTasks(ctx context.Context, items []models.BatchUpdateItem, atomic bool) ([]models.BatchItemResult, error) {
+	const funcName = "Usecase.BatchUpdateTasks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := checkBatchSize(len(items)); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid batch size", err, map[string]any{
+			"method": funcName,
+			"count":  len(items),
+		})
+		return nil, err
+	}
+
+	results := make([]models.BatchItemResult, len(items))
+
+	if !atomic {
+		for i, item := range items {
+			task, err := u.UpdateTask(ctx, item.ID, item.Title, item.Description, item.Status)
+			results[i] = batchResult(i, task, err)
+			results[i].ID = item.ID
+		}
+		logBatchResults(ctx, funcName, results, atomic)
+		return results, nil
+	}
+
+	return u.runAtomicBatch(ctx, funcName, results, func(tx app.TaskTx, i int) (*models.Task, error) {
+		results[i].ID = items[i].ID
+		return updateInTx(ctx, tx, items[i].ID, items[i].Title, items[i].Description, items[i].Status)
+	}), nil
+}
+
+func (u *TaskUsecase) BatchDeleteTasks(ctx context.Context, ids []int64, atomic bool) ([]models.BatchItemResult, error) {
+	const funcName = "Usecase.BatchDeleteTasks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := checkBatchSize(len(ids)); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid batch size", err, map[string]any{
+			"method": funcName,
+			"count":  len(ids),
+		})
+		return nil, err
+	}
+
+	results := make([]models.BatchItemResult, len(ids))
+
+	if !atomic {
+		for i, id := range ids {
+			results[i] = models.BatchItemResult{
+				Index: i,
+				ID:    id,
+				Err:   u.DeleteTask(ctx, id),
+			}
+		}
+		logBatchResults(ctx, funcName, results, atomic)
+		return results, nil
+	}
+
+	return u.runAtomicBatch(ctx, funcName, results, func(tx app.TaskTx, i int) (*models.Task, error) {
+		results[i].ID = ids[i]
+		return nil, deleteInTx(ctx, tx, ids[i])
+	}), nil
+}
+
+func (u *TaskUsecase) runAtomicBatch(ctx context.Context, funcName string, results []models.BatchItemResult, apply func(tx app.TaskTx, i int) (*models.Task, error)) []models.BatchItemResult {
+	err := u.taskRepository.WithTx(ctx, func(tx app.TaskTx) error {
+		for i := range results {
+			results[i].Index = i
+			task, err := apply(tx, i)
+			if err != nil {
+				return &errs.BatchError{Index: i, Err: err}
+			}
+			results[i].Task = task
+		}
+		return nil
+	})
+	if err != nil {
+		var batchErr *errs.BatchError
+		if errors.As(err, &batchErr) {
+			return abortBatch(ctx, funcName, results, batchErr.Index, batchErr.Err)
+		}
+
+		logger.ErrorContext(ctx, "failed to apply batch", err, map[string]any{
+			"method": funcName,
+			"count":  len(results),
+		})
+		for i := range results {
+			results[i] = models.BatchItemResult{Index: i, ID: results[i].ID, Err: err}
+		}
+		return results
+	}
+
+	for i := range results {
+		if results[i].Task != nil {
+			results[i].ID = results[i].Task.ID
+		}
+	}
+	logBatchResults(ctx, funcName, results, true)
+
+	return results
+}
+
+func abortBatch(ctx context.Context, funcName string, results []models.BatchItemResult, failedIndex int, err error) []models.BatchItemResult {
+	logger.ErrorContext(ctx, "batch aborted", err, map[string]any{
+		"method": funcName,
+		"index":  failedIndex,
+		"count":  len(results),
+	})
+
+	for i := range results {
+		results[i] = models.BatchItemResult{Index: i, ID: results[i].ID, Err: errs.ErrBatchAborted}
+	}
+	results[failedIndex].Err = err
+
+	return results
+}
+
+func batchResult(index int, task *models.Task, err error) models.BatchItemResult {
+	result := models.BatchItemResult{Index: index, Task: task, Err: err}
+	if task != nil {
+		result.ID = task.ID
+	}
+	return result
+}
+
+func logBatchResults(ctx context.Context, funcName string, results []models.BatchItemResult, atomic bool) {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	logger.InfoContext(ctx, "batch processed", map[string]any{
+		"method":    funcName,
+		"atomic":    atomic,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	})
+}
+
+func validateNewTask(title, description string) error {
+	if err := validate.CheckTaskTitle(title); err != nil {
+		return err
+	}
+
+	return validate.CheckTaskDescription(description)
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+func TestTaskUsecase_BatchCreateTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		items         []models.CreateTaskRequest
+		atomic        bool
+		mockSetup     func(*mock_app.MockTaskRepository, *mock_app.MockTaskTx)
+		expectedErr   error
+		expectedItems []error
+	}{
+		{
+			name:        "Empty Batch",
+			items:       nil,
+			mockSetup:   func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {},
+			expectedErr: errs.ErrValidation,
+		},
+		{
+			name:        "Batch Too Large",
+			items:       make([]models.CreateTaskRequest, MaxBatchSize+1),
+			mockSetup:   func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {},
+			expectedErr: errs.ErrBatchTooLarge,
+		},
+		{
+			name: "Partial Success",
+			items: []models.CreateTaskRequest{
+				{Title: "Valid Title"},
+				{Title: ""},
+			},
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+						return task, nil
+					})
+			},
+			expectedItems: []error{nil, errs.ErrValidation},
+		},
+		{
+			name:   "Atomic Validation Failure",
+			atomic: true,
+			items: []models.CreateTaskRequest{
+				{Title: "Valid Title"},
+				{Title: ""},
+			},
+			mockSetup:     func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {},
+			expectedItems: []error{errs.ErrBatchAborted, errs.ErrValidation},
+		},
+		{
+			name:   "Atomic Success",
+			atomic: true,
+			items: []models.CreateTaskRequest{
+				{Title: "First Title"},
+				{Title: "Second Title"},
+			},
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				var ids []int64
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+						assert.NotContains(t, ids, task.ID)
+						ids = append(ids, task.ID)
+						return task, nil
+					}).
+					Times(2)
+			},
+			expectedItems: []error{nil, nil},
+		},
+		{
+			name:   "Atomic Repository Error",
+			atomic: true,
+			items: []models.CreateTaskRequest{
+				{Title: "First Title"},
+			},
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				mockRepo.EXPECT().
+					WithTx(gomock.Any(), gomock.Any()).
+					Return(errors.New("repository error"))
+			},
+			expectedItems: []error{errors.New("repository error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
+			tt.mockSetup(mockRepo, mockTx)
+
+			uc := CreateTaskUsecase(mockRepo)
+			results, err := uc.BatchCreateTasks(context.Background(), tt.items, tt.atomic)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assertBatchErrors(t, tt.expectedItems, results)
+		})
+	}
+}
+
+func TestTaskUsecase_BatchUpdateTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		items         []models.BatchUpdateItem
+		atomic        bool
+		mockSetup     func(*mock_app.MockTaskRepository, *mock_app.MockTaskTx)
+		expectedItems []error
+	}{
+		{
+			name: "Partial Success",
+			items: []models.BatchUpdateItem{
+				{ID: 1, Status: models.StatusCompleted},
+				{ID: 2, Status: models.StatusCompleted},
+			},
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					GetTaskByID(gomock.Any(), int64(1)).
+					Return(&models.Task{ID: 1, Title: "Task"}, nil)
+				mockTx.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+						return task, nil
+					})
+				mockRepo.EXPECT().
+					WithTx(gomock.Any(), gomock.Any()).
+					Return(errs.ErrTaskNotFound)
+			},
+			expectedItems: []error{nil, errs.ErrTaskNotFound},
+		},
+		{
+			name:   "Atomic Rollback",
+			atomic: true,
+			items: []models.BatchUpdateItem{
+				{ID: 1, Status: models.StatusCompleted},
+				{ID: 2, Status: models.StatusCompleted},
+			},
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					GetTaskByID(gomock.Any(), int64(1)).
+					Return(&models.Task{ID: 1, Title: "Task"}, nil)
+				mockTx.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+						return task, nil
+					})
+				mockTx.EXPECT().
+					GetTaskByID(gomock.Any(), int64(2)).
+					Return(nil, errs.ErrTaskNotFound)
+			},
+			expectedItems: []error{errs.ErrBatchAborted, errs.ErrTaskNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
+			tt.mockSetup(mockRepo, mockTx)
+
+			uc := CreateTaskUsecase(mockRepo)
+			results, err := uc.BatchUpdateTasks(context.Background(), tt.items, tt.atomic)
+
+			assert.NoError(t, err)
+			assertBatchErrors(t, tt.expectedItems, results)
+		})
+	}
+}
+
+func TestTaskUsecase_BatchDeleteTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	mockTx := mock_app.NewMockTaskTx(ctrl)
+	expectTx(mockRepo, mockTx)
+	expectTx(mockRepo, mockTx)
+	mockTx.EXPECT().DeleteTask(gomock.Any(), int64(1)).Return(nil)
+	mockTx.EXPECT().DeleteTask(gomock.Any(), int64(2)).Return(errs.ErrTaskNotFound)
+
+	uc := CreateTaskUsecase(mockRepo)
+	results, err := uc.BatchDeleteTasks(context.Background(), []int64{1, 2}, false)
+
+	assert.NoError(t, err)
+	assertBatchErrors(t, []error{nil, errs.ErrTaskNotFound}, results)
+	assert.Equal(t, int64(2), results[1].ID)
+}
+
+func assertBatchErrors(t *testing.T, expected []error, results []models.BatchItemResult) {
+	t.Helper()
+
+	assert.Len(t, results, len(expected))
+	for i, expectedErr := range expected {
+		assert.Equal(t, i, results[i].Index)
+		if expectedErr == nil {
+			assert.NoError(t, results[i].Err)
+			continue
+		}
+		if !errors.Is(results[i].Err, expectedErr) {
+			assert.EqualError(t, results[i].Err, expectedErr.Error())
+		}
+	}
+}
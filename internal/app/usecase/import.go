@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+	"github.com/supchaser/LO_test_task/internal/utils/validate"
+)
+
+// ImportTasks validates every item and applies the valid ones in a single
+// transaction; invalid items are reported without stopping the import. Items
+// keep their IDs and timestamps, items without an ID get a new one. With
+// DryRun set nothing is stored, but the results are the same as they would
+// have been.
+func (u *TaskUsecase) ImportTasks(ctx context.Context, items []models.ImportItem, opts models.ImportOptions) ([]models.ImportItemResult, error) {
+	const funcName = "Usecase.ImportTasks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if opts.OnConflict == "" {
+		opts.OnConflict = models.ImportSkip
+	}
+	if opts.OnConflict != models.ImportSkip && opts.OnConflict != models.ImportUpsert {
+		err := fmt.Errorf("%w: unknown conflict policy %q", errs.ErrValidation, opts.OnConflict)
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid import options", err, map[string]any{
+			"method": funcName,
+		})
+		return nil, err
+	}
+
+	results := make([]models.ImportItemResult, len(items))
+	err := u.taskRepository.WithTx(ctx, func(tx app.TaskTx) error {
+		existing, err := tx.GetAllTasks(ctx, "")
+		if err != nil {
+			return err
+		}
+		byID := make(map[int64]*models.Task, len(existing))
+		for _, task := range existing {
+			byID[task.ID] = task
+		}
+
+		for i, item := range items {
+			results[i] = u.importItem(ctx, tx, byID, item, opts)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to import tasks", err, map[string]any{
+			"method": funcName,
+			"count":  len(items),
+		})
+		return nil, err
+	}
+
+	counts := make(map[models.ImportAction]int)
+	for _, result := range results {
+		counts[result.Action]++
+	}
+	logger.InfoContext(ctx, "tasks imported", map[string]any{
+		"method":      funcName,
+		"dry_run":     opts.DryRun,
+		"on_conflict": opts.OnConflict,
+		"created":     counts[models.ImportCreated],
+		"updated":     counts[models.ImportUpdated],
+		"skipped":     counts[models.ImportSkipped],
+		"failed":      counts[models.ImportFailed],
+	})
+
+	return results, nil
+}
+
+// importItem handles one item; byID holds the tasks as the transaction sees
+// them and is kept up to date, so duplicate IDs within one file conflict too.
+func (u *TaskUsecase) importItem(ctx context.Context, tx app.TaskTx, byID map[int64]*models.Task, item models.ImportItem, opts models.ImportOptions) models.ImportItemResult {
+	result := models.ImportItemResult{Row: item.Row, ID: item.Task.ID}
+	fail := func(err error) models.ImportItemResult {
+		result.Action = models.ImportFailed
+		result.Err = err
+		return result
+	}
+
+	task := item.Task
+	if task.Status == "" {
+		task.Status = models.StatusPending
+	}
+	if err := validateImportedTask(&task); err != nil {
+		return fail(err)
+	}
+
+	current, exists := byID[task.ID]
+	switch {
+	case exists && opts.OnConflict == models.ImportSkip:
+		result.Action = models.ImportSkipped
+		return result
+	case exists:
+		result.Action = models.ImportUpdated
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = current.CreatedAt
+		}
+		if task.UpdatedAt.IsZero() {
+			task.UpdatedAt = time.Now()
+		}
+	default:
+		result.Action = models.ImportCreated
+	}
+
+	if opts.DryRun {
+		if task.ID != 0 {
+			byID[task.ID] = &task
+		}
+		return result
+	}
+
+	if task.ID == 0 {
+		task.ID = u.nextTaskID(time.Now())
+		result.ID = task.ID
+	} else {
+		u.reserveTaskID(task.ID)
+	}
+
+	restored, err := tx.RestoreTask(ctx, &task)
+	if err != nil {
+		return fail(err)
+	}
+	byID[restored.ID] = restored
+
+	if result.Action == models.ImportCreated {
+		err = tx.AddEvent(ctx, models.TaskEvent{
+			Type:   models.EventTaskCreated,
+			TaskID: restored.ID,
+			Task:   restored,
+		})
+	} else {
+		err = addUpdateEvents(ctx, tx, restored, current.Status)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	return result
+}
+
+// validateImportedTask applies the rules of CreateTask to an imported task;
+// an ID of 0 means "assign a new one".
+func validateImportedTask(task *models.Task) error {
+	if task.ID < 0 || task.ID > validate.MaxTaskID {
+		return fmt.Errorf("%w: %d is not between 1 and %d", errs.ErrInvalidID, task.ID, validate.MaxTaskID)
+	}
+
+	if err := validateNewTask(task.Title, task.Description); err != nil {
+		return err
+	}
+
+	return validate.CheckTaskStatus(task.Status)
+}
+
+// reserveTaskID makes sure IDs handed out later do not collide with an
+// imported one.
+func (u *TaskUsecase) reserveTaskID(id int64) {
+	for {
+		last := u.lastID.Load()
+		if id <= last || u.lastID.CompareAndSwap(last, id) {
+			return
+		}
+	}
+}
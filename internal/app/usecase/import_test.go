@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/validate"
+)
+
+func TestTaskUsecase_ImportTasks(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	existing := &models.Task{ID: 10, Title: "Existing task", Status: models.StatusPending, CreatedAt: createdAt}
+
+	items := []models.ImportItem{
+		{Row: 1, Task: models.Task{ID: 10, Title: "Changed title", Status: models.StatusCompleted}},
+		{Row: 2, Task: models.Task{ID: 20, Title: "Imported task", Status: models.StatusInProgress, CreatedAt: createdAt}},
+		{Row: 3, Task: models.Task{Title: "Without ID"}},
+		{Row: 4, Task: models.Task{ID: 30, Title: "x"}},
+		{Row: 5, Task: models.Task{ID: 40, Title: "Bad status", Status: "done"}},
+		{Row: 6, Task: models.Task{ID: -1, Title: "Negative ID"}},
+		{Row: 7, Task: models.Task{ID: 20, Title: "Duplicate in file"}},
+	}
+
+	tests := []struct {
+		name            string
+		opts            models.ImportOptions
+		expectedActions []models.ImportAction
+		expectedRestore int
+	}{
+		{
+			name: "Skip Conflicts",
+			opts: models.ImportOptions{OnConflict: models.ImportSkip},
+			expectedActions: []models.ImportAction{
+				models.ImportSkipped, models.ImportCreated, models.ImportCreated,
+				models.ImportFailed, models.ImportFailed, models.ImportFailed, models.ImportSkipped,
+			},
+			expectedRestore: 2,
+		},
+		{
+			name: "Upsert Conflicts",
+			opts: models.ImportOptions{OnConflict: models.ImportUpsert},
+			expectedActions: []models.ImportAction{
+				models.ImportUpdated, models.ImportCreated, models.ImportCreated,
+				models.ImportFailed, models.ImportFailed, models.ImportFailed, models.ImportUpdated,
+			},
+			expectedRestore: 4,
+		},
+		{
+			name: "Dry Run",
+			opts: models.ImportOptions{OnConflict: models.ImportUpsert, DryRun: true},
+			expectedActions: []models.ImportAction{
+				models.ImportUpdated, models.ImportCreated, models.ImportCreated,
+				models.ImportFailed, models.ImportFailed, models.ImportFailed, models.ImportUpdated,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
+			expectTx(mockRepo, mockTx)
+			mockTx.EXPECT().GetAllTasks(gomock.Any(), models.TaskStatus("")).Return([]*models.Task{existing}, nil)
+
+			var restored []*models.Task
+			mockTx.EXPECT().
+				RestoreTask(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+					restored = append(restored, task)
+					return task, nil
+				}).
+				Times(tt.expectedRestore)
+
+			usecase := CreateTaskUsecase(mockRepo)
+			results, err := usecase.ImportTasks(context.Background(), items, tt.opts)
+			require.NoError(t, err)
+			require.Len(t, results, len(items))
+
+			for i, result := range results {
+				assert.Equal(t, items[i].Row, result.Row)
+				assert.Equal(t, tt.expectedActions[i], result.Action, "row %d", result.Row)
+				assert.Equal(t, result.Action == models.ImportFailed, result.Err != nil, "row %d", result.Row)
+			}
+			assert.ErrorIs(t, results[3].Err, errs.ErrValidation)
+			assert.ErrorIs(t, results[4].Err, errs.ErrValidation)
+			assert.ErrorIs(t, results[5].Err, errs.ErrInvalidID)
+
+			if tt.opts.DryRun {
+				assert.Zero(t, results[2].ID)
+				return
+			}
+
+			assert.NotZero(t, results[2].ID)
+			byID := make(map[int64]*models.Task)
+			for _, task := range restored {
+				byID[task.ID] = task
+			}
+			assert.Equal(t, createdAt, byID[20].CreatedAt)
+			assert.Equal(t, models.StatusPending, byID[results[2].ID].Status)
+			if tt.opts.OnConflict == models.ImportUpsert {
+				assert.Equal(t, "Changed title", byID[10].Title)
+				assert.Equal(t, createdAt, byID[10].CreatedAt)
+				assert.Equal(t, "Duplicate in file", byID[20].Title)
+			}
+
+			// IDs handed out after the import do not collide with imported ones.
+			assert.Greater(t, usecase.nextTaskID(time.UnixMilli(0)), int64(20))
+		})
+	}
+}
+
+func TestTaskUsecase_ImportTasksErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	usecase := CreateTaskUsecase(mockRepo)
+
+	_, err := usecase.ImportTasks(context.Background(), nil, models.ImportOptions{OnConflict: "merge"})
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	storageErr := errors.New("storage unavailable")
+	mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).Return(storageErr)
+	_, err = usecase.ImportTasks(context.Background(), []models.ImportItem{{Row: 1, Task: models.Task{Title: "Some task"}}}, models.ImportOptions{})
+	assert.ErrorIs(t, err, storageErr)
+}
+
+func TestTaskUsecase_ImportTasksRecordsEvents(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	usecase := CreateTaskUsecase(repo)
+	ctx := context.Background()
+
+	task, err := usecase.CreateTask(ctx, "Existing task", "")
+	require.NoError(t, err)
+	pending, err := repo.GetPendingEvents(ctx, 100)
+	require.NoError(t, err)
+	require.NoError(t, repo.AckEvents(ctx, pending[len(pending)-1].ID))
+
+	_, err = usecase.ImportTasks(ctx, []models.ImportItem{
+		{Row: 1, Task: models.Task{ID: task.ID, Title: "Existing task", Status: models.StatusCompleted}},
+		{Row: 2, Task: models.Task{ID: 5, Title: "New task"}},
+	}, models.ImportOptions{OnConflict: models.ImportUpsert})
+	require.NoError(t, err)
+
+	events, err := repo.GetPendingEvents(ctx, 100)
+	require.NoError(t, err)
+	var types []models.TaskEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []models.TaskEventType{models.EventTaskUpdated, models.EventTaskStatusChanged, models.EventTaskCreated}, types)
+	assert.Equal(t, models.StatusPending, events[1].PreviousStatus)
+}
+
+func TestTaskUsecase_ImportTasksRejectsHugeID(t *testing.T) {
+	repo := repository.CreateTaskRepository()
+	usecase := CreateTaskUsecase(repo)
+	ctx := context.Background()
+
+	results, err := usecase.ImportTasks(ctx, []models.ImportItem{
+		{Row: 1, Task: models.Task{ID: math.MaxInt64, Title: "Huge ID", Status: models.StatusPending}},
+		{Row: 2, Task: models.Task{ID: validate.MaxTaskID, Title: "Largest ID", Status: models.StatusPending}},
+	}, models.ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportFailed, results[0].Action)
+	assert.Equal(t, models.ImportCreated, results[1].Action)
+
+	task, err := usecase.CreateTask(ctx, "Created after import", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(validate.MaxTaskID+1), task.ID)
+}
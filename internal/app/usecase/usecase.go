@@ -2,16 +2,21 @@ package usecase
 
 import (
 	"context"
+	"iter"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/supchaser/LO_test_task/internal/app"
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
 	"github.com/supchaser/LO_test_task/internal/utils/validate"
 )
 
 type TaskUsecase struct {
 	taskRepository app.TaskRepository
+	lastID         atomic.Int64
 }
 
 func CreateTaskUsecase(taskRepository app.TaskRepository) *TaskUsecase {
@@ -23,8 +28,12 @@ func CreateTaskUsecase(taskRepository app.TaskRepository) *TaskUsecase {
 func (u *TaskUsecase) CreateTask(ctx context.Context, title, description string) (*models.Task, error) {
 	const funcName = "Usecase.CreateTask"
 
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
 	if err := validate.CheckTaskTitle(title); err != nil {
-		logger.Error("invalid task title", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid task title", err, map[string]any{
 			"method": funcName,
 			"title":  title,
 		})
@@ -32,32 +41,32 @@ func (u *TaskUsecase) CreateTask(ctx context.Context, title, description string)
 	}
 
 	if err := validate.CheckTaskDescription(description); err != nil {
-		logger.Error("invalid task description", err, map[string]any{
-			"method":      funcName,
-			"description": description,
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid task description", err, map[string]any{
+			"method":             funcName,
+			"description_length": utf8.RuneCountInString(description),
 		})
 		return nil, err
 	}
 
-	task := &models.Task{
-		ID:          time.Now().UnixNano() / int64(time.Millisecond),
-		Title:       title,
-		Description: description,
-		Status:      models.StatusPending,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
+	task := u.newTask(title, description)
 
-	createdTask, err := u.taskRepository.CreateTask(ctx, task)
+	var createdTask *models.Task
+	err := u.taskRepository.WithTx(ctx, func(tx app.TaskTx) error {
+		var err error
+		createdTask, err = createInTx(ctx, tx, task)
+		return err
+	})
 	if err != nil {
-		logger.Error("failed to create task in repository", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to create task in repository", err, map[string]any{
 			"task_id": task.ID,
 			"method":  funcName,
 		})
 		return nil, err
 	}
 
-	logger.Info("task created successfully", map[string]any{
+	logger.InfoContext(ctx, "task created successfully", map[string]any{
 		"task_id": createdTask.ID,
 		"method":  funcName,
 	})
@@ -65,19 +74,50 @@ func (u *TaskUsecase) CreateTask(ctx context.Context, title, description string)
 	return createdTask, nil
 }
 
+func (u *TaskUsecase) newTask(title, description string) *models.Task {
+	now := time.Now()
+	return &models.Task{
+		ID:          u.nextTaskID(now),
+		Title:       title,
+		Description: description,
+		Status:      models.StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// nextTaskID keeps millisecond timestamps as IDs but never hands out the same
+// value twice, which matters when many tasks are created within one millisecond.
+func (u *TaskUsecase) nextTaskID(now time.Time) int64 {
+	for {
+		last := u.lastID.Load()
+		id := now.UnixMilli()
+		if id <= last {
+			id = last + 1
+		}
+		if u.lastID.CompareAndSwap(last, id) {
+			return id
+		}
+	}
+}
+
 func (u *TaskUsecase) GetTask(ctx context.Context, id int64) (*models.Task, error) {
 	const funcName = "Usecase.GetTask"
 
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
 	task, err := u.taskRepository.GetTaskByID(ctx, id)
 	if err != nil {
-		logger.Error("failed to get task", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to get task", err, map[string]any{
 			"task_id": id,
 			"method":  funcName,
 		})
 		return nil, err
 	}
 
-	logger.Info("task retrieved", map[string]any{
+	logger.InfoContext(ctx, "task retrieved", map[string]any{
 		"task_id": task.ID,
 		"method":  funcName,
 	})
@@ -85,101 +125,234 @@ func (u *TaskUsecase) GetTask(ctx context.Context, id int64) (*models.Task, erro
 	return task, nil
 }
 
-func (u *TaskUsecase) ListTasks(ctx context.Context, statusFilter models.TaskStatus) ([]*models.Task, error) {
+// listPageSize is how many tasks ListTasks reads from the repository at a time.
+const listPageSize = 500
+
+// ListTasks reads the tasks page by page, each page under a short read lock,
+// so neither the repository nor the caller holds the whole listing. Pages
+// continue after the last ID seen: tasks written while the sequence is being
+// consumed may or may not show up, but no task is yielded twice.
+func (u *TaskUsecase) ListTasks(ctx context.Context, statusFilter models.TaskStatus, offset, limit int) iter.Seq2[*models.Task, error] {
 	const funcName = "Usecase.ListTasks"
 
-	tasks, err := u.taskRepository.GetAllTasks(ctx, statusFilter)
+	return func(yield func(*models.Task, error) bool) {
+		ctx, span := tracing.Start(ctx, funcName)
+		defer span.End()
+
+		var afterID int64
+		skip := offset
+		listed := 0
+		for limit == 0 || listed < limit {
+			pageSize := listPageSize
+			if limit > 0 {
+				pageSize = min(pageSize, limit-listed)
+			}
+
+			page, err := u.taskRepository.GetTasksPage(ctx, statusFilter, afterID, skip, pageSize)
+			if err != nil {
+				span.RecordError(err)
+				logger.ErrorContext(ctx, "failed to list tasks", err, map[string]any{
+					"method":        funcName,
+					"status_filter": statusFilter,
+				})
+				yield(nil, err)
+				return
+			}
+
+			for _, task := range page {
+				listed++
+				if !yield(task, nil) {
+					return
+				}
+			}
+			if len(page) < pageSize {
+				break
+			}
+			afterID = page[len(page)-1].ID
+			skip = 0
+		}
+
+		logger.InfoContext(ctx, "tasks listed", map[string]any{
+			"count":         listed,
+			"status_filter": statusFilter,
+			"method":        funcName,
+		})
+	}
+}
+
+func (u *TaskUsecase) CountTasks(ctx context.Context, statusFilter models.TaskStatus) (int, error) {
+	const funcName = "Usecase.CountTasks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	count, err := u.taskRepository.CountTasks(ctx, statusFilter)
 	if err != nil {
-		logger.Error("failed to list tasks", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to count tasks", err, map[string]any{
 			"method":        funcName,
 			"status_filter": statusFilter,
 		})
-		return nil, err
+		return 0, err
 	}
 
-	logger.Info("tasks listed", map[string]any{
-		"count":         len(tasks),
-		"status_filter": statusFilter,
-		"method":        funcName,
-	})
-
-	return tasks, nil
+	return count, nil
 }
 
 func (u *TaskUsecase) UpdateTask(ctx context.Context, id int64, newTitle, newDescription string, status models.TaskStatus) (*models.Task, error) {
 	const funcName = "Usecase.UpdateTask"
 
-	existingTask, err := u.taskRepository.GetTaskByID(ctx, id)
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	var updatedTask *models.Task
+	err := u.taskRepository.WithTx(ctx, func(tx app.TaskTx) error {
+		var err error
+		updatedTask, err = updateInTx(ctx, tx, id, newTitle, newDescription, status)
+		return err
+	})
 	if err != nil {
-		logger.Error("task not found for update", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update task", err, map[string]any{
 			"task_id": id,
 			"method":  funcName,
 		})
 		return nil, err
 	}
 
+	logger.InfoContext(ctx, "task updated", map[string]any{
+		"task_id": updatedTask.ID,
+		"method":  funcName,
+	})
+
+	return updatedTask, nil
+}
+
+func applyTaskChanges(task *models.Task, newTitle, newDescription string, status models.TaskStatus) error {
 	if newTitle != "" {
 		if err := validate.CheckTaskTitle(newTitle); err != nil {
-			logger.Error("invalid new task title", err, map[string]any{
-				"method":  funcName,
-				"task_id": id,
-				"title":   newTitle,
-			})
-			return nil, err
+			return err
 		}
-		existingTask.Title = newTitle
+		task.Title = newTitle
 	}
 
 	if newDescription != "" {
 		if err := validate.CheckTaskDescription(newDescription); err != nil {
-			logger.Error("invalid new task description", err, map[string]any{
-				"method":      funcName,
-				"task_id":     id,
-				"description": newDescription,
-			})
-			return nil, err
+			return err
 		}
-		existingTask.Description = newDescription
+		task.Description = newDescription
 	}
 
 	if status != "" {
-		existingTask.Status = status
+		if err := validate.CheckTaskStatus(status); err != nil {
+			return err
+		}
+		task.Status = status
 	}
 
-	existingTask.UpdatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (u *TaskUsecase) DeleteTask(ctx context.Context, id int64) error {
+	const funcName = "Usecase.DeleteTask"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
 
-	updatedTask, err := u.taskRepository.UpdateTask(ctx, existingTask)
+	err := u.taskRepository.WithTx(ctx, func(tx app.TaskTx) error {
+		return deleteInTx(ctx, tx, id)
+	})
 	if err != nil {
-		logger.Error("failed to update task", err, map[string]any{
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to delete task", err, map[string]any{
 			"task_id": id,
 			"method":  funcName,
 		})
-		return nil, err
+		return err
 	}
 
-	logger.Info("task updated", map[string]any{
-		"task_id": updatedTask.ID,
+	logger.InfoContext(ctx, "task deleted", map[string]any{
+		"task_id": id,
 		"method":  funcName,
 	})
 
-	return updatedTask, nil
+	return nil
 }
 
-func (u *TaskUsecase) DeleteTask(ctx context.Context, id int64) error {
-	const funcName = "Usecase.DeleteTask"
+// createInTx, updateInTx and deleteInTx record the domain events next to the
+// change itself, so they are only relayed if the transaction commits.
+func createInTx(ctx context.Context, tx app.TaskTx, task *models.Task) (*models.Task, error) {
+	created, err := tx.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := u.taskRepository.DeleteTask(ctx, id); err != nil {
-		logger.Error("failed to delete task", err, map[string]any{
-			"task_id": id,
-			"method":  funcName,
-		})
+	err = tx.AddEvent(ctx, models.TaskEvent{
+		Type:   models.EventTaskCreated,
+		TaskID: created.ID,
+		Task:   created,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func updateInTx(ctx context.Context, tx app.TaskTx, id int64, newTitle, newDescription string, status models.TaskStatus) (*models.Task, error) {
+	task, err := tx.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := task.Status
+	if err := applyTaskChanges(task, newTitle, newDescription, status); err != nil {
+		return nil, err
+	}
+
+	updated, err := tx.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addUpdateEvents(ctx, tx, updated, previousStatus); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func addUpdateEvents(ctx context.Context, tx app.TaskTx, updated *models.Task, previousStatus models.TaskStatus) error {
+	err := tx.AddEvent(ctx, models.TaskEvent{
+		Type:   models.EventTaskUpdated,
+		TaskID: updated.ID,
+		Task:   updated,
+	})
+	if err != nil {
 		return err
 	}
 
-	logger.Info("task deleted", map[string]any{
-		"task_id": id,
-		"method":  funcName,
+	if updated.Status == previousStatus {
+		return nil
+	}
+
+	return tx.AddEvent(ctx, models.TaskEvent{
+		Type:           models.EventTaskStatusChanged,
+		TaskID:         updated.ID,
+		Task:           updated,
+		PreviousStatus: previousStatus,
 	})
+}
 
-	return nil
+func deleteInTx(ctx context.Context, tx app.TaskTx, id int64) error {
+	if err := tx.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.AddEvent(ctx, models.TaskEvent{
+		Type:   models.EventTaskDeleted,
+		TaskID: id,
+	})
 }
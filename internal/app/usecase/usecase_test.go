@@ -10,6 +10,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/supchaser/LO_test_task/internal/app"
 	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
 	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
@@ -34,7 +35,7 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 		name          string
 		title         string
 		description   string
-		mockSetup     func(*mock_app.MockTaskRepository)
+		mockSetup     func(*mock_app.MockTaskRepository, *mock_app.MockTaskTx)
 		expectedTask  *models.Task
 		expectedError error
 	}{
@@ -42,8 +43,9 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 			name:        "Success",
 			title:       "Valid Title",
 			description: "Valid Description",
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					CreateTask(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
 						return mockTask, nil
@@ -56,7 +58,7 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 			name:          "Invalid Title",
 			title:         "",
 			description:   "Valid Description",
-			mockSetup:     func(mockRepo *mock_app.MockTaskRepository) {},
+			mockSetup:     func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {},
 			expectedTask:  nil,
 			expectedError: fmt.Errorf("%w: task title cannot be empty", errs.ErrValidation),
 		},
@@ -64,7 +66,7 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 			name:          "Invalid Description",
 			title:         "Valid Title",
 			description:   "This description is way too long and exceeds the maximum allowed length of 5000 characters. " + strings.Repeat("a", 5000),
-			mockSetup:     func(mockRepo *mock_app.MockTaskRepository) {},
+			mockSetup:     func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {},
 			expectedTask:  nil,
 			expectedError: fmt.Errorf("%w: task description cannot be longer than %d characters", errs.ErrValidation, validate.MaxTaskDescriptionLength),
 		},
@@ -72,8 +74,9 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 			name:        "Repository Error",
 			title:       "Valid Title",
 			description: "Valid Description",
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					CreateTask(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("repository error"))
 			},
@@ -85,8 +88,9 @@ func TestTaskUsecase_CreateTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
 			if tt.mockSetup != nil {
-				tt.mockSetup(mockRepo)
+				tt.mockSetup(mockRepo, mockTx)
 			}
 
 			uc := CreateTaskUsecase(mockRepo)
@@ -196,9 +200,16 @@ func TestTaskUsecase_ListTasks(t *testing.T) {
 		},
 	}
 
+	fullPage := make([]*models.Task, listPageSize)
+	for i := range fullPage {
+		fullPage[i] = &models.Task{ID: int64(i + 1)}
+	}
+	nextPage := []*models.Task{{ID: listPageSize + 1}}
+
 	tests := []struct {
 		name          string
 		statusFilter  models.TaskStatus
+		offset, limit int
 		mockSetup     func(*mock_app.MockTaskRepository)
 		expectedTasks []*models.Task
 		expectedError error
@@ -208,7 +219,7 @@ func TestTaskUsecase_ListTasks(t *testing.T) {
 			statusFilter: "",
 			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
 				mockRepo.EXPECT().
-					GetAllTasks(gomock.Any(), models.TaskStatus("")).
+					GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(0), 0, listPageSize).
 					Return(mockTasks, nil)
 			},
 			expectedTasks: mockTasks,
@@ -219,18 +230,48 @@ func TestTaskUsecase_ListTasks(t *testing.T) {
 			statusFilter: models.StatusPending,
 			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
 				mockRepo.EXPECT().
-					GetAllTasks(gomock.Any(), models.StatusPending).
+					GetTasksPage(gomock.Any(), models.StatusPending, int64(0), 0, listPageSize).
 					Return(mockTasks, nil)
 			},
 			expectedTasks: mockTasks,
 			expectedError: nil,
 		},
+		{
+			name:         "Offset And Limit",
+			statusFilter: "",
+			offset:       1,
+			limit:        2,
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
+				mockRepo.EXPECT().
+					GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(0), 1, 2).
+					Return(mockTasks[1:], nil)
+			},
+			expectedTasks: mockTasks[1:],
+			expectedError: nil,
+		},
+		{
+			name:         "Continues After Last ID",
+			statusFilter: "",
+			offset:       3,
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
+				gomock.InOrder(
+					mockRepo.EXPECT().
+						GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(0), 3, listPageSize).
+						Return(fullPage, nil),
+					mockRepo.EXPECT().
+						GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(listPageSize), 0, listPageSize).
+						Return(nextPage, nil),
+				)
+			},
+			expectedTasks: append(fullPage[:len(fullPage):len(fullPage)], nextPage...),
+			expectedError: nil,
+		},
 		{
 			name:         "Repository Error",
 			statusFilter: "",
 			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
 				mockRepo.EXPECT().
-					GetAllTasks(gomock.Any(), models.TaskStatus("")).
+					GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(0), 0, listPageSize).
 					Return(nil, errors.New("repository error"))
 			},
 			expectedTasks: nil,
@@ -246,7 +287,15 @@ func TestTaskUsecase_ListTasks(t *testing.T) {
 			}
 
 			uc := CreateTaskUsecase(mockRepo)
-			result, err := uc.ListTasks(context.Background(), tt.statusFilter)
+			var result []*models.Task
+			var err error
+			for task, iterErr := range uc.ListTasks(context.Background(), tt.statusFilter, tt.offset, tt.limit) {
+				if iterErr != nil {
+					err = iterErr
+					break
+				}
+				result = append(result, task)
+			}
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -259,6 +308,45 @@ func TestTaskUsecase_ListTasks(t *testing.T) {
 	}
 }
 
+func TestTaskUsecase_ListTasksStopsEarly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fullPage := make([]*models.Task, listPageSize)
+	for i := range fullPage {
+		fullPage[i] = &models.Task{ID: int64(i + 1)}
+	}
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	mockRepo.EXPECT().
+		GetTasksPage(gomock.Any(), models.TaskStatus(""), int64(0), 0, listPageSize).
+		Return(fullPage, nil)
+
+	uc := CreateTaskUsecase(mockRepo)
+	for task, err := range uc.ListTasks(context.Background(), "", 0, 0) {
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), task.ID)
+		break
+	}
+}
+
+func TestTaskUsecase_CountTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	uc := CreateTaskUsecase(mockRepo)
+
+	mockRepo.EXPECT().CountTasks(gomock.Any(), models.StatusPending).Return(2, nil)
+	count, err := uc.CountTasks(context.Background(), models.StatusPending)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	mockRepo.EXPECT().CountTasks(gomock.Any(), models.TaskStatus("")).Return(0, errors.New("repository error"))
+	_, err = uc.CountTasks(context.Background(), "")
+	assert.EqualError(t, err, "repository error")
+}
+
 func TestTaskUsecase_UpdateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -288,7 +376,7 @@ func TestTaskUsecase_UpdateTask(t *testing.T) {
 		newTitle       string
 		newDescription string
 		newStatus      models.TaskStatus
-		mockSetup      func(*mock_app.MockTaskRepository)
+		mockSetup      func(*mock_app.MockTaskRepository, *mock_app.MockTaskTx)
 		expectedTask   *models.Task
 		expectedError  error
 	}{
@@ -298,11 +386,12 @@ func TestTaskUsecase_UpdateTask(t *testing.T) {
 			newTitle:       "New Title",
 			newDescription: "New Description",
 			newStatus:      models.StatusCompleted,
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					GetTaskByID(gomock.Any(), int64(1)).
 					Return(existingTask, nil)
-				mockRepo.EXPECT().
+				mockTx.EXPECT().
 					UpdateTask(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
 						return updatedTask, nil
@@ -317,25 +406,54 @@ func TestTaskUsecase_UpdateTask(t *testing.T) {
 			newTitle:       "New Title",
 			newDescription: "New Description",
 			newStatus:      models.StatusCompleted,
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					GetTaskByID(gomock.Any(), int64(2)).
 					Return(nil, errors.New("task not found"))
 			},
 			expectedTask:  nil,
 			expectedError: errors.New("task not found"),
 		},
+		{
+			name:           "Invalid Title",
+			taskID:         1,
+			newTitle:       "",
+			newDescription: strings.Repeat("a", validate.MaxTaskDescriptionLength+1),
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					GetTaskByID(gomock.Any(), int64(1)).
+					Return(&models.Task{ID: 1, Title: "Old Title"}, nil)
+			},
+			expectedTask:  nil,
+			expectedError: errs.ErrValidation,
+		},
+		{
+			name:      "Invalid Status",
+			taskID:    1,
+			newStatus: "done",
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
+					GetTaskByID(gomock.Any(), int64(1)).
+					Return(&models.Task{ID: 1, Title: "Old Title", Status: models.StatusPending}, nil)
+			},
+			expectedTask:  nil,
+			expectedError: errs.ErrValidation,
+		},
 		{
 			name:           "Repository Update Error",
 			taskID:         1,
 			newTitle:       "New Title",
 			newDescription: "New Description",
 			newStatus:      models.StatusCompleted,
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					GetTaskByID(gomock.Any(), int64(1)).
 					Return(existingTask, nil)
-				mockRepo.EXPECT().
+				mockTx.EXPECT().
 					UpdateTask(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("update error"))
 			},
@@ -347,8 +465,9 @@ func TestTaskUsecase_UpdateTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
 			if tt.mockSetup != nil {
-				tt.mockSetup(mockRepo)
+				tt.mockSetup(mockRepo, mockTx)
 			}
 
 			uc := CreateTaskUsecase(mockRepo)
@@ -378,6 +497,18 @@ func TestTaskUsecase_UpdateTask(t *testing.T) {
 	}
 }
 
+func expectTx(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+	mockRepo.EXPECT().
+		WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(app.TaskTx) error) error {
+			return fn(mockTx)
+		})
+	mockTx.EXPECT().
+		AddEvent(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+}
+
 func TestTaskUsecase_DeleteTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -385,14 +516,15 @@ func TestTaskUsecase_DeleteTask(t *testing.T) {
 	tests := []struct {
 		name          string
 		taskID        int64
-		mockSetup     func(*mock_app.MockTaskRepository)
+		mockSetup     func(*mock_app.MockTaskRepository, *mock_app.MockTaskTx)
 		expectedError error
 	}{
 		{
 			name:   "Success",
 			taskID: 1,
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					DeleteTask(gomock.Any(), int64(1)).
 					Return(nil)
 			},
@@ -401,8 +533,9 @@ func TestTaskUsecase_DeleteTask(t *testing.T) {
 		{
 			name:   "Task Not Found",
 			taskID: 2,
-			mockSetup: func(mockRepo *mock_app.MockTaskRepository) {
-				mockRepo.EXPECT().
+			mockSetup: func(mockRepo *mock_app.MockTaskRepository, mockTx *mock_app.MockTaskTx) {
+				expectTx(mockRepo, mockTx)
+				mockTx.EXPECT().
 					DeleteTask(gomock.Any(), int64(2)).
 					Return(errors.New("task not found"))
 			},
@@ -413,8 +546,9 @@ func TestTaskUsecase_DeleteTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mock_app.NewMockTaskRepository(ctrl)
+			mockTx := mock_app.NewMockTaskTx(ctrl)
 			if tt.mockSetup != nil {
-				tt.mockSetup(mockRepo)
+				tt.mockSetup(mockRepo, mockTx)
 			}
 
 			uc := CreateTaskUsecase(mockRepo)
@@ -429,3 +563,70 @@ func TestTaskUsecase_DeleteTask(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskUsecase_RecordsDomainEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_app.NewMockTaskRepository(ctrl)
+	mockTx := mock_app.NewMockTaskTx(ctrl)
+	uc := CreateTaskUsecase(mockRepo)
+
+	var staged, committed []models.TaskEvent
+	mockRepo.EXPECT().
+		WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(app.TaskTx) error) error {
+			staged = nil
+			if err := fn(mockTx); err != nil {
+				return err
+			}
+			committed = append(committed, staged...)
+			return nil
+		}).
+		Times(5)
+	mockTx.EXPECT().
+		AddEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event models.TaskEvent) error {
+			staged = append(staged, event)
+			return nil
+		}).
+		AnyTimes()
+
+	mockTx.EXPECT().
+		CreateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+			return task, nil
+		})
+	mockTx.EXPECT().
+		GetTaskByID(gomock.Any(), int64(1)).
+		Return(&models.Task{ID: 1, Title: "Old Title", Status: models.StatusPending}, nil).
+		Times(2)
+	mockTx.EXPECT().
+		UpdateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, task *models.Task) (*models.Task, error) {
+			return task, nil
+		}).
+		Times(2)
+	mockTx.EXPECT().DeleteTask(gomock.Any(), int64(1)).Return(nil)
+	mockTx.EXPECT().DeleteTask(gomock.Any(), int64(2)).Return(errs.ErrTaskNotFound)
+
+	created, err := uc.CreateTask(context.Background(), "Valid Title", "")
+	assert.NoError(t, err)
+	_, err = uc.UpdateTask(context.Background(), 1, "", "", models.StatusCompleted)
+	assert.NoError(t, err)
+	_, err = uc.UpdateTask(context.Background(), 1, "New Title", "", "")
+	assert.NoError(t, err)
+	assert.NoError(t, uc.DeleteTask(context.Background(), 1))
+	assert.Error(t, uc.DeleteTask(context.Background(), 2))
+
+	assert.Len(t, committed, 5)
+	assert.Equal(t, models.EventTaskCreated, committed[0].Type)
+	assert.Equal(t, created.ID, committed[0].TaskID)
+	assert.Equal(t, models.EventTaskUpdated, committed[1].Type)
+	assert.Equal(t, models.EventTaskStatusChanged, committed[2].Type)
+	assert.Equal(t, models.StatusPending, committed[2].PreviousStatus)
+	assert.Equal(t, models.StatusCompleted, committed[2].Task.Status)
+	assert.Equal(t, models.EventTaskUpdated, committed[3].Type)
+	assert.Equal(t, models.EventTaskDeleted, committed[4].Type)
+	assert.Nil(t, committed[4].Task)
+}
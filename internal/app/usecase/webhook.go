@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+	"github.com/supchaser/LO_test_task/internal/utils/validate"
+)
+
+type WebhookUsecase struct {
+	webhookRepository app.WebhookRepository
+	lastID            atomic.Int64
+}
+
+func CreateWebhookUsecase(webhookRepository app.WebhookRepository) *WebhookUsecase {
+	return &WebhookUsecase{
+		webhookRepository: webhookRepository,
+	}
+}
+
+func validateWebhook(url string, eventTypes []models.TaskEventType) error {
+	if err := validate.CheckWebhookURL(url); err != nil {
+		return err
+	}
+
+	return validate.CheckWebhookEventTypes(eventTypes)
+}
+
+func normalizeEventTypes(eventTypes []models.TaskEventType) []models.TaskEventType {
+	normalized := append([]models.TaskEventType{}, eventTypes...)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+func (u *WebhookUsecase) CreateWebhook(ctx context.Context, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error) {
+	const funcName = "Usecase.CreateWebhook"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := validateWebhook(url, eventTypes); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid webhook", err, map[string]any{
+			"method": funcName,
+			"url":    url,
+		})
+		return nil, err
+	}
+
+	if err := validate.CheckWebhookSecret(secret); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid webhook secret", err, map[string]any{
+			"method": funcName,
+		})
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := &models.Webhook{
+		ID:         u.nextWebhookID(now),
+		URL:        url,
+		EventTypes: normalizeEventTypes(eventTypes),
+		Secret:     secret,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	created, err := u.webhookRepository.CreateWebhook(ctx, webhook)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to create webhook in repository", err, map[string]any{
+			"webhook_id": webhook.ID,
+			"method":     funcName,
+		})
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "webhook created successfully", map[string]any{
+		"webhook_id": created.ID,
+		"method":     funcName,
+	})
+
+	return created, nil
+}
+
+func (u *WebhookUsecase) nextWebhookID(now time.Time) int64 {
+	for {
+		last := u.lastID.Load()
+		id := now.UnixMilli()
+		if id <= last {
+			id = last + 1
+		}
+		if u.lastID.CompareAndSwap(last, id) {
+			return id
+		}
+	}
+}
+
+func (u *WebhookUsecase) GetWebhook(ctx context.Context, id int64) (*models.Webhook, error) {
+	const funcName = "Usecase.GetWebhook"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	webhook, err := u.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to get webhook", err, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (u *WebhookUsecase) ListWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	const funcName = "Usecase.ListWebhooks"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	webhooks, err := u.webhookRepository.GetAllWebhooks(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list webhooks", err, map[string]any{
+			"method": funcName,
+		})
+		return nil, err
+	}
+
+	slices.SortFunc(webhooks, func(a, b *models.Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return webhooks, nil
+}
+
+func (u *WebhookUsecase) UpdateWebhook(ctx context.Context, id int64, url string, eventTypes []models.TaskEventType, secret string) (*models.Webhook, error) {
+	const funcName = "Usecase.UpdateWebhook"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := validateWebhook(url, eventTypes); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "invalid webhook", err, map[string]any{
+			"method":     funcName,
+			"webhook_id": id,
+		})
+		return nil, err
+	}
+
+	webhook, err := u.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to get webhook for update", err, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return nil, err
+	}
+
+	if secret != "" {
+		if err := validate.CheckWebhookSecret(secret); err != nil {
+			span.RecordError(err)
+			logger.ErrorContext(ctx, "invalid webhook secret", err, map[string]any{
+				"method":     funcName,
+				"webhook_id": id,
+			})
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+	webhook.URL = url
+	webhook.EventTypes = normalizeEventTypes(eventTypes)
+
+	updated, err := u.webhookRepository.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to update webhook in repository", err, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "webhook updated successfully", map[string]any{
+		"webhook_id": id,
+		"method":     funcName,
+	})
+
+	return updated, nil
+}
+
+func (u *WebhookUsecase) DeleteWebhook(ctx context.Context, id int64) error {
+	const funcName = "Usecase.DeleteWebhook"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	if err := u.webhookRepository.DeleteWebhook(ctx, id); err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to delete webhook", err, map[string]any{
+			"webhook_id": id,
+			"method":     funcName,
+		})
+		return err
+	}
+
+	logger.InfoContext(ctx, "webhook deleted successfully", map[string]any{
+		"webhook_id": id,
+		"method":     funcName,
+	})
+
+	return nil
+}
+
+func (u *WebhookUsecase) ListDeliveryLogs(ctx context.Context, webhookID int64) ([]models.WebhookDeliveryLog, error) {
+	const funcName = "Usecase.ListDeliveryLogs"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	logs, err := u.webhookRepository.GetDeliveryLogs(ctx, webhookID)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list delivery logs", err, map[string]any{
+			"webhook_id": webhookID,
+			"method":     funcName,
+		})
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (u *WebhookUsecase) ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	const funcName = "Usecase.ListDeadLetters"
+
+	ctx, span := tracing.Start(ctx, funcName)
+	defer span.End()
+
+	letters, err := u.webhookRepository.GetDeadLetters(ctx)
+	if err != nil {
+		span.RecordError(err)
+		logger.ErrorContext(ctx, "failed to list dead letters", err, map[string]any{
+			"method": funcName,
+		})
+		return nil, err
+	}
+
+	return letters, nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_app "github.com/supchaser/LO_test_task/internal/app/mocks"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+func TestWebhookUsecase_CreateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		url           string
+		eventTypes    []models.TaskEventType
+		secret        string
+		mockSetup     func(*mock_app.MockWebhookRepository)
+		expectedTypes []models.TaskEventType
+		expectedError error
+	}{
+		{
+			name:       "Success",
+			url:        "https://example.com/hook",
+			eventTypes: []models.TaskEventType{models.EventTaskUpdated, models.EventTaskCreated, models.EventTaskUpdated},
+			secret:     testWebhookSecret,
+			mockSetup: func(mockRepo *mock_app.MockWebhookRepository) {
+				mockRepo.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+						return webhook, nil
+					})
+			},
+			expectedTypes: []models.TaskEventType{models.EventTaskCreated, models.EventTaskUpdated},
+		},
+		{
+			name:          "Invalid URL",
+			url:           "ftp://example.com",
+			secret:        testWebhookSecret,
+			mockSetup:     func(mockRepo *mock_app.MockWebhookRepository) {},
+			expectedError: errs.ErrValidation,
+		},
+		{
+			name:          "Unknown Event Type",
+			url:           "https://example.com/hook",
+			eventTypes:    []models.TaskEventType{"task.archived"},
+			secret:        testWebhookSecret,
+			mockSetup:     func(mockRepo *mock_app.MockWebhookRepository) {},
+			expectedError: errs.ErrValidation,
+		},
+		{
+			name:          "Short Secret",
+			url:           "https://example.com/hook",
+			secret:        "short",
+			mockSetup:     func(mockRepo *mock_app.MockWebhookRepository) {},
+			expectedError: errs.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mock_app.NewMockWebhookRepository(ctrl)
+			tt.mockSetup(mockRepo)
+
+			uc := CreateWebhookUsecase(mockRepo)
+			webhook, err := uc.CreateWebhook(context.Background(), tt.url, tt.eventTypes, tt.secret)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, webhook)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.url, webhook.URL)
+			assert.Equal(t, tt.expectedTypes, webhook.EventTypes)
+			assert.Equal(t, tt.secret, webhook.Secret)
+			assert.Positive(t, webhook.ID)
+		})
+	}
+}
+
+func TestWebhookUsecase_UpdateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	existing := &models.Webhook{
+		ID:     1,
+		URL:    "https://example.com/hook",
+		Secret: testWebhookSecret,
+	}
+
+	tests := []struct {
+		name           string
+		secret         string
+		mockSetup      func(*mock_app.MockWebhookRepository)
+		expectedSecret string
+		expectedError  error
+	}{
+		{
+			name:   "Keeps Secret",
+			secret: "",
+			mockSetup: func(mockRepo *mock_app.MockWebhookRepository) {
+				clone := *existing
+				mockRepo.EXPECT().GetWebhookByID(gomock.Any(), int64(1)).Return(&clone, nil)
+				mockRepo.EXPECT().
+					UpdateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+						return webhook, nil
+					})
+			},
+			expectedSecret: testWebhookSecret,
+		},
+		{
+			name:   "Rotates Secret",
+			secret: "fedcba9876543210",
+			mockSetup: func(mockRepo *mock_app.MockWebhookRepository) {
+				clone := *existing
+				mockRepo.EXPECT().GetWebhookByID(gomock.Any(), int64(1)).Return(&clone, nil)
+				mockRepo.EXPECT().
+					UpdateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+						return webhook, nil
+					})
+			},
+			expectedSecret: "fedcba9876543210",
+		},
+		{
+			name:   "Not Found",
+			secret: "",
+			mockSetup: func(mockRepo *mock_app.MockWebhookRepository) {
+				mockRepo.EXPECT().GetWebhookByID(gomock.Any(), int64(1)).Return(nil, errs.ErrWebhookNotFound)
+			},
+			expectedError: errs.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mock_app.NewMockWebhookRepository(ctrl)
+			tt.mockSetup(mockRepo)
+
+			uc := CreateWebhookUsecase(mockRepo)
+			webhook, err := uc.UpdateWebhook(context.Background(), 1, "https://example.com/new", nil, tt.secret)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https://example.com/new", webhook.URL)
+			assert.Equal(t, tt.expectedSecret, webhook.Secret)
+			assert.Empty(t, webhook.EventTypes)
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	WebhookIDHeader = "X-Webhook-ID"
+
+	maxResponseDrain = 4 << 10
+)
+
+var (
+	errQueueFull         = errors.New("delivery queue is full")
+	errDispatcherStopped = errors.New("dispatcher stopped")
+)
+
+type Config struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	Timeout     time.Duration
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Workers:     4,
+		QueueSize:   1024,
+		MaxAttempts: 5,
+		Timeout:     10 * time.Second,
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+	}
+}
+
+type job struct {
+	webhookID int64
+	url       string
+	event     models.TaskEvent
+	payload   []byte
+	attempt   int
+	lastError string
+}
+
+type Dispatcher struct {
+	webhookRepository app.WebhookRepository
+	config            Config
+	client            *http.Client
+	jobs              chan job
+	retries           map[*time.Timer]job
+	closed            bool
+	mu                sync.Mutex
+	workers           sync.WaitGroup
+	ctx               context.Context
+	cancel            context.CancelFunc
+}
+
+func CreateDispatcher(webhookRepository app.WebhookRepository, config Config) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &Dispatcher{
+		webhookRepository: webhookRepository,
+		config:            config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		jobs:    make(chan job, config.QueueSize),
+		retries: make(map[*time.Timer]job),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	d.workers.Add(config.Workers)
+	for range config.Workers {
+		go d.worker()
+	}
+
+	return d
+}
+
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// Deliver queues the event for every matching webhook. It fails without
+// queueing anything when the queue cannot take all of them, so the caller can
+// retry the whole event later.
+func (d *Dispatcher) Deliver(ctx context.Context, event models.TaskEvent) error {
+	webhooks, err := d.webhookRepository.GetAllWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var jobs []job
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		jobs = append(jobs, job{
+			webhookID: webhook.ID,
+			url:       webhook.URL,
+			event:     event,
+			payload:   payload,
+		})
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return errDispatcherStopped
+	}
+	if len(jobs) > cap(d.jobs)-len(d.jobs) {
+		return errQueueFull
+	}
+	for _, j := range jobs {
+		d.jobs <- j
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) enqueue(j job) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		d.deadLetter(j, errDispatcherStopped)
+		return
+	}
+
+	select {
+	case d.jobs <- j:
+	default:
+		d.deadLetter(j, errQueueFull)
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.workers.Done()
+
+	for j := range d.jobs {
+		d.deliver(j)
+	}
+}
+
+func (d *Dispatcher) deliver(j job) {
+	const funcName = "WebhookDispatcher.deliver"
+
+	webhook, err := d.webhookRepository.GetWebhookByID(d.ctx, j.webhookID)
+	if err != nil {
+		if !errors.Is(err, errs.ErrWebhookNotFound) {
+			logger.Error("failed to load webhook", err, map[string]any{
+				"method":     funcName,
+				"webhook_id": j.webhookID,
+			})
+		}
+		return
+	}
+
+	j.url = webhook.URL
+	j.attempt++
+
+	ctx, span := tracing.Start(d.ctx, funcName, tracing.WithSpanKind(tracing.SpanKindClient), tracing.WithAttributes(map[string]any{
+		"webhook.id":      webhook.ID,
+		"webhook.attempt": j.attempt,
+		"event.id":        j.event.ID,
+		"event.type":      string(j.event.Type),
+	}))
+	defer span.End()
+
+	started := time.Now()
+	statusCode, err := d.send(ctx, webhook, j)
+	if statusCode != 0 {
+		span.SetAttribute("http.response.status_code", statusCode)
+	}
+	span.RecordError(err)
+	entry := models.WebhookDeliveryLog{
+		WebhookID:   webhook.ID,
+		EventID:     j.event.ID,
+		EventType:   j.event.Type,
+		Attempt:     j.attempt,
+		StatusCode:  statusCode,
+		Success:     err == nil,
+		Duration:    time.Since(started),
+		AttemptedAt: started,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	d.webhookRepository.AddDeliveryLog(d.ctx, entry)
+
+	if err == nil {
+		logger.Info("webhook delivered", map[string]any{
+			"method":     funcName,
+			"webhook_id": webhook.ID,
+			"event_id":   j.event.ID,
+			"attempt":    j.attempt,
+		})
+		return
+	}
+
+	logger.Warn("webhook delivery failed", map[string]any{
+		"method":      funcName,
+		"webhook_id":  webhook.ID,
+		"event_id":    j.event.ID,
+		"attempt":     j.attempt,
+		"status_code": statusCode,
+		"error":       err.Error(),
+	})
+
+	j.lastError = err.Error()
+	if !retryable(statusCode) || j.attempt >= d.config.MaxAttempts {
+		d.mu.Lock()
+		d.deadLetter(j, err)
+		d.mu.Unlock()
+		return
+	}
+
+	d.scheduleRetry(j)
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook *models.Webhook, j job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(j.payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, strconv.FormatInt(webhook.ID, 10))
+	req.Header.Set(EventHeader, string(j.event.Type))
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(j.event.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, j.payload))
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a failed attempt may succeed later: transport
+// errors, timeouts, throttling and server errors are retried, other client
+// errors and redirects are treated as permanent.
+func retryable(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.BackoffBase
+	for i := 1; i < attempt && delay < d.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.config.BackoffMax)
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func (d *Dispatcher) scheduleRetry(j job) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		d.deadLetter(j, errDispatcherStopped)
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d.backoff(j.attempt), func() {
+		d.mu.Lock()
+		_, pending := d.retries[timer]
+		delete(d.retries, timer)
+		d.mu.Unlock()
+
+		if pending {
+			d.enqueue(j)
+		}
+	})
+	d.retries[timer] = j
+}
+
+// deadLetter must be called with d.mu held.
+func (d *Dispatcher) deadLetter(j job, err error) {
+	const funcName = "WebhookDispatcher.deadLetter"
+
+	lastError := j.lastError
+	if lastError == "" || errors.Is(err, errDispatcherStopped) || errors.Is(err, errQueueFull) {
+		lastError = err.Error()
+	}
+
+	d.webhookRepository.AddDeadLetter(context.Background(), models.DeadLetter{
+		WebhookID: j.webhookID,
+		URL:       j.url,
+		Event:     j.event,
+		Attempts:  j.attempt,
+		LastError: lastError,
+		FailedAt:  time.Now(),
+	})
+
+	logger.Error("webhook delivery moved to dead letters", err, map[string]any{
+		"method":     funcName,
+		"webhook_id": j.webhookID,
+		"event_id":   j.event.ID,
+		"attempts":   j.attempt,
+	})
+}
+
+// Check reports whether the dispatcher accepts new deliveries.
+func (d *Dispatcher) Check(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return errDispatcherStopped
+	}
+	if len(d.jobs) == cap(d.jobs) {
+		return errQueueFull
+	}
+
+	return nil
+}
+
+// Close stops accepting events, dead-letters pending retries and waits for
+// queued deliveries to finish. When ctx expires first, in-flight requests are
+// cancelled.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	// A retry belongs to whoever removes it from d.retries under d.mu. A timer
+	// that has already fired but whose callback still waits for the lock finds
+	// its entry gone and leaves the job to this dead letter.
+	for timer, j := range d.retries {
+		timer.Stop()
+		d.deadLetter(j, errDispatcherStopped)
+		delete(d.retries, timer)
+	}
+	close(d.jobs)
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
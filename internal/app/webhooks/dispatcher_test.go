@@ -0,0 +1,274 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+const testSecret = "0123456789abcdef"
+
+func testConfig() Config {
+	return Config{
+		Workers:     2,
+		QueueSize:   16,
+		MaxAttempts: 3,
+		Timeout:     time.Second,
+		BackoffBase: time.Millisecond,
+		BackoffMax:  5 * time.Millisecond,
+	}
+}
+
+func createWebhook(t *testing.T, repo *repository.WebhookRepository, url string, eventTypes ...models.TaskEventType) *models.Webhook {
+	t.Helper()
+
+	webhook, err := repo.CreateWebhook(context.Background(), &models.Webhook{
+		ID:         1,
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     testSecret,
+	})
+	require.NoError(t, err)
+
+	return webhook
+}
+
+func closeDispatcher(t *testing.T, d *Dispatcher) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, d.Close(ctx))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	payload := []byte(`{"id":1}`)
+	signature := Sign(testSecret, 1700000000, payload)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify(testSecret, 1700000000, payload, signature))
+	assert.False(t, Verify(testSecret, 1700000001, payload, signature))
+	assert.False(t, Verify("another-secret-value", 1700000000, payload, signature))
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	repo := repository.CreateWebhookRepository()
+	webhook := createWebhook(t, repo, server.URL, models.EventTaskCreated)
+	d := CreateDispatcher(repo, testConfig())
+
+	require.NoError(t, d.Deliver(context.Background(), models.TaskEvent{ID: 7, Type: models.EventTaskUpdated, TaskID: 1}))
+	require.NoError(t, d.Deliver(context.Background(), models.TaskEvent{ID: 8, Type: models.EventTaskCreated, TaskID: 1}))
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	body := <-bodies
+	closeDispatcher(t, d)
+
+	assert.Equal(t, "8", req.Header.Get(DeliveryHeader))
+	assert.Equal(t, string(models.EventTaskCreated), req.Header.Get(EventHeader))
+	assert.Equal(t, strconv.FormatInt(webhook.ID, 10), req.Header.Get(WebhookIDHeader))
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify(testSecret, timestamp, body, req.Header.Get(SignatureHeader)))
+
+	_, err = tracing.ParseTraceparent(req.Header.Get(tracing.TraceparentHeader))
+	assert.NoError(t, err)
+
+	logs, err := repo.GetDeliveryLogs(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.True(t, logs[0].Success)
+	assert.Equal(t, http.StatusOK, logs[0].StatusCode)
+	assert.Equal(t, uint64(8), logs[0].EventID)
+}
+
+func TestDispatcher_RetriesThenSucceeds(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	repo := repository.CreateWebhookRepository()
+	webhook := createWebhook(t, repo, server.URL)
+	d := CreateDispatcher(repo, testConfig())
+
+	require.NoError(t, d.Deliver(context.Background(), models.TaskEvent{ID: 1, Type: models.EventTaskCreated}))
+
+	require.Eventually(t, func() bool {
+		logs, _ := repo.GetDeliveryLogs(context.Background(), webhook.ID)
+		return len(logs) == 3
+	}, time.Second, 5*time.Millisecond)
+	closeDispatcher(t, d)
+
+	logs, err := repo.GetDeliveryLogs(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	assert.False(t, logs[0].Success)
+	assert.Equal(t, http.StatusServiceUnavailable, logs[0].StatusCode)
+	assert.Equal(t, 3, logs[2].Attempt)
+	assert.True(t, logs[2].Success)
+
+	letters, err := repo.GetDeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestDispatcher_DeadLetters(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		expectedAttempts int
+	}{
+		{
+			name:             "Retries Exhausted",
+			status:           http.StatusInternalServerError,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "Permanent Failure",
+			status:           http.StatusGone,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			repo := repository.CreateWebhookRepository()
+			webhook := createWebhook(t, repo, server.URL)
+			d := CreateDispatcher(repo, testConfig())
+
+			require.NoError(t, d.Deliver(context.Background(), models.TaskEvent{ID: 5, Type: models.EventTaskDeleted, TaskID: 9}))
+
+			require.Eventually(t, func() bool {
+				letters, _ := repo.GetDeadLetters(context.Background())
+				return len(letters) == 1
+			}, time.Second, 5*time.Millisecond)
+			closeDispatcher(t, d)
+
+			letters, err := repo.GetDeadLetters(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, webhook.ID, letters[0].WebhookID)
+			assert.Equal(t, uint64(5), letters[0].Event.ID)
+			assert.Equal(t, tt.expectedAttempts, letters[0].Attempts)
+			assert.Contains(t, letters[0].LastError, strconv.Itoa(tt.status))
+			assert.Equal(t, int32(tt.expectedAttempts), calls.Load())
+		})
+	}
+}
+
+func TestDispatcher_CloseDeadLettersPendingRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repo := repository.CreateWebhookRepository()
+	webhook := createWebhook(t, repo, server.URL)
+	config := testConfig()
+	config.BackoffBase = time.Hour
+	config.BackoffMax = time.Hour
+	d := CreateDispatcher(repo, config)
+
+	require.NoError(t, d.Deliver(context.Background(), models.TaskEvent{ID: 1, Type: models.EventTaskCreated}))
+	require.Eventually(t, func() bool {
+		logs, _ := repo.GetDeliveryLogs(context.Background(), webhook.ID)
+		return len(logs) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	closeDispatcher(t, d)
+
+	letters, err := repo.GetDeadLetters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 1, letters[0].Attempts)
+	assert.Equal(t, errDispatcherStopped.Error(), letters[0].LastError)
+
+	err = d.Deliver(context.Background(), models.TaskEvent{ID: 2, Type: models.EventTaskCreated})
+	assert.ErrorIs(t, err, errDispatcherStopped)
+}
+
+func TestDispatcher_CloseWhileRetryFires(t *testing.T) {
+	repo := repository.CreateWebhookRepository()
+	config := testConfig()
+	config.BackoffBase = 20 * time.Millisecond
+	config.BackoffMax = 20 * time.Millisecond
+	d := CreateDispatcher(repo, config)
+
+	d.scheduleRetry(job{webhookID: 1, url: "http://127.0.0.1:1", event: models.TaskEvent{ID: 1}, attempt: 1})
+
+	// Close waits for the lock first; the timer fires meanwhile, so its
+	// callback gets the lock only after Close has taken the retry.
+	d.mu.Lock()
+	closed := make(chan error, 1)
+	go func() {
+		closed <- d.Close(context.Background())
+	}()
+	time.Sleep(60 * time.Millisecond)
+	d.mu.Unlock()
+	require.NoError(t, <-closed)
+
+	require.Eventually(t, func() bool {
+		letters, _ := repo.GetDeadLetters(context.Background())
+		return len(letters) == 1
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	letters, err := repo.GetDeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
+
+func TestDispatcher_BackoffIsBounded(t *testing.T) {
+	d := &Dispatcher{config: Config{BackoffBase: 100 * time.Millisecond, BackoffMax: time.Second}}
+
+	for attempt := 1; attempt <= 70; attempt++ {
+		delay := d.backoff(attempt)
+		assert.LessOrEqual(t, delay, time.Second)
+		assert.Positive(t, delay)
+	}
+
+	delay := d.backoff(3)
+	assert.GreaterOrEqual(t, delay, 200*time.Millisecond)
+	assert.LessOrEqual(t, delay, 400*time.Millisecond)
+}
+
+func TestDispatcher_Check(t *testing.T) {
+	d := CreateDispatcher(repository.CreateWebhookRepository(), testConfig())
+	assert.NoError(t, d.Check(context.Background()))
+
+	closeDispatcher(t, d)
+	assert.ErrorIs(t, d.Check(context.Background()), errDispatcherStopped)
+}
@@ -1,86 +1,420 @@
 package config
 
 import (
-	"bufio"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"os"
-	"strings"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tlsconfig"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+
+	StorageBackendMemory = "memory"
+
+	defaultEnvFile = ".env"
 )
 
+// Config is populated from these sources, each overriding the previous one:
+//
+//  1. defaults (Default)
+//  2. the JSON file named by the -config flag or the CONFIG_FILE variable
+//  3. the .env file (-env-file, ".env" by default; optional)
+//  4. environment variables
+//  5. command-line flags, named after the JSON path, e.g. -server.port
+//
+// Every field carries its JSON key and environment variable in struct tags;
+// fields tagged reload:"true" can be changed at runtime (see Watcher).
 type Config struct {
-	ServerPort string
+	Server      ServerConfig      `json:"server"`
+	Storage     StorageConfig     `json:"storage"`
+	Limits      LimitsConfig      `json:"limits"`
+	Idempotency IdempotencyConfig `json:"idempotency"`
+	Events      EventsConfig      `json:"events"`
+	Webhooks    WebhooksConfig    `json:"webhooks"`
+	Tracing     TracingConfig     `json:"tracing"`
+	AccessLog   AccessLogConfig   `json:"access_log"`
+	Log         LogConfig         `json:"log"`
+	Reload      ReloadConfig      `json:"reload"`
+	Health      HealthConfig      `json:"health"`
+	Admin       AdminConfig       `json:"admin"`
 }
 
-func loadEnv(filename string) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
+// Zero read and write timeouts disable them; they are off by default because
+// they also cut long-lived SSE and WebSocket connections.
+type ServerConfig struct {
+	Port              string        `json:"port" env:"SERVER_PORT" usage:"HTTP listen port"`
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" usage:"time limit for reading request headers"`
+	ReadTimeout       time.Duration `json:"read_timeout" env:"SERVER_READ_TIMEOUT" usage:"time limit for reading the whole request, 0 disables"`
+	WriteTimeout      time.Duration `json:"write_timeout" env:"SERVER_WRITE_TIMEOUT" usage:"time limit for writing the response, 0 disables"`
+	IdleTimeout       time.Duration `json:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" usage:"keep-alive idle connection timeout"`
+	ShutdownTimeout   time.Duration `json:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" usage:"graceful shutdown time limit"`
+	TLS               TLSConfig     `json:"tls"`
+}
+
+// TLS is enabled when CertFile and KeyFile are set.
+type TLSConfig struct {
+	CertFile          string        `json:"cert_file" env:"SERVER_TLS_CERT_FILE" usage:"PEM certificate file, enables HTTPS"`
+	KeyFile           string        `json:"key_file" env:"SERVER_TLS_KEY_FILE" usage:"PEM private key file"`
+	ClientCAFile      string        `json:"client_ca_file" env:"SERVER_TLS_CLIENT_CA_FILE" usage:"PEM CA bundle used to verify client certificates"`
+	ClientAuth        string        `json:"client_auth" env:"SERVER_TLS_CLIENT_AUTH" usage:"client certificate policy (none, request, require, verify_if_given, require_and_verify)"`
+	CertCheckInterval time.Duration `json:"cert_check_interval" env:"SERVER_TLS_CERT_CHECK_INTERVAL" usage:"how often certificate files are checked for rotation"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+type ReloadConfig struct {
+	PollInterval time.Duration `json:"poll_interval" env:"CONFIG_POLL_INTERVAL" usage:"how often config files are checked for changes, 0 disables"`
+}
+
+type HealthConfig struct {
+	CheckTimeout  time.Duration `json:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" usage:"time limit for a single health check"`
+	ShutdownDelay time.Duration `json:"shutdown_delay" env:"HEALTH_SHUTDOWN_DELAY" usage:"how long /readyz fails before the server stops accepting connections"`
+}
+
+// AdminConfig protects the /admin/* endpoints; without a token they are
+// disabled.
+type AdminConfig struct {
+	Token string `json:"token" env:"ADMIN_TOKEN" usage:"bearer token required by the /admin/* endpoints, empty disables them"`
+}
+
+type StorageConfig struct {
+	Backend string `json:"backend" env:"STORAGE_BACKEND" usage:"task storage backend (memory)"`
+}
+
+type LimitsConfig struct {
+	MaxHeaderBytes int   `json:"max_header_bytes" env:"LIMITS_MAX_HEADER_BYTES" usage:"maximum size of request headers in bytes"`
+	MaxBodyBytes   int64 `json:"max_body_bytes" env:"LIMITS_MAX_BODY_BYTES" usage:"maximum size of a request body in bytes"`
+}
+
+type IdempotencyConfig struct {
+	TTL time.Duration `json:"ttl" env:"IDEMPOTENCY_TTL" usage:"how long idempotent responses are kept"`
+}
+
+type EventsConfig struct {
+	ReplaySize         int           `json:"replay_size" env:"EVENT_REPLAY_SIZE" usage:"number of events kept for replay"`
+	OutboxPollInterval time.Duration `json:"outbox_poll_interval" env:"OUTBOX_POLL_INTERVAL" usage:"outbox relay poll interval"`
+	// WebSocketOrigins are the browser origins besides the server's own that
+	// may open /tasks/ws, e.g. https://app.example.com; "*" allows any.
+	WebSocketOrigins []string `json:"websocket_origins" env:"EVENT_WEBSOCKET_ORIGINS" usage:"comma separated origins allowed to open WebSocket connections"`
+}
+
+type WebhooksConfig struct {
+	Workers     int           `json:"workers" env:"WEBHOOK_WORKERS" usage:"number of delivery workers"`
+	MaxAttempts int           `json:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" usage:"delivery attempts before dead-lettering"`
+	Timeout     time.Duration `json:"timeout" env:"WEBHOOK_TIMEOUT" usage:"delivery request timeout"`
+	BackoffBase time.Duration `json:"backoff_base" env:"WEBHOOK_BACKOFF_BASE" usage:"first retry delay"`
+	BackoffMax  time.Duration `json:"backoff_max" env:"WEBHOOK_BACKOFF_MAX" usage:"maximum retry delay"`
+}
+
+type TracingConfig struct {
+	Exporter     string `json:"exporter" env:"TRACING_EXPORTER" usage:"span exporter (none, stdout, otlp)"`
+	ServiceName  string `json:"service_name" env:"TRACING_SERVICE_NAME" usage:"service.name resource attribute"`
+	OTLPEndpoint string `json:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT" usage:"OTLP/HTTP traces endpoint"`
+}
+
+type AccessLogConfig struct {
+	Format        string        `json:"format" env:"ACCESS_LOG_FORMAT" usage:"access log format (common, combined, json)"`
+	SlowThreshold time.Duration `json:"slow_threshold" env:"ACCESS_LOG_SLOW_THRESHOLD" usage:"requests at least this slow are logged as warnings"`
+	ExcludePaths  []string      `json:"exclude_paths" env:"ACCESS_LOG_EXCLUDE_PATHS" usage:"comma separated paths left out of the access log"`
+}
+
+type LogConfig struct {
+	Level           string            `json:"level" env:"LOG_LEVEL" reload:"true" usage:"minimum log level"`
+	ComponentLevels map[string]string `json:"component_levels" env:"LOG_COMPONENT_LEVELS" reload:"true" usage:"per-component levels, e.g. Repository.*=WARN"`
+	Format          string            `json:"format" env:"LOG_FORMAT" usage:"log format (text, json)"`
+	Sink            string            `json:"sink" env:"LOG_SINK" usage:"log destination (stdout, file, syslog)"`
+	BufferSize      int               `json:"buffer_size" env:"LOG_BUFFER_SIZE" usage:"log queue size"`
+	OverflowPolicy  string            `json:"overflow_policy" env:"LOG_OVERFLOW_POLICY" usage:"full queue policy (block, drop_newest, drop_oldest)"`
+	File            LogFileConfig     `json:"file"`
+	Syslog          LogSyslogConfig   `json:"syslog"`
+	Redact          LogRedactConfig   `json:"redact"`
+}
+
+type LogFileConfig struct {
+	Path       string        `json:"path" env:"LOG_FILE_PATH" usage:"log file path"`
+	MaxSizeMB  int           `json:"max_size_mb" env:"LOG_FILE_MAX_SIZE_MB" usage:"rotate the log file at this size in megabytes"`
+	MaxAge     time.Duration `json:"max_age" env:"LOG_FILE_MAX_AGE" usage:"rotate the log file after this time"`
+	MaxBackups int           `json:"max_backups" env:"LOG_FILE_MAX_BACKUPS" usage:"number of rotated files to keep"`
+	Compress   bool          `json:"compress" env:"LOG_FILE_COMPRESS" usage:"gzip rotated files"`
+}
+
+type LogSyslogConfig struct {
+	Address string `json:"address" env:"LOG_SYSLOG_ADDRESS" usage:"syslog UDP address"`
+	Tag     string `json:"tag" env:"LOG_SYSLOG_TAG" usage:"syslog application name"`
+}
+
+// Keys and Detectors set to the single value "none" are switched off, as an
+// empty value keeps the defaults.
+type LogRedactConfig struct {
+	Keys           []string `json:"keys" env:"LOG_REDACT_KEYS" reload:"true" usage:"field name patterns whose values are hidden"`
+	Allowlist      []string `json:"allowlist" env:"LOG_REDACT_ALLOWLIST" reload:"true" usage:"if set, only matching fields are logged as is"`
+	Detectors      []string `json:"detectors" env:"LOG_REDACT_DETECTORS" reload:"true" usage:"value detectors (email, token, phone)"`
+	MaxValueLength int      `json:"max_value_length" env:"LOG_REDACT_MAX_VALUE_LENGTH" reload:"true" usage:"truncate longer string values"`
+}
+
+func Default() Config {
+	redaction := logger.DefaultRedactionConfig()
+
+	return Config{
+		Server: ServerConfig{
+			Port:              "8080",
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+			TLS: TLSConfig{
+				ClientAuth:        tlsconfig.ClientAuthNone,
+				CertCheckInterval: 10 * time.Second,
+			},
+		},
+		Storage: StorageConfig{
+			Backend: StorageBackendMemory,
+		},
+		Limits: LimitsConfig{
+			MaxHeaderBytes: 1 << 20,
+			MaxBodyBytes:   10 << 20,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		Events: EventsConfig{
+			ReplaySize:         1000,
+			OutboxPollInterval: time.Second,
+		},
+		Webhooks: WebhooksConfig{
+			Workers:     4,
+			MaxAttempts: 5,
+			Timeout:     10 * time.Second,
+			BackoffBase: time.Second,
+			BackoffMax:  time.Minute,
+		},
+		Tracing: TracingConfig{
+			Exporter:     TracingExporterNone,
+			ServiceName:  "task-service",
+			OTLPEndpoint: "http://localhost:4318/v1/traces",
+		},
+		AccessLog: AccessLogConfig{
+			Format:        "combined",
+			SlowThreshold: time.Second,
+			ExcludePaths:  []string{"/health", "/livez", "/readyz", "/metrics"},
+		},
+		Log: LogConfig{
+			Level:           logger.LevelInfo,
+			ComponentLevels: map[string]string{},
+			Format:          logger.FormatText,
+			Sink:            logger.SinkStdout,
+			BufferSize:      1024,
+			OverflowPolicy:  logger.PolicyDropNewest,
+			File: LogFileConfig{
+				Path:       "logs/app.log",
+				MaxSizeMB:  100,
+				MaxAge:     24 * time.Hour,
+				MaxBackups: 7,
+				Compress:   true,
+			},
+			Syslog: LogSyslogConfig{
+				Address: "localhost:514",
+				Tag:     "task-service",
+			},
+			Redact: LogRedactConfig{
+				Keys:           redaction.Keys,
+				Detectors:      redaction.Detectors,
+				MaxValueLength: redaction.MaxValueLength,
+			},
+		},
+		Reload: ReloadConfig{
+			PollInterval: 5 * time.Second,
+		},
+		Health: HealthConfig{
+			CheckTimeout:  2 * time.Second,
+			ShutdownDelay: 5 * time.Second,
+		},
 	}
-	defer file.Close()
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "#") || len(strings.TrimSpace(line)) == 0 {
-			continue
-		}
+// Load builds the configuration from all sources; args are the command-line
+// arguments without the program name. Problems in every source are reported
+// together. With -h it returns an error wrapping flag.ErrHelp.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	fields := fieldsOf(&cfg)
 
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
+	flags, values, configFile, envFile := newFlagSet(fields)
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		value = strings.Trim(value, `"`)
+	var errs []error
 
-		if _, exists := os.LookupEnv(key); !exists {
-			os.Setenv(key, value)
+	if *configFile == "" {
+		*configFile = lookupEnv("CONFIG_FILE")
+	}
+	if *configFile != "" {
+		if err := applyFile(fields, *configFile); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return scanner.Err()
+	dotEnv, err := readEnvFile(*envFile)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, applyEnv(fields, dotEnv)...)
+	errs = append(errs, applyFlags(flags, fields, values)...)
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	return &cfg, nil
 }
 
-func checkEnv(envVars []string) error {
-	var missingVars []string
+func newFlagSet(fields []field) (*flag.FlagSet, map[string]*string, *string, *string) {
+	flags := flag.NewFlagSet("task-service", flag.ContinueOnError)
+
+	configFile := flags.String("config", "", "JSON configuration file (also CONFIG_FILE)")
+	envFile := flags.String("env-file", defaultEnvFile, "file with environment variables, ignored if missing")
 
-	for _, envVar := range envVars {
-		if value, exists := os.LookupEnv(envVar); !exists || value == "" {
-			missingVars = append(missingVars, envVar)
+	values := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		usage := f.usage
+		if f.env != "" {
+			usage += " (env " + f.env + ")"
 		}
+		values[f.path] = flags.String(f.path, "", usage)
 	}
 
-	if len(missingVars) > 0 {
-		return fmt.Errorf("error: this env vars are missing: %v", missingVars)
-	} else {
-		return nil
+	return flags, values, configFile, envFile
+}
+
+func (c *Config) normalize() {
+	c.Log.Redact.Keys = withoutNone(c.Log.Redact.Keys)
+	c.Log.Redact.Detectors = withoutNone(c.Log.Redact.Detectors)
+}
+
+func withoutNone(values []string) []string {
+	if len(values) == 1 && values[0] == "none" {
+		return []string{}
 	}
+	return values
 }
 
-func validateEnv() error {
-	err := checkEnv([]string{
-		"SERVER_PORT",
-	})
-	if err != nil {
-		return err
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(name, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: invalid value %q: must be one of %v", name, value, allowed))
 	}
 
-	return nil
-}
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port <= 65535, "server.port: invalid value %q: must be a number between 1 and 65535", c.Server.Port)
+	check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout: must not be negative")
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout: must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout: must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout: must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout: must be positive")
+	if c.Server.TLS.Enabled() {
+		check(c.Server.TLS.CertFile != "" && c.Server.TLS.KeyFile != "", "server.tls: cert_file and key_file must be set together")
+	}
+	if authType, err := tlsconfig.ParseClientAuth(c.Server.TLS.ClientAuth); err != nil {
+		errs = append(errs, fmt.Errorf("server.tls.client_auth: %w", err))
+	} else if authType != tls.NoClientCert {
+		check(c.Server.TLS.Enabled(), "server.tls.client_auth: requires cert_file and key_file")
+		verifies := authType == tls.VerifyClientCertIfGiven || authType == tls.RequireAndVerifyClientCert
+		check(!verifies || c.Server.TLS.ClientCAFile != "", "server.tls.client_auth: %q requires client_ca_file", c.Server.TLS.ClientAuth)
+	}
+	check(c.Server.TLS.CertCheckInterval >= 0, "server.tls.cert_check_interval: must not be negative")
 
-func LoadConfig(envFile string) (*Config, error) {
-	err := loadEnv(envFile)
-	if err != nil {
-		return nil, fmt.Errorf("load configuration file: %w", err)
+	oneOf("storage.backend", c.Storage.Backend, StorageBackendMemory)
+
+	check(c.Limits.MaxHeaderBytes > 0, "limits.max_header_bytes: must be positive")
+	check(c.Limits.MaxBodyBytes > 0, "limits.max_body_bytes: must be positive")
+
+	check(c.Idempotency.TTL > 0, "idempotency.ttl: must be positive")
+	check(c.Events.ReplaySize > 0, "events.replay_size: must be positive")
+	check(c.Events.OutboxPollInterval > 0, "events.outbox_poll_interval: must be positive")
+
+	check(c.Webhooks.Workers > 0, "webhooks.workers: must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts: must be positive")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout: must be positive")
+	check(c.Webhooks.BackoffBase > 0, "webhooks.backoff_base: must be positive")
+	check(c.Webhooks.BackoffMax >= c.Webhooks.BackoffBase, "webhooks.backoff_max: must not be less than webhooks.backoff_base")
+
+	oneOf("tracing.exporter", c.Tracing.Exporter, TracingExporterNone, TracingExporterStdout, TracingExporterOTLP)
+	check(c.Tracing.ServiceName != "", "tracing.service_name: must not be empty")
+	if c.Tracing.Exporter == TracingExporterOTLP {
+		endpoint, err := url.Parse(c.Tracing.OTLPEndpoint)
+		check(err == nil && (endpoint.Scheme == "http" || endpoint.Scheme == "https") && endpoint.Host != "",
+			"tracing.otlp_endpoint: invalid value %q: must be an http(s) URL", c.Tracing.OTLPEndpoint)
 	}
 
-	err = validateEnv()
-	if err != nil {
-		return nil, fmt.Errorf("LoadConfig: %w", err)
+	oneOf("access_log.format", c.AccessLog.Format, "common", "combined", "json")
+	check(c.AccessLog.SlowThreshold > 0, "access_log.slow_threshold: must be positive")
+
+	if err := c.LevelSettings().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
 	}
+	if _, err := logger.CreateRedactor(c.Redaction()); err != nil {
+		errs = append(errs, fmt.Errorf("log.redact: %w", err))
+	}
+	oneOf("log.format", c.Log.Format, logger.FormatText, logger.FormatJSON)
+	oneOf("log.sink", c.Log.Sink, logger.SinkStdout, logger.SinkFile, logger.SinkSyslog)
+	check(c.Log.BufferSize > 0, "log.buffer_size: must be positive")
+	oneOf("log.overflow_policy", c.Log.OverflowPolicy, logger.PolicyBlock, logger.PolicyDropNewest, logger.PolicyDropOldest)
+	if c.Log.Sink == logger.SinkFile {
+		check(c.Log.File.Path != "", "log.file.path: must not be empty")
+		check(c.Log.File.MaxSizeMB > 0, "log.file.max_size_mb: must be positive")
+		check(c.Log.File.MaxAge > 0, "log.file.max_age: must be positive")
+		check(c.Log.File.MaxBackups > 0, "log.file.max_backups: must be positive")
+	}
+	if c.Log.Sink == logger.SinkSyslog {
+		check(c.Log.Syslog.Address != "", "log.syslog.address: must not be empty")
+	}
+	check(c.Log.Redact.MaxValueLength > 0, "log.redact.max_value_length: must be positive")
+
+	check(c.Reload.PollInterval >= 0, "reload.poll_interval: must not be negative")
+
+	check(c.Health.CheckTimeout > 0, "health.check_timeout: must be positive")
+	check(c.Health.ShutdownDelay >= 0, "health.shutdown_delay: must not be negative")
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) LevelSettings() logger.LevelSettings {
+	return logger.LevelSettings{Global: c.Log.Level, Components: c.Log.ComponentLevels}
+}
+
+func (c *Config) Redaction() logger.RedactionConfig {
+	redaction := logger.DefaultRedactionConfig()
+	redaction.Keys = c.Log.Redact.Keys
+	redaction.Allowlist = c.Log.Redact.Allowlist
+	redaction.Detectors = c.Log.Redact.Detectors
+	redaction.MaxValueLength = c.Log.Redact.MaxValueLength
 
-	return &Config{
-		ServerPort: os.Getenv("SERVER_PORT"),
-	}, nil
+	return redaction
 }
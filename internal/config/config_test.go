@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")})
+	require.NoError(t, err)
+
+	assert.Equal(t, Default(), *cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	configFile := writeFile(t, "config.json", `{
+		"server": {"port": "1001", "read_timeout": "1s", "write_timeout": "1s", "idle_timeout": "1s"},
+		"log": {"level": "debug", "component_levels": {"Repository.*": "WARN"}},
+		"access_log": {"exclude_paths": ["/a", "/b"]}
+	}`)
+	envFile := writeFile(t, ".env", "SERVER_PORT=1002\nSERVER_WRITE_TIMEOUT=2s\nSERVER_IDLE_TIMEOUT=2s\n")
+	t.Setenv("SERVER_PORT", "1003")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "3s")
+
+	cfg, err := Load([]string{"-config", configFile, "-env-file", envFile, "-server.port", "1004"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1004", cfg.Server.Port)
+	assert.Equal(t, 3*time.Second, cfg.Server.IdleTimeout)
+	assert.Equal(t, 2*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, map[string]string{"Repository.*": "WARN"}, cfg.Log.ComponentLevels)
+	assert.Equal(t, []string{"/a", "/b"}, cfg.AccessLog.ExcludePaths)
+	assert.Equal(t, Default().Webhooks, cfg.Webhooks)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.json", `{"storage": {"backend": "memory"}, "limits": {"max_body_bytes": 2048}}`))
+
+	cfg, err := Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2048), cfg.Limits.MaxBodyBytes)
+}
+
+func TestLoad_ReportsAllProblems(t *testing.T) {
+	configFile := writeFile(t, "config.json", `{
+		"server": {"port": "http", "shutdown_timeout": 30},
+		"unknown": {"key": 1},
+		"log": {"levle": "INFO"}
+	}`)
+	t.Setenv("WEBHOOK_WORKERS", "many")
+	t.Setenv("LOG_SINK", "kafka")
+
+	_, err := Load([]string{"-config", configFile, "-env-file", filepath.Join(t.TempDir(), "missing.env"), "-storage.backend", "postgres"})
+	require.Error(t, err)
+
+	for _, want := range []string{
+		`server.shutdown_timeout: duration must be a string`,
+		`unknown: unknown setting`,
+		`log.levle: unknown setting`,
+		`env WEBHOOK_WORKERS: invalid integer "many"`,
+		`server.port: invalid value "http"`,
+		`storage.backend: invalid value "postgres"`,
+		`log.sink: invalid value "kafka"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	_, err := Load([]string{"-h"})
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+}
+
+func TestLoad_RedactNone(t *testing.T) {
+	t.Setenv("LOG_REDACT_DETECTORS", "none")
+
+	cfg, err := Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")})
+	require.NoError(t, err)
+
+	assert.Empty(t, cfg.Log.Redact.Detectors)
+	assert.NotEmpty(t, cfg.Log.Redact.Keys)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			modify: func(*Config) {},
+		},
+		{
+			name:    "negative timeout",
+			modify:  func(c *Config) { c.Server.ReadTimeout = -time.Second },
+			wantErr: "server.read_timeout: must not be negative",
+		},
+		{
+			name:    "tls key without certificate",
+			modify:  func(c *Config) { c.Server.TLS.KeyFile = "server.key" },
+			wantErr: "server.tls: cert_file and key_file must be set together",
+		},
+		{
+			name:    "client auth without tls",
+			modify:  func(c *Config) { c.Server.TLS.ClientAuth = "require" },
+			wantErr: "server.tls.client_auth: requires cert_file and key_file",
+		},
+		{
+			name: "client verification without CA",
+			modify: func(c *Config) {
+				c.Server.TLS.CertFile, c.Server.TLS.KeyFile = "server.crt", "server.key"
+				c.Server.TLS.ClientAuth = "require_and_verify"
+			},
+			wantErr: "requires client_ca_file",
+		},
+		{
+			name:    "backoff max below base",
+			modify:  func(c *Config) { c.Webhooks.BackoffMax = time.Millisecond },
+			wantErr: "webhooks.backoff_max",
+		},
+		{
+			name:    "invalid otlp endpoint",
+			modify:  func(c *Config) { c.Tracing.Exporter, c.Tracing.OTLPEndpoint = TracingExporterOTLP, "localhost:4318" },
+			wantErr: "tracing.otlp_endpoint",
+		},
+		{
+			name:    "invalid component level",
+			modify:  func(c *Config) { c.Log.ComponentLevels = map[string]string{"Repository.*": "LOUD"} },
+			wantErr: "log.level",
+		},
+		{
+			name:    "unknown detector",
+			modify:  func(c *Config) { c.Log.Redact.Detectors = []string{"iban"} },
+			wantErr: "log.redact",
+		},
+		{
+			name:    "file sink without path",
+			modify:  func(c *Config) { c.Log.Sink, c.Log.File.Path = "file", "" },
+			wantErr: "log.file.path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a settable leaf of Config, addressed by its dotted JSON path.
+type field struct {
+	path       string
+	env        string
+	usage      string
+	reloadable bool
+	value      reflect.Value
+}
+
+func fieldsOf(cfg *Config) []field {
+	var fields []field
+	collectFields(reflect.ValueOf(cfg).Elem(), "", &fields)
+	return fields
+}
+
+func collectFields(v reflect.Value, prefix string, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		path := prefix + sf.Tag.Get("json")
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			collectFields(v.Field(i), path+".", fields)
+			continue
+		}
+
+		*fields = append(*fields, field{
+			path:       path,
+			env:        sf.Tag.Get("env"),
+			usage:      sf.Tag.Get("usage"),
+			reloadable: sf.Tag.Get("reload") == "true",
+			value:      v.Field(i),
+		})
+	}
+}
+
+// set parses raw according to the field type: durations use
+// time.ParseDuration, lists are comma separated and maps are comma separated
+// key=value pairs.
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	v := f.value
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int, v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice:
+		values := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	case v.Kind() == reflect.Map:
+		values := map[string]string{}
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("invalid pair %q: expected key=value", pair)
+			}
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// applyFile reads a JSON object mirroring Config. Durations are strings such
+// as "30s", lists are arrays and maps are objects; unknown keys are errors so
+// that typos do not go unnoticed.
+func applyFile(fields []field, filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("config file %s: %w", filename, err)
+	}
+
+	byPath := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byPath[f.path] = f
+	}
+
+	var errs []error
+	applyObject(document, "", byPath, &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("config file %s: %w", filename, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func applyObject(object map[string]any, prefix string, byPath map[string]field, errs *[]error) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		value := object[key]
+
+		f, ok := byPath[path]
+		if !ok {
+			if nested, isObject := value.(map[string]any); isObject && isSection(byPath, path) {
+				applyObject(nested, path+".", byPath, errs)
+				continue
+			}
+			*errs = append(*errs, fmt.Errorf("%s: unknown setting", path))
+			continue
+		}
+
+		raw, err := jsonToRaw(f, value)
+		if err == nil {
+			err = f.set(raw)
+		}
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+}
+
+func isSection(byPath map[string]field, path string) bool {
+	for p := range byPath {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonToRaw(f field, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		if f.value.Type() == durationType {
+			return "", fmt.Errorf("duration must be a string such as \"30s\", got %s", v)
+		}
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		if f.value.Kind() != reflect.Slice {
+			break
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("list items must be strings")
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		if f.value.Kind() != reflect.Map {
+			break
+		}
+		pairs := make([]string, 0, len(v))
+		for key, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("map values must be strings")
+			}
+			pairs = append(pairs, key+"="+s)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ","), nil
+	}
+
+	return "", fmt.Errorf("unexpected value %v", value)
+}
+
+// readEnvFile parses KEY=VALUE lines; a missing file is not an error.
+func readEnvFile(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("env file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("env file %s:%d: expected KEY=VALUE", filename, line)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("env file %s: %w", filename, err)
+	}
+
+	return values, nil
+}
+
+// applyEnv applies the .env values and then the process environment, which
+// wins. Empty values are treated as unset.
+func applyEnv(fields []field, dotEnv map[string]string) []error {
+	var errs []error
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+
+		raw, source := lookupEnv(f.env), "env"
+		if raw == "" {
+			raw, source = dotEnv[f.env], "env file"
+		}
+		if raw == "" {
+			continue
+		}
+
+		if err := f.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", source, f.env, err))
+		}
+	}
+
+	return errs
+}
+
+func lookupEnv(key string) string {
+	value, _ := os.LookupEnv(key)
+	return value
+}
+
+// applyFlags applies only the flags given on the command line, so that
+// unset flags do not reset values from other sources.
+func applyFlags(flags *flag.FlagSet, fields []field, values map[string]*string) []error {
+	var errs []error
+	byPath := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byPath[f.path] = f
+	}
+
+	flags.Visit(func(fl *flag.Flag) {
+		f, ok := byPath[fl.Name]
+		if !ok {
+			return
+		}
+		if err := f.set(*values[fl.Name]); err != nil {
+			errs = append(errs, fmt.Errorf("flag -%s: %w", fl.Name, err))
+		}
+	})
+
+	return errs
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+// Watcher reloads the configuration from the same arguments it was first
+// loaded with. Only fields tagged reload:"true" take effect; changes to other
+// settings, such as the port, are ignored with a warning until a restart.
+type Watcher struct {
+	args    []string
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(previous, current *Config)
+}
+
+func CreateWatcher(args []string, cfg *Config) *Watcher {
+	w := &Watcher{args: args}
+	w.current.Store(cfg)
+	return w
+}
+
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with the previous and the new
+// configuration after every successful reload, so it can apply only what
+// changed. Subscribers run one at a time, in registration order.
+func (w *Watcher) Subscribe(fn func(previous, current *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads and validates the configuration again; on error the current
+// configuration is kept.
+func (w *Watcher) Reload() error {
+	const funcName = "Config.Reload"
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := Load(w.args)
+	if err != nil {
+		return err
+	}
+
+	previous := w.current.Load()
+	merged, changed, ignored := mergeReloadable(previous, next)
+	for _, path := range ignored {
+		logger.Warn("setting cannot be changed without a restart", map[string]any{
+			"method":  funcName,
+			"setting": path,
+		})
+	}
+
+	w.current.Store(merged)
+	for _, fn := range w.subscribers {
+		fn(previous, merged)
+	}
+
+	logger.Info("configuration reloaded", map[string]any{
+		"method":  funcName,
+		"changed": changed,
+	})
+
+	return nil
+}
+
+// mergeReloadable returns current with the reloadable fields taken from next,
+// along with the paths of the reloadable fields that changed and of the
+// other fields whose change was ignored.
+func mergeReloadable(current, next *Config) (*Config, []string, []string) {
+	merged := *current
+	mergedFields := fieldsOf(&merged)
+	nextFields := fieldsOf(next)
+
+	changed, ignored := []string{}, []string{}
+	for i, f := range mergedFields {
+		value := nextFields[i].value
+		if reflect.DeepEqual(f.value.Interface(), value.Interface()) {
+			continue
+		}
+
+		if f.reloadable {
+			f.value.Set(value)
+			changed = append(changed, f.path)
+		} else {
+			ignored = append(ignored, f.path)
+		}
+	}
+
+	return &merged, changed, ignored
+}
+
+// Watch polls the config and env files every interval and reloads when their
+// modification time or size changes, until ctx is done.
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration) {
+	files := w.files()
+	last := statFiles(files)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := statFiles(files)
+			if reflect.DeepEqual(current, last) {
+				continue
+			}
+			last = current
+
+			if err := w.Reload(); err != nil {
+				logger.Error("failed to reload configuration", err, map[string]any{
+					"method": "Config.Watch",
+				})
+			}
+		}
+	}
+}
+
+// files returns the files Load reads for the watcher's arguments.
+func (w *Watcher) files() []string {
+	cfg := Default()
+	flags, _, configFile, envFile := newFlagSet(fieldsOf(&cfg))
+	flags.SetOutput(io.Discard)
+	flags.Parse(w.args)
+
+	if *configFile == "" {
+		*configFile = lookupEnv("CONFIG_FILE")
+	}
+	if *configFile == "" {
+		return []string{*envFile}
+	}
+	return []string{*configFile, *envFile}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFiles treats a missing file as the zero state, so creating or
+// removing a file also counts as a change.
+func statFiles(files []string) []fileState {
+	states := make([]fileState, len(files))
+	for i, name := range files {
+		if info, err := os.Stat(name); err == nil {
+			states[i] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
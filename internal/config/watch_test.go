@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_ReloadAppliesReloadableSettings(t *testing.T) {
+	configFile := writeFile(t, "config.json", `{"server": {"port": "1001"}, "log": {"level": "INFO"}}`)
+	args := []string{"-config", configFile, "-env-file", filepath.Join(t.TempDir(), "missing.env")}
+
+	cfg, err := Load(args)
+	require.NoError(t, err)
+
+	watcher := CreateWatcher(args, cfg)
+	var notified []*Config
+	watcher.Subscribe(func(previous, c *Config) {
+		assert.Same(t, cfg, previous)
+		notified = append(notified, c)
+	})
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"server": {"port": "2002"}, "log": {"level": "DEBUG", "component_levels": {"Repository.*": "WARN"}}}`), 0o600))
+	require.NoError(t, watcher.Reload())
+
+	current := watcher.Current()
+	assert.Equal(t, "DEBUG", current.Log.Level)
+	assert.Equal(t, map[string]string{"Repository.*": "WARN"}, current.Log.ComponentLevels)
+	assert.Equal(t, "1001", current.Server.Port)
+	assert.Equal(t, "INFO", cfg.Log.Level)
+	require.Len(t, notified, 1)
+	assert.Same(t, current, notified[0])
+}
+
+func TestWatcher_ReloadKeepsConfigOnError(t *testing.T) {
+	configFile := writeFile(t, "config.json", `{"log": {"level": "INFO"}}`)
+	args := []string{"-config", configFile, "-env-file", filepath.Join(t.TempDir(), "missing.env")}
+
+	cfg, err := Load(args)
+	require.NoError(t, err)
+
+	watcher := CreateWatcher(args, cfg)
+	notified := 0
+	watcher.Subscribe(func(_, _ *Config) { notified++ })
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"log": {"level": "LOUD"}}`), 0o600))
+	assert.Error(t, watcher.Reload())
+
+	assert.Same(t, cfg, watcher.Current())
+	assert.Zero(t, notified)
+}
+
+func TestMergeReloadable(t *testing.T) {
+	current := Default()
+	next := Default()
+	next.Server.Port = "9090"
+	next.Limits.MaxBodyBytes = 1
+	next.Log.Redact.Detectors = []string{}
+
+	merged, changed, ignored := mergeReloadable(&current, &next)
+
+	assert.Equal(t, []string{"log.redact.detectors"}, changed)
+	assert.Equal(t, []string{"server.port", "limits.max_body_bytes"}, ignored)
+	assert.Equal(t, current.Server, merged.Server)
+	assert.Empty(t, merged.Log.Redact.Detectors)
+	assert.NotEmpty(t, current.Log.Redact.Detectors)
+}
+
+func TestWatcher_WatchReloadsOnFileChange(t *testing.T) {
+	envFile := writeFile(t, ".env", "LOG_LEVEL=INFO\n")
+	args := []string{"-env-file", envFile}
+
+	cfg, err := Load(args)
+	require.NoError(t, err)
+
+	watcher := CreateWatcher(args, cfg)
+	reloaded := make(chan *Config, 1)
+	watcher.Subscribe(func(_, c *Config) { reloaded <- c })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Watch(ctx, 10*time.Millisecond)
+
+	// Give Watch time to record the initial state before the file changes.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(envFile, []byte("LOG_LEVEL=WARN\n"), 0o600))
+	require.NoError(t, os.Chtimes(envFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	select {
+	case c := <-reloaded:
+		assert.Equal(t, "WARN", c.Log.Level)
+	case <-time.After(2 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
@@ -0,0 +1,38 @@
+package adminauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+)
+
+// TokenMiddleware lets through only requests carrying
+// "Authorization: Bearer <token>". With an empty token the admin endpoints
+// are disabled and every request is refused, so they are never left open
+// by a missing setting.
+func TokenMiddleware(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := map[string]any{
+			"path": r.URL.Path,
+		}
+
+		if token == "" {
+			logger.WarnContext(r.Context(), "admin endpoint is disabled", fields)
+			respond.Error(w, r, "admin endpoints are disabled: no admin token is configured", http.StatusForbidden)
+			return
+		}
+
+		scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(credentials), []byte(token)) != 1 {
+			logger.WarnContext(r.Context(), "admin request rejected", fields)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			respond.Error(w, r, "invalid or missing admin token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,107 @@
+package httpmetrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+)
+
+const (
+	unmatchedRoute = "unmatched"
+	otherMethod    = "OTHER"
+)
+
+var (
+	requestsTotal = metrics.NewCounterVec(
+		"http_requests_total",
+		"Total number of HTTP requests by method, route and status code.",
+		"method", "route", "code",
+	)
+	requestDuration = metrics.NewHistogramVec(
+		"http_request_duration_seconds",
+		"HTTP request latency by method and route.",
+		metrics.DefaultBuckets,
+		"method", "route",
+	)
+	requestsInFlight = metrics.NewGauge(
+		"http_requests_in_flight",
+		"Number of HTTP requests currently being served.",
+	)
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach Flush and Hijack on the
+// underlying writer, which the SSE and WebSocket handlers rely on.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// MetricsMiddleware wraps the ServeMux: the mux records the matched pattern on
+// the request, and routes are labelled by it to keep path parameters out of
+// label values.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsInFlight.Inc()
+		defer requestsInFlight.Dec()
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		started := time.Now()
+
+		defer func() {
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			method, route := methodOf(r), routeOf(r)
+			requestsTotal.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+			requestDuration.WithLabelValues(method, route).Observe(time.Since(started).Seconds())
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// methodOf labels methods outside the standard set as OTHER: clients choose
+// the method freely, and every new value would add a series.
+func methodOf(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return r.Method
+	default:
+		return otherMethod
+	}
+}
+
+func routeOf(r *http.Request) string {
+	if r.Pattern == "" {
+		return unmatchedRoute
+	}
+
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
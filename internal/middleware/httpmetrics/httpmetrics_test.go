@@ -0,0 +1,78 @@
+package httpmetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supchaser/LO_test_task/internal/utils/metrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	handler := MetricsMiddleware(mux)
+
+	for _, path := range []string{"/items/1", "/items/2", "/items/missing", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/unknown", nil))
+
+	output := metrics.Default.Gather()
+
+	assert.Contains(t, output, `http_requests_total{method="GET",route="/items/{id}",code="200"} 2`)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="/items/{id}",code="404"} 1`)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="unmatched",code="404"} 1`)
+	assert.Contains(t, output, `http_requests_total{method="OTHER",route="unmatched",code="404"} 1`)
+	assert.NotContains(t, output, `method="BREW"`)
+	assert.Contains(t, output, `http_request_duration_seconds_count{method="GET",route="/items/{id}"} 3`)
+	assert.Contains(t, output, "http_requests_in_flight 0\n")
+}
+
+func TestMethodOf(t *testing.T) {
+	tests := []struct {
+		method   string
+		expected string
+	}{
+		{method: http.MethodGet, expected: http.MethodGet},
+		{method: http.MethodDelete, expected: http.MethodDelete},
+		{method: "PROPFIND", expected: otherMethod},
+		{method: "get", expected: otherMethod},
+		{method: "X-RANDOM-1234", expected: otherMethod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Method = tt.method
+			assert.Equal(t, tt.expected, methodOf(req))
+		})
+	}
+}
+
+func TestRouteOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{name: "method and path", pattern: "GET /tasks/{id}", expected: "/tasks/{id}"},
+		{name: "path only", pattern: "/", expected: "/"},
+		{name: "no pattern", pattern: "", expected: unmatchedRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Pattern = tt.pattern
+			assert.Equal(t, tt.expected, routeOf(req))
+		})
+	}
+}
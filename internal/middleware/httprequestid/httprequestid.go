@@ -0,0 +1,29 @@
+package httprequestid
+
+import (
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+)
+
+// RequestIDMiddleware keeps a valid X-Request-ID sent by the client or
+// generates a new one, echoes it in the response and attaches it to the
+// request context, so every log entry written for the request carries it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.Generate()
+		}
+
+		w.Header().Set(requestid.Header, id)
+
+		ctx := requestid.NewContext(r.Context(), id)
+		ctx = logger.ContextWithFields(ctx, map[string]any{
+			"request_id": id,
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,54 @@
+package httprequestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		keepsID   bool
+		expectNew bool
+	}{
+		{name: "keeps client ID", header: "client-request-1", keepsID: true},
+		{name: "generates missing ID", header: "", expectNew: true},
+		{name: "replaces invalid ID", header: "bad id\twith spaces", expectNew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contextID string
+			var fields map[string]any
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contextID = requestid.FromContext(r.Context())
+				fields = logger.FieldsFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			responseID := w.Header().Get(requestid.Header)
+			if tt.keepsID {
+				assert.Equal(t, tt.header, responseID)
+			}
+			if tt.expectNew {
+				assert.NotEqual(t, tt.header, responseID)
+				assert.True(t, requestid.Valid(responseID))
+			}
+			assert.Equal(t, responseID, contextID)
+			assert.Equal(t, responseID, fields["request_id"])
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package httptracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// TracingMiddleware continues the trace from incoming traceparent and
+// tracestate headers, or starts a new one, and wraps the request in a server
+// span. Like the metrics middleware it wraps the ServeMux, so the span is
+// named after the matched route once the mux has run.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if remote, ok := tracing.Extract(r.Header); ok {
+			ctx = tracing.ContextWithSpanContext(ctx, remote)
+		}
+
+		ctx, span := tracing.Start(ctx, r.Method, tracing.WithSpanKind(tracing.SpanKindServer), tracing.WithAttributes(map[string]any{
+			"http.request.method": r.Method,
+			"url.path":            r.URL.Path,
+		}))
+		ctx = logger.ContextWithFields(ctx, map[string]any{
+			"trace_id": span.SpanContext().TraceID.String(),
+		})
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		r = r.WithContext(ctx)
+		defer func() {
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if route := routeOf(r); route != "" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttribute("http.route", route)
+			}
+			span.SetAttribute("http.response.status_code", status)
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+			}
+			span.End()
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+func routeOf(r *http.Request) string {
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
@@ -0,0 +1,93 @@
+package httptracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/utils/tracing"
+)
+
+type recordingExporter struct {
+	spans []tracing.SpanData
+	mu    sync.Mutex
+}
+
+func (e *recordingExporter) Export(ctx context.Context, spans []tracing.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func serve(t *testing.T, req *http.Request, handler http.HandlerFunc) []tracing.SpanData {
+	t.Helper()
+
+	exporter := &recordingExporter{}
+	tracer := tracing.CreateTracer(exporter)
+	previous := tracing.GetTracer()
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(previous)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks/{id}", handler)
+	TracingMiddleware(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NoError(t, tracer.Close(context.Background()))
+	return exporter.spans
+}
+
+func TestTracingMiddleware_ContinuesRemoteTrace(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+	req.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(tracing.TracestateHeader, "congo=t61rcWkgMzE")
+
+	var handlerContext tracing.SpanContext
+	spans := serve(t, req, func(w http.ResponseWriter, r *http.Request) {
+		handlerContext = tracing.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /tasks/{id}", span.Name)
+	assert.Equal(t, tracing.SpanKindServer, span.Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+	assert.Equal(t, "congo=t61rcWkgMzE", span.SpanContext.TraceState)
+	assert.Equal(t, "/tasks/{id}", span.Attributes["http.route"])
+	assert.Equal(t, http.StatusNotFound, span.Attributes["http.response.status_code"])
+	assert.Equal(t, tracing.StatusUnset, span.StatusCode)
+	assert.Equal(t, span.SpanContext, handlerContext)
+}
+
+func TestTracingMiddleware_StartsNewTrace(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+	req.Header.Set(tracing.TraceparentHeader, "garbage")
+
+	spans := serve(t, req, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	require.Len(t, spans, 1)
+	assert.True(t, spans[0].SpanContext.IsValid())
+	assert.False(t, spans[0].ParentSpanID.IsValid())
+	assert.Equal(t, tracing.StatusError, spans[0].StatusCode)
+}
+
+func TestTracingMiddleware_UnmatchedRoute(t *testing.T) {
+	spans := serve(t, httptest.NewRequest(http.MethodGet, "/unknown", nil), func(w http.ResponseWriter, r *http.Request) {})
+
+	require.Len(t, spans, 1)
+	assert.Equal(t, http.MethodGet, spans[0].Name)
+	assert.NotContains(t, spans[0].Attributes, "http.route")
+}
@@ -0,0 +1,212 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	MaxKeyLength = 255
+)
+
+type record struct {
+	fingerprint string
+	completed   bool
+	status      int
+	header      http.Header
+	body        []byte
+	expiresAt   time.Time
+}
+
+type Store struct {
+	records   map[string]*record
+	ttl       time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func CreateStore(ttl time.Duration) *Store {
+	return &Store{
+		records: make(map[string]*record),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (s *Store) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > MaxKeyLength {
+			respond.Error(w, r, "idempotency key is too long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "failed to read request body", err, map[string]any{
+				"idempotency_key": key,
+				"path":            r.URL.Path,
+			})
+			respond.Error(w, r, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(r, body)
+
+		existing, acquired := s.acquire(key, fingerprint)
+		if !acquired {
+			s.respondExisting(w, r, key, fingerprint, existing)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				s.release(key)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			s.release(key)
+		} else {
+			s.complete(key, rec)
+		}
+		completed = true
+	})
+}
+
+func (s *Store) respondExisting(w http.ResponseWriter, r *http.Request, key, fingerprint string, existing record) {
+	fields := map[string]any{
+		"idempotency_key": key,
+		"path":            r.URL.Path,
+	}
+
+	switch {
+	case existing.fingerprint != fingerprint:
+		logger.WarnContext(r.Context(), "idempotency key reused with different request", fields)
+		respond.Error(w, r, "idempotency key was already used with a different request", http.StatusUnprocessableEntity)
+	case !existing.completed:
+		logger.WarnContext(r.Context(), "request with idempotency key is in progress", fields)
+		respond.Error(w, r, "request with this idempotency key is in progress", http.StatusConflict)
+	default:
+		logger.InfoContext(r.Context(), "replaying idempotent response", fields)
+		for name, values := range existing.header {
+			if name == http.CanonicalHeaderKey(requestid.Header) {
+				continue
+			}
+			w.Header()[name] = values
+		}
+		w.Header().Set(HeaderReplayed, "true")
+		w.WriteHeader(existing.status)
+		w.Write(existing.body)
+	}
+}
+
+func (s *Store) acquire(key, fingerprint string) (record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if existing, ok := s.records[key]; ok && (!existing.completed || now.Before(existing.expiresAt)) {
+		return *existing, false
+	}
+
+	s.records[key] = &record{fingerprint: fingerprint}
+
+	return record{}, true
+}
+
+func (s *Store) complete(key string, rec *recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[key]
+	if !ok {
+		return
+	}
+
+	existing.completed = true
+	existing.status = rec.status
+	existing.header = rec.Header().Clone()
+	existing.body = rec.body.Bytes()
+	existing.expiresAt = s.now().Add(s.ttl)
+}
+
+func (s *Store) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+}
+
+func (s *Store) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, rec := range s.records {
+		if rec.completed && !now.Before(rec.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
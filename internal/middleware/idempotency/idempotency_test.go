@@ -0,0 +1,179 @@
+package idempotency
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+)
+
+func newRequest(key, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	return req
+}
+
+func countingHandler(calls *int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"call":%d,"body":%s}`, n, body)
+	})
+}
+
+func TestMiddleware_NoKeyPassesThrough(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusCreated))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest("", `{}`))
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestMiddleware_ReplaysResponse(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusCreated))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newRequest("key-1", `{"title":"Task"}`))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newRequest("key-1", `{"title":"Task"}`))
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+	assert.Empty(t, first.Header().Get(HeaderReplayed))
+}
+
+func TestMiddleware_ReplayKeepsCurrentRequestID(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusCreated))
+
+	first := httptest.NewRecorder()
+	first.Header().Set(requestid.Header, "first")
+	handler.ServeHTTP(first, newRequest("key-1", `{}`))
+
+	second := httptest.NewRecorder()
+	second.Header().Set(requestid.Header, "second")
+	handler.ServeHTTP(second, newRequest("key-1", `{}`))
+
+	assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+	assert.Equal(t, "second", second.Header().Get(requestid.Header))
+}
+
+func TestMiddleware_DifferentBodyRejected(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusCreated))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", `{"title":"Task"}`))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("key-1", `{"title":"Other"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestMiddleware_ServerErrorNotStored(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusInternalServerError))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest("key-1", `{}`))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	}
+
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestMiddleware_ExpiredKeyExecutesAgain(t *testing.T) {
+	var calls int32
+	store := CreateStore(time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	handler := store.Middleware(countingHandler(&calls, http.StatusCreated))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", `{}`))
+
+	now = now.Add(2 * time.Minute)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("key-1", `{}`))
+
+	assert.Equal(t, int32(2), calls)
+	assert.Empty(t, w.Header().Get(HeaderReplayed))
+}
+
+func TestMiddleware_ConcurrentRequestConflict(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := CreateStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(first, newRequest("key-1", `{}`))
+	}()
+
+	<-started
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newRequest("key-1", `{}`))
+	assert.Equal(t, http.StatusConflict, second.Code)
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, http.StatusCreated, first.Code)
+}
+
+func TestMiddleware_PanicReleasesKey(t *testing.T) {
+	var calls int32
+	store := CreateStore(time.Hour)
+	panicking := store.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	assert.Panics(t, func() {
+		panicking.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", `{}`))
+	})
+
+	w := httptest.NewRecorder()
+	store.Middleware(countingHandler(&calls, http.StatusCreated)).ServeHTTP(w, newRequest("key-1", `{}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	var calls int32
+	handler := CreateStore(time.Hour).Middleware(countingHandler(&calls, http.StatusCreated))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest(strings.Repeat("k", MaxKeyLength+1), `{}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, int32(0), calls)
+}
@@ -1,23 +1,277 @@
 package logging
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
 )
 
-func LoggingMiddleware(next http.Handler) http.Handler {
+const (
+	FormatCommon   = "common"
+	FormatCombined = "combined"
+	FormatJSON     = "json"
+
+	clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+)
+
+type Config struct {
+	Format        string
+	SlowThreshold time.Duration
+	ExcludePaths  []string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Format:        FormatCombined,
+		SlowThreshold: time.Second,
+		ExcludePaths:  []string{"/health", "/metrics"},
+	}
+}
+
+// AccessLog logs one INFO entry per request through the application logger,
+// so access lines share its level filter, redaction, formatter and sink, and
+// reports slow requests as warnings.
+type AccessLog struct {
+	config   Config
+	excluded map[string]bool
+}
+
+func CreateAccessLog(config Config) *AccessLog {
+	excluded := make(map[string]bool, len(config.ExcludePaths))
+	for _, path := range config.ExcludePaths {
+		excluded[path] = true
+	}
+
+	return &AccessLog{
+		config:   config,
+		excluded: excluded,
+	}
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	bytes    int64
+	hijacked bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack is used by the WebSocket upgrade; the handshake response is written
+// to the raw connection, so the request is logged as 101 Switching Protocols.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.hijacked = true
+		if w.status == 0 {
+			w.status = http.StatusSwitchingProtocols
+		}
+	}
+	return conn, rw, err
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type entry struct {
+	time       time.Time
+	remoteAddr string
+	method     string
+	path       string
+	query      string
+	proto      string
+	status     int
+	bytes      int64
+	duration   float64
+	referer    string
+	userAgent  string
+}
+
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	const funcName = "AccessLog.Middleware"
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger.Info("request started", map[string]any{
-			"method": r.Method,
-			"path":   r.URL.Path,
-		})
+		if a.excluded[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		started := time.Now()
+		rw := &responseWriter{ResponseWriter: w}
+
+		defer func() {
+			duration := time.Since(started)
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			e := entry{
+				time:       started,
+				remoteAddr: remoteHost(r.RemoteAddr),
+				method:     r.Method,
+				path:       r.URL.Path,
+				query:      logger.RedactQuery(r.URL.RawQuery),
+				proto:      r.Proto,
+				status:     status,
+				bytes:      rw.bytes,
+				duration:   float64(duration.Microseconds()) / 1000,
+				referer:    r.Referer(),
+				userAgent:  r.UserAgent(),
+			}
+			a.log(r.Context(), funcName, e)
 
-		next.ServeHTTP(w, r)
+			if a.config.SlowThreshold > 0 && duration >= a.config.SlowThreshold && !rw.hijacked && !isStream(rw) {
+				logger.WarnContext(r.Context(), "slow request", map[string]any{
+					"method":       funcName,
+					"http_method":  r.Method,
+					"path":         r.URL.Path,
+					"status":       status,
+					"duration_ms":  e.duration,
+					"threshold_ms": a.config.SlowThreshold.Milliseconds(),
+				})
+			}
+		}()
 
-		logger.Info("request completed", map[string]any{
-			"method": r.Method,
-			"path":   r.URL.Path,
-		})
+		next.ServeHTTP(rw, r)
 	})
 }
+
+// isStream reports whether the response is a Server-Sent Events stream, which
+// stays open by design and must not be reported as slow.
+func isStream(w http.ResponseWriter) bool {
+	return strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream")
+}
+
+// log writes the Common or Combined Log Format line as the message, or, for
+// the json format, a short message with every value as a separate field that
+// the logger redacts like any other.
+// Request and trace IDs are added from the context by the logger.
+func (a *AccessLog) log(ctx context.Context, funcName string, e entry) {
+	fields := map[string]any{
+		"method":      funcName,
+		"duration_ms": e.duration,
+	}
+
+	if a.config.Format == FormatJSON {
+		fields["remote_addr"] = e.remoteAddr
+		fields["http_method"] = e.method
+		fields["path"] = e.path
+		fields["proto"] = e.proto
+		fields["status"] = e.status
+		fields["bytes"] = e.bytes
+		for key, value := range map[string]string{"query": e.query, "referer": e.referer, "user_agent": e.userAgent} {
+			if value != "" {
+				fields[key] = value
+			}
+		}
+		logger.InfoContext(ctx, "request completed", fields)
+		return
+	}
+
+	// The line is redacted part by part: the query when the entry is built,
+	// the referer and user agent here. Run as a whole through the redactor it
+	// would be truncated, and numbers such as "200 12345678" taken for phones.
+	var buf bytes.Buffer
+	writeCommon(&buf, e)
+	if a.config.Format != FormatCommon {
+		fmt.Fprintf(&buf, " %s %s", quote(redactURL(e.referer)), quote(logger.RedactText(e.userAgent)))
+	}
+	logger.InfoContextRedacted(ctx, buf.String(), fields)
+}
+
+// redactURL redacts the query of a URL such as the referer.
+func redactURL(rawURL string) string {
+	base, rawQuery, found := strings.Cut(rawURL, "?")
+	if !found {
+		return rawURL
+	}
+	return base + "?" + logger.RedactQuery(rawQuery)
+}
+
+// writeCommon renders the Common Log Format: host ident authuser [time]
+// "request" status bytes. Identity and user are not known and logged as "-".
+func writeCommon(buf *bytes.Buffer, e entry) {
+	size := "-"
+	if e.bytes > 0 {
+		size = strconv.FormatInt(e.bytes, 10)
+	}
+
+	target := e.path
+	if e.query != "" {
+		target += "?" + e.query
+	}
+
+	fmt.Fprintf(buf, "%s - - [%s] %s %d %s",
+		e.remoteAddr,
+		e.time.Format(clfTimeLayout),
+		quote(e.method+" "+target+" "+e.proto),
+		e.status,
+		size,
+	)
+}
+
+// quote wraps a value in double quotes, escaping quotes, backslashes and
+// non-printable bytes so a client cannot forge extra fields or lines.
+func quote(value string) string {
+	if value == "" {
+		return `"-"`
+	}
+
+	var b bytes.Buffer
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,286 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+type recordingSink struct {
+	entries []logger.LogEntry
+	mu      sync.Mutex
+}
+
+func (s *recordingSink) Write(entry logger.LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+// captureLog sends the global logger to a recording sink for the rest of the
+// test.
+func captureLog(t *testing.T) *recordingSink {
+	t.Helper()
+
+	sink := &recordingSink{}
+	require.NoError(t, logger.Configure(logger.TextFormatter{}, sink))
+	t.Cleanup(func() {
+		logger.Configure(logger.TextFormatter{}, logger.CreateWriterSink(os.Stderr))
+	})
+	return sink
+}
+
+// Entries waits for the logger to write everything logged so far.
+func (s *recordingSink) Entries(t *testing.T) []logger.LogEntry {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, logger.Flush(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]logger.LogEntry(nil), s.entries...)
+}
+
+func newRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.RemoteAddr = "192.0.2.10:54321"
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+	req.Header.Set("Referer", "https://example.com/")
+	return req
+}
+
+func TestAccessLog_Formats(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	})
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, e logger.LogEntry)
+	}{
+		{
+			name:   "common",
+			format: FormatCommon,
+			check: func(t *testing.T, e logger.LogEntry) {
+				assert.Regexp(t, `^192\.0\.2\.10 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "POST /tasks\?x=1 HTTP/1\.1" 201 8$`, e.Message)
+			},
+		},
+		{
+			name:   "combined",
+			format: FormatCombined,
+			check: func(t *testing.T, e logger.LogEntry) {
+				assert.True(t, strings.HasSuffix(e.Message, `"POST /tasks?x=1 HTTP/1.1" 201 8 "https://example.com/" "curl/8.0 \"quoted\""`), e.Message)
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			check: func(t *testing.T, e logger.LogEntry) {
+				assert.Equal(t, "request completed", e.Message)
+				assert.Equal(t, "192.0.2.10", e.Fields["remote_addr"])
+				assert.Equal(t, "POST", e.Fields["http_method"])
+				assert.Equal(t, "/tasks", e.Fields["path"])
+				assert.Equal(t, "x=1", e.Fields["query"])
+				assert.EqualValues(t, http.StatusCreated, e.Fields["status"])
+				assert.EqualValues(t, 8, e.Fields["bytes"])
+				assert.Equal(t, `curl/8.0 "quoted"`, e.Fields["user_agent"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := captureLog(t)
+			accessLog := CreateAccessLog(Config{Format: tt.format})
+
+			req := newRequest(http.MethodPost, "/tasks?x=1")
+			req = req.WithContext(logger.ContextWithFields(req.Context(), map[string]any{"request_id": "req-1"}))
+			accessLog.Middleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+			entries := sink.Entries(t)
+			require.Len(t, entries, 1)
+			assert.Equal(t, logger.LevelInfo, entries[0].Level)
+			assert.Equal(t, "AccessLog.Middleware", entries[0].Fields["method"])
+			assert.Equal(t, "req-1", entries[0].Fields["request_id"])
+			assert.Contains(t, entries[0].Fields, "duration_ms")
+			tt.check(t, entries[0])
+		})
+	}
+}
+
+func TestAccessLog_DefaultsAndExclusions(t *testing.T) {
+	sink := captureLog(t)
+	accessLog := CreateAccessLog(DefaultConfig())
+	handler := accessLog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/health"))
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/metrics"))
+	assert.Empty(t, sink.Entries(t))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/tasks"))
+	entries := sink.Entries(t)
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].Message, `"GET /tasks HTTP/1.1" 200 - `)
+}
+
+func TestAccessLog_EscapesControlCharacters(t *testing.T) {
+	sink := captureLog(t)
+	accessLog := CreateAccessLog(Config{Format: FormatCombined})
+
+	req := newRequest(http.MethodGet, "/tasks")
+	req.Header.Set("User-Agent", "evil\nagent")
+	accessLog.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := sink.Entries(t)
+	require.Len(t, entries, 1)
+	assert.NotContains(t, entries[0].Message, "\n")
+	assert.Contains(t, entries[0].Message, `"evil\x0aagent"`)
+}
+
+func TestAccessLog_RedactsQuery(t *testing.T) {
+	sink := captureLog(t)
+
+	for _, format := range []string{FormatCombined, FormatJSON} {
+		accessLog := CreateAccessLog(Config{Format: format})
+		req := newRequest(http.MethodGet, "/tasks?access_token=s3cr3t&email=jane%40example.com&status=pending")
+		accessLog.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := sink.Entries(t)
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[0].Message, "/tasks?access_token=[REDACTED]&email=[REDACTED:email]&status=pending ")
+	assert.Equal(t, "access_token=[REDACTED]&email=[REDACTED:email]&status=pending", entries[1].Fields["query"])
+	for _, e := range entries {
+		assert.NotContains(t, e.Message, "s3cr3t")
+		assert.NotContains(t, e.Message, "jane")
+	}
+}
+
+func TestAccessLog_LongCombinedLine(t *testing.T) {
+	sink := captureLog(t)
+	accessLog := CreateAccessLog(Config{Format: FormatCombined})
+	handler := accessLog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 12345678))
+	}))
+
+	userAgent := "Mozilla/5.0 " + strings.Repeat("x", 300) + " (contact jane@example.com)"
+	referer := "https://example.com/" + strings.Repeat("p", 300) + "?access_token=s3cr3t&page=2"
+	req := newRequest(http.MethodGet, "/tasks")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Referer", referer)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := sink.Entries(t)
+	require.Len(t, entries, 1)
+	message := entries[0].Message
+	assert.NotContains(t, message, "truncated")
+	assert.Contains(t, message, `"GET /tasks HTTP/1.1" 200 12345678 `)
+	assert.Contains(t, message, `"Mozilla/5.0 `+strings.Repeat("x", 300)+` (contact [REDACTED:email])"`)
+	assert.Contains(t, message, strings.Repeat("p", 300)+`?access_token=[REDACTED]&page=2"`)
+	assert.NotContains(t, message, "s3cr3t")
+}
+
+func TestAccessLog_SlowRequestWarning(t *testing.T) {
+	sink := captureLog(t)
+
+	accessLog := CreateAccessLog(Config{Format: FormatCommon, SlowThreshold: 10 * time.Millisecond})
+	handler := accessLog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/fast"))
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/slow"))
+
+	var warnings []logger.LogEntry
+	for _, e := range sink.Entries(t) {
+		if e.Level == logger.LevelWarn {
+			warnings = append(warnings, e)
+		}
+	}
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "slow request", warnings[0].Message)
+	assert.Equal(t, "/slow", warnings[0].Fields["path"])
+	assert.Equal(t, "AccessLog.Middleware", warnings[0].Fields["method"])
+	assert.Equal(t, http.MethodGet, warnings[0].Fields["http_method"])
+}
+
+func TestAccessLog_ComponentLevel(t *testing.T) {
+	sink := captureLog(t)
+	require.NoError(t, logger.SetLevels(logger.LevelSettings{
+		Global:     logger.LevelInfo,
+		Components: map[string]string{"AccessLog.*": logger.LevelWarn},
+	}))
+	t.Cleanup(func() {
+		logger.SetLevels(logger.LevelSettings{Global: logger.LevelInfo})
+	})
+
+	accessLog := CreateAccessLog(Config{Format: FormatCommon})
+	accessLog.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/tasks"))
+
+	assert.Empty(t, sink.Entries(t))
+}
+
+func TestResponseWriter_FlushAndUnwrap(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: recorder}
+
+	require.NoError(t, http.NewResponseController(rw).Flush())
+
+	assert.Equal(t, http.StatusOK, rw.status)
+	assert.True(t, recorder.Flushed)
+	assert.Same(t, recorder, rw.Unwrap())
+}
+
+func TestResponseWriter_Hijack(t *testing.T) {
+	sink := captureLog(t)
+	accessLog := CreateAccessLog(Config{Format: FormatCommon})
+
+	server := httptest.NewServer(accessLog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: close\r\n\r\n")
+		rw.Flush()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/tasks/ws")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The entry is logged after the handler returns, which may be after the
+	// client has read the response.
+	assert.Eventually(t, func() bool {
+		for _, e := range sink.Entries(t) {
+			if strings.Contains(e.Message, `"GET /tasks/ws HTTP/1.1" 101 -`) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
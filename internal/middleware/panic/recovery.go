@@ -5,17 +5,18 @@ import (
 	"net/http"
 
 	"github.com/supchaser/LO_test_task/internal/utils/logger"
+	"github.com/supchaser/LO_test_task/internal/utils/respond"
 )
 
 func RecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Error("panic recovered", fmt.Errorf("%v", err), map[string]any{
+				logger.ErrorContext(r.Context(), "panic recovered", fmt.Errorf("%v", err), map[string]any{
 					"method": r.Method,
 					"path":   r.URL.Path,
 				})
-				http.Error(w, "internal server error", http.StatusInternalServerError)
+				respond.Error(w, r, "internal server error", http.StatusInternalServerError)
 			}
 		}()
 		next.ServeHTTP(w, r)
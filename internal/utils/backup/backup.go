@@ -0,0 +1,159 @@
+// Package backup reads and writes task store snapshots.
+//
+// A backup file is NDJSON: a header line, one line per task and a trailer
+// with the SHA-256 checksum of the lines before it, each ending in "\n".
+// Converting line endings to "\r\n" therefore keeps a backup valid.
+//
+//	{"format":"tasks-backup","version":1,"taken_at":"...","last_event_id":42,"tasks":2}
+//	{"id":1,"title":"...","description":"","status":"pending","created_at":"...","updated_at":"..."}
+//	{"id":2,...}
+//	{"sha256":"9f86d0..."}
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/taskcodec"
+)
+
+const (
+	formatName = "tasks-backup"
+	version    = 1
+
+	maxLineSize = 1 << 20
+
+	// ContentType is what the backup endpoint responds with.
+	ContentType = "application/x-ndjson"
+)
+
+var (
+	ErrInvalid          = errors.New("invalid backup file")
+	ErrChecksumMismatch = errors.New("backup checksum mismatch")
+)
+
+type header struct {
+	Format      string    `json:"format"`
+	Version     int       `json:"version"`
+	TakenAt     time.Time `json:"taken_at"`
+	LastEventID uint64    `json:"last_event_id"`
+	Tasks       int       `json:"tasks"`
+}
+
+type trailer struct {
+	SHA256 string `json:"sha256"`
+}
+
+// Write writes the snapshot and returns the hex-encoded checksum stored in the
+// trailer.
+func Write(w io.Writer, snapshot *models.Snapshot) (string, error) {
+	sum := sha256.New()
+	body := io.MultiWriter(w, sum)
+
+	err := json.NewEncoder(body).Encode(header{
+		Format:      formatName,
+		Version:     version,
+		TakenAt:     snapshot.TakenAt,
+		LastEventID: snapshot.LastEventID,
+		Tasks:       len(snapshot.Tasks),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoder := taskcodec.CreateEncoder(body, taskcodec.FormatNDJSON)
+	for _, task := range snapshot.Tasks {
+		if err := encoder.Encode(task); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	checksum := checksumOf(sum)
+	if err := json.NewEncoder(w).Encode(trailer{SHA256: checksum}); err != nil {
+		return "", err
+	}
+	return checksum, nil
+}
+
+// Read verifies the checksum of a backup before decoding it, so a damaged
+// file is reported as ErrChecksumMismatch rather than as whatever the damage
+// happens to break. It returns the snapshot and the verified checksum; errors
+// of r are returned as they are.
+func Read(r io.Reader) (*models.Snapshot, string, error) {
+	var lines [][]byte
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	for scanner.Scan() {
+		lines = append(lines, bytes.Clone(scanner.Bytes()))
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, "", fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalid, len(lines)+1, maxLineSize)
+		}
+		return nil, "", err
+	}
+
+	if len(lines) < 2 {
+		return nil, "", fmt.Errorf("%w: too short, the file may be truncated", ErrInvalid)
+	}
+
+	var t trailer
+	if err := json.Unmarshal(lines[len(lines)-1], &t); err != nil || t.SHA256 == "" {
+		return nil, "", fmt.Errorf("%w: no checksum line, the file may be truncated", ErrInvalid)
+	}
+	lines = lines[:len(lines)-1]
+
+	sum := sha256.New()
+	for _, line := range lines {
+		sum.Write(line)
+		sum.Write([]byte{'\n'})
+	}
+	if checksum := checksumOf(sum); checksum != t.SHA256 {
+		return nil, "", fmt.Errorf("%w: file has %s, expected %s", ErrChecksumMismatch, checksum, t.SHA256)
+	}
+
+	var h header
+	if err := json.Unmarshal(lines[0], &h); err != nil || h.Format != formatName {
+		return nil, "", fmt.Errorf("%w: missing header", ErrInvalid)
+	}
+	if h.Version != version {
+		return nil, "", fmt.Errorf("%w: unsupported version %d", ErrInvalid, h.Version)
+	}
+	if h.Tasks != len(lines)-1 {
+		return nil, "", fmt.Errorf("%w: header lists %d tasks, file has %d", ErrInvalid, h.Tasks, len(lines)-1)
+	}
+
+	snapshot := &models.Snapshot{
+		TakenAt:     h.TakenAt,
+		LastEventID: h.LastEventID,
+		Tasks:       make([]*models.Task, 0, h.Tasks),
+	}
+	for i, line := range lines[1:] {
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+
+		var task models.Task
+		if err := decoder.Decode(&task); err != nil {
+			return nil, "", fmt.Errorf("%w: line %d: %v", ErrInvalid, i+2, err)
+		}
+		snapshot.Tasks = append(snapshot.Tasks, &task)
+	}
+
+	return snapshot, t.SHA256, nil
+}
+
+func checksumOf(sum hash.Hash) string {
+	return hex.EncodeToString(sum.Sum(nil))
+}
@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+var testSnapshot = &models.Snapshot{
+	TakenAt:     time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	LastEventID: 42,
+	Tasks: []*models.Task{
+		{
+			ID:          1,
+			Title:       "First task",
+			Description: "Line one\nline two",
+			Status:      models.StatusPending,
+			CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC),
+			UpdatedAt:   time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			ID:        2,
+			Title:     "Second task",
+			Status:    models.StatusCompleted,
+			CreatedAt: time.Date(2024, 2, 2, 3, 4, 5, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 2, 3, 3, 4, 5, 0, time.UTC),
+		},
+	},
+}
+
+func write(t *testing.T, snapshot *models.Snapshot) (string, string) {
+	t.Helper()
+
+	var b bytes.Buffer
+	checksum, err := Write(&b, snapshot)
+	require.NoError(t, err)
+	return b.String(), checksum
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, snapshot := range []*models.Snapshot{testSnapshot, {TakenAt: testSnapshot.TakenAt}} {
+		data, checksum := write(t, snapshot)
+
+		restored, readChecksum, err := Read(strings.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, checksum, readChecksum)
+		assert.Equal(t, snapshot.TakenAt, restored.TakenAt)
+		assert.Equal(t, snapshot.LastEventID, restored.LastEventID)
+		assert.Len(t, restored.Tasks, len(snapshot.Tasks))
+		for i, task := range restored.Tasks {
+			assert.Equal(t, *snapshot.Tasks[i], *task)
+		}
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	data, _ := write(t, testSnapshot)
+	lines := strings.SplitAfter(data, "\n")
+
+	// signed adds a valid trailer, so only the contents are wrong.
+	signed := func(body string) string {
+		sum := sha256.Sum256([]byte(body))
+		return body + `{"sha256":"` + hex.EncodeToString(sum[:]) + `"}` + "\n"
+	}
+	body := strings.Join(lines[:3], "")
+
+	tests := []struct {
+		name     string
+		data     string
+		expected error
+	}{
+		{name: "Empty", data: "", expected: ErrInvalid},
+		{name: "Truncated", data: strings.Join(lines[:2], ""), expected: ErrInvalid},
+		{name: "Changed Task", data: strings.Replace(data, "First task", "Fir5t task", 1), expected: ErrChecksumMismatch},
+		{name: "Dropped Task", data: lines[0] + lines[2] + lines[3], expected: ErrChecksumMismatch},
+		{name: "Line Endings Converted", data: strings.ReplaceAll(data, "\n", "\r\n")},
+		{name: "Not A Backup", data: "{\"title\":\"Task\"}\n{\"title\":\"Task\"}\n", expected: ErrInvalid},
+		{name: "Unsupported Version", data: signed(strings.Replace(body, `"version":1`, `"version":2`, 1)), expected: ErrInvalid},
+		{name: "Task Count Mismatch", data: signed(lines[0] + lines[1]), expected: ErrInvalid},
+		{name: "Unknown Task Field", data: signed(strings.Replace(body, `"id":2`, `"id":2,"color":"red"`, 1)), expected: ErrInvalid},
+		{name: "Valid When Signed", data: signed(body)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(strings.NewReader(tt.data))
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRead_ReadErrorsPassThrough(t *testing.T) {
+	readErr := errors.New("connection reset")
+	_, _, err := Read(iotest.ErrReader(readErr))
+	assert.ErrorIs(t, err, readErr)
+	assert.NotErrorIs(t, err, ErrInvalid)
+}
@@ -1,9 +1,32 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrTaskNotFound = errors.New("task not found")
-	ErrInvalidID    = errors.New("invalid task ID")
-	ErrValidation   = errors.New("validation error")
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrInvalidID     = errors.New("invalid task ID")
+	ErrValidation    = errors.New("validation error")
+	ErrBatchTooLarge = errors.New("batch is too large")
+	ErrBatchAborted  = errors.New("batch aborted")
+	ErrTxDone        = errors.New("transaction has already been committed or rolled back")
+	ErrStreamClosed  = errors.New("event stream is closed")
+	ErrStoreNotEmpty = errors.New("task store is not empty")
+
+	ErrWebhookNotFound = errors.New("webhook not found")
 )
+
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
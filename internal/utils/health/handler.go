@@ -0,0 +1,33 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+func (r *Registry) LivezHandler() http.Handler {
+	return reportHandler(r.Liveness)
+}
+
+func (r *Registry) ReadyzHandler() http.Handler {
+	return reportHandler(r.Readiness)
+}
+
+// reportHandler answers 200 when every check passes and 503 otherwise, with
+// the per-check details as JSON.
+func reportHandler(probe func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := probe(req.Context())
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	})
+}
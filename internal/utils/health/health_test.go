@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passing(context.Context) error { return nil }
+
+func decodeReport(t *testing.T, w *httptest.ResponseRecorder) Report {
+	t.Helper()
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return report
+}
+
+func TestRegistry_Handlers(t *testing.T) {
+	registry := CreateRegistry(time.Second)
+	registry.RegisterLiveness("logger", passing)
+	registry.RegisterReadiness("storage", passing)
+	registry.RegisterReadiness("webhooks", func(context.Context) error { return errors.New("delivery queue is full") })
+
+	w := httptest.NewRecorder()
+	registry.LivezHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	live := decodeReport(t, w)
+	assert.Equal(t, StatusOK, live.Status)
+	assert.Equal(t, []string{"logger"}, keys(live.Checks))
+
+	w = httptest.NewRecorder()
+	registry.ReadyzHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	ready := decodeReport(t, w)
+	assert.Equal(t, StatusFailing, ready.Status)
+	assert.Equal(t, StatusOK, ready.Checks["logger"].Status)
+	assert.Equal(t, StatusOK, ready.Checks["storage"].Status)
+	assert.Equal(t, CheckResult{Status: StatusFailing, Error: "delivery queue is full", DurationMS: ready.Checks["webhooks"].DurationMS}, ready.Checks["webhooks"])
+}
+
+func TestRegistry_CheckTimeout(t *testing.T) {
+	registry := CreateRegistry(20 * time.Millisecond)
+	registry.RegisterReadiness("honours_context", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	registry.RegisterReadiness("ignores_context", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	started := time.Now()
+	report := registry.Readiness(context.Background())
+
+	assert.Less(t, time.Since(started), 500*time.Millisecond)
+	assert.Equal(t, StatusFailing, report.Status)
+	assert.Equal(t, errTimeout.Error(), report.Checks["honours_context"].Error)
+	assert.Equal(t, errTimeout.Error(), report.Checks["ignores_context"].Error)
+}
+
+func TestRegistry_ShuttingDown(t *testing.T) {
+	registry := CreateRegistry(time.Second)
+	registry.RegisterReadiness("storage", passing)
+	require.Equal(t, StatusOK, registry.Readiness(context.Background()).Status)
+
+	registry.SetShuttingDown()
+
+	ready := registry.Readiness(context.Background())
+	assert.Equal(t, StatusFailing, ready.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), ready.Checks["shutdown"].Error)
+	assert.Equal(t, StatusOK, registry.Liveness(context.Background()).Status)
+}
+
+func keys(checks map[string]CheckResult) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	return names
+}
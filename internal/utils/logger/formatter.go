@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formatter renders a single entry without a trailing newline; line framing
+// is left to the sink.
+type Formatter interface {
+	Format(entry LogEntry) ([]byte, error)
+}
+
+// TextFormatter renders "[time] LEVEL: message key=value ..." with fields
+// sorted by key, so equal entries always produce equal lines.
+type TextFormatter struct{}
+
+func (TextFormatter) Format(entry LogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "[%s] %s: %s", entry.Timestamp.Format(time.RFC3339), entry.Level, entry.Message)
+	for _, key := range sortedKeys(entry.Fields) {
+		fmt.Fprintf(&buf, " %s=%v", key, entry.Fields[key])
+	}
+
+	return buf.Bytes(), nil
+}
+
+// JSONFormatter renders one JSON object per entry: time, level and message
+// first, then the fields sorted by key. A field that would shadow one of the
+// leading keys is written with a "fields." prefix.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(entry LogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	writeJSONMember(&buf, "time", entry.Timestamp.Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeJSONMember(&buf, "level", entry.Level)
+	buf.WriteByte(',')
+	writeJSONMember(&buf, "message", entry.Message)
+
+	for _, key := range sortedKeys(entry.Fields) {
+		value, err := json.Marshal(jsonValue(entry.Fields[key]))
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(entry.Fields[key]))
+		}
+
+		name := key
+		if name == "time" || name == "level" || name == "message" {
+			name = "fields." + name
+		}
+
+		buf.WriteByte(',')
+		writeJSONString(&buf, name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// jsonValue keeps errors and other Stringers readable instead of encoding
+// them as empty objects.
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return value
+	}
+}
+
+func writeJSONMember(buf *bytes.Buffer, key, value string) {
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+	writeJSONString(buf, value)
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s)
+	buf.Write(encoded)
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func CreateFormatter(format string) (Formatter, error) {
+	switch format {
+	case FormatText, "":
+		return TextFormatter{}, nil
+	case FormatJSON:
+		return JSONFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("logger: unknown format %q", format)
+	}
+}
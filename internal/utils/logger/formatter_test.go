@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testEntry() LogEntry {
+	return LogEntry{
+		Level:     LevelError,
+		Message:   "task update failed",
+		Timestamp: time.Date(2025, 7, 31, 11, 17, 18, 0, time.UTC),
+		Fields: map[string]any{
+			"task_id": 7,
+			"method":  "Usecase.UpdateTask",
+			"error":   errors.New("task not found"),
+			"level":   "shadowed",
+		},
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	line, err := TextFormatter{}.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	expected := "[2025-07-31T11:17:18Z] ERROR: task update failed error=task not found level=shadowed method=Usecase.UpdateTask task_id=7"
+	if string(line) != expected {
+		t.Errorf("Expected %q, got %q", expected, line)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	line, err := JSONFormatter{}.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	expected := `{"time":"2025-07-31T11:17:18Z","level":"ERROR","message":"task update failed","error":"task not found","fields.level":"shadowed","method":"Usecase.UpdateTask","task_id":7}`
+	if string(line) != expected {
+		t.Errorf("Expected %s, got %s", expected, line)
+	}
+
+	if !json.Valid(line) {
+		t.Error("Expected valid JSON")
+	}
+}
+
+func TestJSONFormatterUnsupportedValue(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Message:   "odd value",
+		Timestamp: time.Date(2025, 7, 31, 11, 17, 18, 0, time.UTC),
+		Fields:    map[string]any{"ch": make(chan int)},
+	}
+
+	line, err := JSONFormatter{}.Format(entry)
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if !json.Valid(line) {
+		t.Errorf("Expected valid JSON, got %s", line)
+	}
+}
+
+func TestCreateFormatter(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    Formatter
+		wantErr bool
+	}{
+		{format: "", want: TextFormatter{}},
+		{format: FormatText, want: TextFormatter{}},
+		{format: FormatJSON, want: JSONFormatter{}},
+		{format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := CreateFormatter(tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CreateFormatter(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CreateFormatter(%q) = %T, want %T", tt.format, got, tt.want)
+		}
+	}
+}
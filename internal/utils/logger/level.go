@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var levelRanks = map[string]int{
+	LevelTrace: 0,
+	LevelDebug: 1,
+	LevelInfo:  2,
+	LevelWarn:  3,
+	LevelError: 4,
+	LevelFatal: 5,
+}
+
+// ParseLevel normalizes a level name such as "debug" to its constant.
+func ParseLevel(level string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(level))
+	if _, ok := levelRanks[normalized]; !ok {
+		return "", fmt.Errorf("logger: unknown level %q", level)
+	}
+
+	return normalized, nil
+}
+
+// LevelSettings holds the minimum level written globally and per component.
+// A component is matched against the "method" field of an entry, either
+// exactly ("Repository.GetTaskByID") or by prefix ("Repository.*"); the most
+// specific pattern wins.
+type LevelSettings struct {
+	Global     string            `json:"global"`
+	Components map[string]string `json:"components"`
+}
+
+// Validate reports the error SetLevels would return for these settings.
+func (s LevelSettings) Validate() error {
+	_, err := newLevelFilter(s)
+	return err
+}
+
+type componentLevel struct {
+	pattern string
+	prefix  string
+	exact   bool
+	rank    int
+}
+
+// levelFilter is immutable once built, so it can be swapped atomically while
+// other goroutines are logging.
+type levelFilter struct {
+	settings   LevelSettings
+	global     int
+	lowest     int
+	components []componentLevel
+}
+
+func newLevelFilter(settings LevelSettings) (*levelFilter, error) {
+	global, err := ParseLevel(settings.Global)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &levelFilter{
+		settings: LevelSettings{Global: global, Components: make(map[string]string, len(settings.Components))},
+		global:   levelRanks[global],
+		lowest:   levelRanks[global],
+	}
+
+	for pattern, level := range settings.Components {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || pattern == "*" || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return nil, fmt.Errorf("logger: invalid component pattern %q", pattern)
+		}
+
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+
+		filter.settings.Components[pattern] = parsed
+		filter.components = append(filter.components, componentLevel{
+			pattern: pattern,
+			prefix:  strings.TrimSuffix(pattern, "*"),
+			exact:   !strings.HasSuffix(pattern, "*"),
+			rank:    levelRanks[parsed],
+		})
+		filter.lowest = min(filter.lowest, levelRanks[parsed])
+	}
+
+	// Exact patterns first, then longer prefixes, so the first match is the
+	// most specific one.
+	sort.Slice(filter.components, func(i, j int) bool {
+		a, b := filter.components[i], filter.components[j]
+		if a.exact != b.exact {
+			return a.exact
+		}
+		if len(a.prefix) != len(b.prefix) {
+			return len(a.prefix) > len(b.prefix)
+		}
+		return a.pattern < b.pattern
+	})
+
+	return filter, nil
+}
+
+func (f *levelFilter) enabled(level string, fields map[string]any) bool {
+	rank, ok := levelRanks[level]
+	if !ok || level == LevelFatal {
+		return true
+	}
+
+	threshold := f.global
+	if method, ok := fields["method"].(string); ok && method != "" {
+		for _, c := range f.components {
+			if (c.exact && method == c.pattern) || (!c.exact && strings.HasPrefix(method, c.prefix)) {
+				threshold = c.rank
+				break
+			}
+		}
+	}
+
+	return rank >= threshold
+}
+
+func SetLevels(settings LevelSettings) error {
+	return Get().SetLevels(settings)
+}
+
+func Levels() LevelSettings {
+	return Get().Levels()
+}
+
+// SetLevels replaces the thresholds; on error the current ones are kept.
+func (l *Logger) SetLevels(settings LevelSettings) error {
+	filter, err := newLevelFilter(settings)
+	if err != nil {
+		return err
+	}
+
+	l.levels.Store(filter)
+	return nil
+}
+
+func (l *Logger) Levels() LevelSettings {
+	settings := l.levels.Load().settings
+
+	components := make(map[string]string, len(settings.Components))
+	for pattern, level := range settings.Components {
+		components[pattern] = level
+	}
+
+	return LevelSettings{Global: settings.Global, Components: components}
+}
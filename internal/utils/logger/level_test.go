@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "debug", want: LevelDebug},
+		{input: " Trace ", want: LevelTrace},
+		{input: "ERROR", want: LevelError},
+		{input: "verbose", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLevelFilter(t *testing.T) {
+	filter, err := newLevelFilter(LevelSettings{
+		Global: "info",
+		Components: map[string]string{
+			"Repository.*":          "warn",
+			"Repository.CreateTask": "debug",
+			"Repository.Webhook*":   "error",
+			"Usecase.*":             "trace",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newLevelFilter returned error: %v", err)
+	}
+
+	tests := []struct {
+		level  string
+		method any
+		want   bool
+	}{
+		{level: LevelInfo, method: nil, want: true},
+		{level: LevelDebug, method: nil, want: false},
+		{level: LevelDebug, method: "Delivery.GetTask", want: false},
+		{level: LevelInfo, method: "Repository.GetTaskByID", want: false},
+		{level: LevelWarn, method: "Repository.GetTaskByID", want: true},
+		{level: LevelDebug, method: "Repository.CreateTask", want: true},
+		{level: LevelWarn, method: "Repository.WebhookCreate", want: false},
+		{level: LevelTrace, method: "Usecase.GetTask", want: true},
+		{level: LevelFatal, method: "Repository.WebhookCreate", want: true},
+		{level: LevelDebug, method: 42, want: false},
+	}
+
+	for _, tt := range tests {
+		fields := map[string]any{}
+		if tt.method != nil {
+			fields["method"] = tt.method
+		}
+
+		if got := filter.enabled(tt.level, fields); got != tt.want {
+			t.Errorf("enabled(%s, %v) = %v, want %v", tt.level, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestLevelFilterInvalidSettings(t *testing.T) {
+	tests := []LevelSettings{
+		{Global: "loud"},
+		{Global: LevelInfo, Components: map[string]string{"Repository.*": "loud"}},
+		{Global: LevelInfo, Components: map[string]string{"*": LevelDebug}},
+		{Global: LevelInfo, Components: map[string]string{"Repo*.Get": LevelDebug}},
+	}
+
+	for _, settings := range tests {
+		if _, err := newLevelFilter(settings); err == nil {
+			t.Errorf("Expected error for settings %+v", settings)
+		}
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	read := captureGlobal(t)
+
+	previous := Levels()
+	defer SetLevels(previous)
+
+	if err := SetLevels(LevelSettings{Global: "warn", Components: map[string]string{"Repository.*": "debug"}}); err != nil {
+		t.Fatalf("SetLevels returned error: %v", err)
+	}
+	if err := SetLevels(LevelSettings{Global: "loud"}); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+
+	settings := Levels()
+	if settings.Global != LevelWarn || settings.Components["Repository.*"] != LevelDebug {
+		t.Errorf("Expected invalid update to keep previous settings, got %+v", settings)
+	}
+
+	Info("filtered info", nil)
+	Warn("visible warn", nil)
+	Debug("repository debug", map[string]any{"method": "Repository.GetTaskByID"})
+	Trace("repository trace", map[string]any{"method": "Repository.GetTaskByID"})
+
+	output := read()
+
+	for _, exp := range []string{"visible warn", "DEBUG: repository debug"} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected log output to contain %q", exp)
+		}
+	}
+	for _, unexpected := range []string{"filtered info", "repository trace"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected log output not to contain %q", unexpected)
+		}
+	}
+}
@@ -1,20 +1,35 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"maps"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
+	LevelTrace = "TRACE"
+	LevelDebug = "DEBUG"
 	LevelInfo  = "INFO"
 	LevelWarn  = "WARN"
 	LevelError = "ERROR"
 	LevelFatal = "FATAL"
 )
 
+const (
+	PolicyBlock      = "block"
+	PolicyDropNewest = "drop_newest"
+	PolicyDropOldest = "drop_oldest"
+
+	defaultBufferSize = 1024
+	fatalFlushTimeout = 5 * time.Second
+)
+
 var (
 	globalLogger *Logger
 	initOnce     sync.Once
@@ -27,72 +42,306 @@ type LogEntry struct {
 	Message   string
 	Timestamp time.Time
 	Fields    map[string]any
+
+	// flushed is set on the markers queued by Flush instead of a log line.
+	flushed chan struct{}
+	// redacted marks a message the caller has already redacted; see
+	// InfoContextRedacted.
+	redacted bool
+}
+
+// Options control the queue between callers and the writer goroutine.
+// Policy decides what happens when the queue is full: block waits for free
+// space, drop_newest discards the entry being logged and drop_oldest
+// discards the oldest queued entry to make room.
+type Options struct {
+	BufferSize int
+	Policy     string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BufferSize: defaultBufferSize,
+		Policy:     PolicyDropNewest,
+	}
 }
 
 type Logger struct {
-	logChan  chan LogEntry
-	stopChan chan struct{}
-	wg       sync.WaitGroup
+	logChan   chan LogEntry
+	flushChan chan LogEntry
+	stopChan  chan struct{}
+	done      chan struct{}
+	policy    string
+	dropped   atomic.Uint64
+	levels    atomic.Pointer[levelFilter]
+	redactor  atomic.Pointer[Redactor]
+
+	// closeMu is held for reading while an entry is being queued, so Close
+	// cannot stop the writer between the closed check and the send.
+	closeMu   sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+
+	formatter Formatter
+	sink      Sink
+	outputMu  sync.Mutex
+}
+
+type QueueStats struct {
+	Queued   int
+	Capacity int
+	Dropped  uint64
+	Policy   string
+}
+
+func CreateLogger(opts Options) (*Logger, error) {
+	if opts.BufferSize <= 0 {
+		return nil, fmt.Errorf("logger: buffer size must be positive, got %d", opts.BufferSize)
+	}
+	switch opts.Policy {
+	case PolicyBlock, PolicyDropNewest, PolicyDropOldest:
+	default:
+		return nil, fmt.Errorf("logger: unknown overflow policy %q", opts.Policy)
+	}
+
+	l := &Logger{
+		logChan:   make(chan LogEntry, opts.BufferSize),
+		flushChan: make(chan LogEntry),
+		stopChan:  make(chan struct{}),
+		done:      make(chan struct{}),
+		policy:    opts.Policy,
+		formatter: TextFormatter{},
+		sink:      stdLogSink{},
+	}
+	l.SetLevels(LevelSettings{Global: LevelInfo})
+	redactor, _ := CreateRedactor(DefaultRedactionConfig())
+	l.SetRedactor(redactor)
+
+	go l.processLogs()
+
+	return l, nil
 }
 
 func Init() *Logger {
+	return InitWithOptions(DefaultOptions())
+}
+
+// InitWithOptions creates the global logger. Only the first call, or the
+// first use of any logging function, takes effect; invalid options fall back
+// to the defaults.
+func InitWithOptions(opts Options) *Logger {
 	initOnce.Do(func() {
-		globalLogger = &Logger{
-			logChan:  make(chan LogEntry, 100),
-			stopChan: make(chan struct{}),
+		l, err := CreateLogger(opts)
+		if err != nil {
+			log.Printf("%v, using default options", err)
+			l, _ = CreateLogger(DefaultOptions())
 		}
-
-		globalLogger.wg.Add(1)
-		go globalLogger.processLogs()
+		globalLogger = l
 	})
 	return globalLogger
 }
 
 func Get() *Logger {
-	if globalLogger == nil {
-		return Init()
-	}
-	return globalLogger
+	return Init()
 }
 
 func Close() {
-	if globalLogger != nil {
-		globalLogger.Close()
+	Get().Close()
+}
+
+// Flush waits until every entry logged before the call has been written or
+// dropped.
+func Flush(ctx context.Context) error {
+	return Get().Flush(ctx)
+}
+
+// Configure replaces the formatter and sink of the global logger. The
+// previous sink is closed once entries stop being written to it.
+func Configure(formatter Formatter, sink Sink) error {
+	return Get().Configure(formatter, sink)
+}
+
+func (l *Logger) Configure(formatter Formatter, sink Sink) error {
+	l.outputMu.Lock()
+	defer l.outputMu.Unlock()
+
+	previous := l.sink
+	l.formatter = formatter
+	l.sink = sink
+
+	return previous.Close()
+}
+
+// SetRedactor replaces the redaction applied to every entry before it is
+// formatted; nil disables redaction.
+func SetRedactor(r *Redactor) {
+	Get().SetRedactor(r)
+}
+
+func (l *Logger) SetRedactor(r *Redactor) {
+	l.redactor.Store(r)
+}
+
+// RedactQuery applies the current redactor to a raw URL query, for callers
+// that log a query as part of a larger string.
+func RedactQuery(rawQuery string) string {
+	return Get().RedactQuery(rawQuery)
+}
+
+func (l *Logger) RedactQuery(rawQuery string) string {
+	redactor := l.redactor.Load()
+	if redactor == nil {
+		return rawQuery
+	}
+	return redactor.RedactQuery(rawQuery)
+}
+
+// RedactText applies the current redactor's detectors to a value without
+// truncating it, for callers that log the value as part of a larger string.
+func RedactText(value string) string {
+	return Get().RedactText(value)
+}
+
+func (l *Logger) RedactText(value string) string {
+	redactor := l.redactor.Load()
+	if redactor == nil {
+		return value
+	}
+	return redactor.RedactText(value)
+}
+
+// Stats reports the depth of the log channel and how many entries were
+// discarded instead of being written.
+func Stats() QueueStats {
+	return Get().Stats()
+}
+
+func (l *Logger) Stats() QueueStats {
+	return QueueStats{
+		Queued:   len(l.logChan),
+		Capacity: cap(l.logChan),
+		Dropped:  l.dropped.Load(),
+		Policy:   l.policy,
 	}
 }
 
 func (l *Logger) processLogs() {
-	defer l.wg.Done()
+	defer close(l.done)
 
 	for {
 		select {
 		case entry := <-l.logChan:
-			logMsg := formatLogEntry(entry)
-			log.Println(logMsg)
-
-			if entry.Level == LevelFatal {
-				osExit(1)
-			}
+			l.handle(entry)
+		case marker := <-l.flushChan:
+			close(marker.flushed)
 		case <-l.stopChan:
-			return
+			for {
+				select {
+				case entry := <-l.logChan:
+					l.handle(entry)
+				default:
+					return
+				}
+			}
 		}
 	}
 }
 
+func (l *Logger) handle(entry LogEntry) {
+	if entry.flushed != nil {
+		close(entry.flushed)
+		return
+	}
+	l.write(entry)
+}
+
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a context carrying fields that the *Context
+// logging functions add to every entry, e.g. the request ID.
+func ContextWithFields(ctx context.Context, fields map[string]any) context.Context {
+	merged := make(map[string]any, len(fields))
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+func FieldsFromContext(ctx context.Context) map[string]any {
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]any)
+	return fields
+}
+
+func Trace(message string, fields map[string]any) {
+	logFields(context.Background(), SlogLevelTrace, message, fields)
+}
+
+func Tracef(format string, args ...any) {
+	logFields(context.Background(), SlogLevelTrace, fmt.Sprintf(format, args...), nil)
+}
+
+func TraceContext(ctx context.Context, message string, fields map[string]any) {
+	logFields(ctx, SlogLevelTrace, message, fields)
+}
+
+func Debug(message string, fields map[string]any) {
+	logFields(context.Background(), slog.LevelDebug, message, fields)
+}
+
+func Debugf(format string, args ...any) {
+	logFields(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...), nil)
+}
+
+func DebugContext(ctx context.Context, message string, fields map[string]any) {
+	logFields(ctx, slog.LevelDebug, message, fields)
+}
+
 func Info(message string, fields map[string]any) {
-	Get().log(LevelInfo, message, fields)
+	logFields(context.Background(), slog.LevelInfo, message, fields)
 }
 
 func Infof(format string, args ...any) {
-	Get().log(LevelInfo, fmt.Sprintf(format, args...), nil)
+	logFields(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+func InfoContext(ctx context.Context, message string, fields map[string]any) {
+	logFields(ctx, slog.LevelInfo, message, fields)
+}
+
+// InfoContextRedacted logs a message the caller has already redacted piece by
+// piece, such as an access log line: the redactor leaves the message whole,
+// where it would otherwise truncate it and run the detectors over the line,
+// and only processes the fields.
+func InfoContextRedacted(ctx context.Context, message string, fields map[string]any) {
+	contextFields := FieldsFromContext(ctx)
+
+	merged := make(map[string]any, len(contextFields)+len(fields))
+	maps.Copy(merged, contextFields)
+	maps.Copy(merged, fields)
+
+	Get().logEntry(LogEntry{
+		Level:     LevelInfo,
+		Message:   message,
+		Timestamp: time.Now(),
+		Fields:    merged,
+		redacted:  true,
+	})
 }
 
 func Warn(message string, fields map[string]any) {
-	Get().log(LevelWarn, message, fields)
+	logFields(context.Background(), slog.LevelWarn, message, fields)
 }
 
 func Warnf(format string, args ...any) {
-	Get().log(LevelWarn, fmt.Sprintf(format, args...), nil)
+	logFields(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...), nil)
+}
+
+func WarnContext(ctx context.Context, message string, fields map[string]any) {
+	logFields(ctx, slog.LevelWarn, message, fields)
 }
 
 func Error(message string, err error, fields map[string]any) {
@@ -102,11 +351,21 @@ func Error(message string, err error, fields map[string]any) {
 	if err != nil {
 		fields["error"] = err.Error()
 	}
-	Get().log(LevelError, message, fields)
+	logFields(context.Background(), slog.LevelError, message, fields)
 }
 
 func Errorf(format string, args ...any) {
-	Get().log(LevelError, fmt.Sprintf(format, args...), nil)
+	logFields(context.Background(), slog.LevelError, fmt.Sprintf(format, args...), nil)
+}
+
+func ErrorContext(ctx context.Context, message string, err error, fields map[string]any) {
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	logFields(ctx, slog.LevelError, message, fields)
 }
 
 func Fatal(message string, err error, fields map[string]any) {
@@ -116,41 +375,161 @@ func Fatal(message string, err error, fields map[string]any) {
 	if err != nil {
 		fields["error"] = err.Error()
 	}
-	Get().log(LevelFatal, message, fields)
+	Get().fatal(message, fields)
 }
 
 func Fatalf(format string, args ...any) {
-	Get().log(LevelFatal, fmt.Sprintf(format, args...), nil)
+	Get().fatal(fmt.Sprintf(format, args...), nil)
+}
+
+// fatal writes the entry from the calling goroutine after the entries queued
+// before it, so nothing is lost when the process exits.
+func (l *Logger) fatal(message string, fields map[string]any) {
+	ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	l.Flush(ctx)
+	cancel()
+
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	l.write(LogEntry{
+		Level:     LevelFatal,
+		Message:   message,
+		Timestamp: time.Now(),
+		Fields:    fields,
+	})
+
+	osExit(1)
 }
 
 func (l *Logger) log(level, message string, fields map[string]any) {
 	if fields == nil {
 		fields = make(map[string]any)
 	}
-	l.logChan <- LogEntry{
+	l.logEntry(LogEntry{
 		Level:     level,
 		Message:   message,
 		Timestamp: time.Now(),
 		Fields:    fields,
+	})
+}
+
+func (l *Logger) logEntry(entry LogEntry) {
+	if filter := l.levels.Load(); filter != nil && !filter.enabled(entry.Level, entry.Fields) {
+		return
 	}
+
+	l.enqueue(entry)
 }
 
-func (l *Logger) Close() {
-	close(l.stopChan)
-	l.wg.Wait()
+func (l *Logger) enqueue(entry LogEntry) {
+	l.closeMu.RLock()
+	defer l.closeMu.RUnlock()
+
+	if l.closed {
+		l.dropped.Add(1)
+		return
+	}
+
+	switch l.policy {
+	case PolicyBlock:
+		l.logChan <- entry
+	case PolicyDropOldest:
+		for {
+			select {
+			case l.logChan <- entry:
+				return
+			default:
+			}
+
+			select {
+			case oldest := <-l.logChan:
+				if oldest.flushed != nil {
+					// Everything queued before the marker has already been
+					// taken by the writer, so it goes straight to the writer
+					// instead of being dropped.
+					l.flushChan <- oldest
+				} else {
+					l.dropped.Add(1)
+				}
+			default:
+			}
+		}
+	default:
+		select {
+		case l.logChan <- entry:
+		default:
+			l.dropped.Add(1)
+		}
+	}
+}
+
+func (l *Logger) Flush(ctx context.Context) error {
+	marker := LogEntry{flushed: make(chan struct{})}
+
+	l.closeMu.RLock()
+	if l.closed {
+		l.closeMu.RUnlock()
+		// The writer drains the queue before it stops.
+		select {
+		case <-l.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case l.logChan <- marker:
+	case <-ctx.Done():
+		l.closeMu.RUnlock()
+		return ctx.Err()
+	}
+	l.closeMu.RUnlock()
+
+	select {
+	case <-marker.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func formatLogEntry(entry LogEntry) string {
-	fieldsStr := ""
-	for k, v := range entry.Fields {
-		fieldsStr += fmt.Sprintf("%s=%v ", k, v)
+func (l *Logger) write(entry LogEntry) {
+	if redactor := l.redactor.Load(); redactor != nil {
+		entry = redactor.Redact(entry)
+	}
+
+	l.outputMu.Lock()
+	defer l.outputMu.Unlock()
+
+	line, err := l.formatter.Format(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: format entry: %v\n", err)
+		return
 	}
 
-	return fmt.Sprintf(
-		"[%s] %s: %s %s",
-		entry.Timestamp.Format(time.RFC3339),
-		entry.Level,
-		entry.Message,
-		fieldsStr,
-	)
+	// A failing sink must not take the application down with it, so the
+	// line is written to stderr together with the error.
+	if err := l.sink.Write(entry, line); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: write entry: %v: %s\n", err, line)
+	}
+}
+
+// Close writes the queued entries and closes the sink. Entries logged after
+// Close are counted as dropped.
+func (l *Logger) Close() {
+	l.closeOnce.Do(func() {
+		l.closeMu.Lock()
+		l.closed = true
+		l.closeMu.Unlock()
+
+		close(l.stopChan)
+		<-l.done
+
+		l.outputMu.Lock()
+		defer l.outputMu.Unlock()
+
+		l.sink.Close()
+	})
 }
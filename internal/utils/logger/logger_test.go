@@ -1,16 +1,32 @@
 package logger
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"log"
-	"os"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 )
 
+// captureGlobal sends the global logger to a recording sink until the test
+// ends. The returned function waits for everything logged so far and returns
+// the written lines.
+func captureGlobal(t *testing.T) func() string {
+	t.Helper()
+
+	sink := &recordingSink{}
+	Configure(TextFormatter{}, sink)
+	t.Cleanup(func() {
+		Configure(TextFormatter{}, stdLogSink{})
+	})
+
+	return func() string {
+		flush(t, Get())
+		return strings.Join(sink.Lines(), "\n")
+	}
+}
+
 func TestLoggerInitialization(t *testing.T) {
 	logger1 := Init()
 	logger2 := Init()
@@ -21,11 +37,7 @@ func TestLoggerInitialization(t *testing.T) {
 }
 
 func TestLogLevels(t *testing.T) {
-	buf := bytes.Buffer{}
-	log.SetOutput(&buf)
-	defer func() {
-		log.SetOutput(os.Stderr)
-	}()
+	read := captureGlobal(t)
 
 	Info("test info", nil)
 	Warn("test warn", nil)
@@ -34,8 +46,7 @@ func TestLogLevels(t *testing.T) {
 	Warnf("test warnf: %s", "value")
 	Errorf("test errorf: %v", "error")
 
-	time.Sleep(100 * time.Millisecond)
-	output := buf.String()
+	output := read()
 
 	expected := []string{
 		"INFO",
@@ -57,11 +68,7 @@ func TestLogLevels(t *testing.T) {
 }
 
 func TestLogWithFields(t *testing.T) {
-	buf := bytes.Buffer{}
-	log.SetOutput(&buf)
-	defer func() {
-		log.SetOutput(os.Stderr)
-	}()
+	read := captureGlobal(t)
 
 	fields := map[string]any{
 		"user_id": 123,
@@ -69,9 +76,7 @@ func TestLogWithFields(t *testing.T) {
 	}
 	Info("user action", fields)
 
-	time.Sleep(100 * time.Millisecond)
-
-	output := buf.String()
+	output := read()
 
 	expectedFields := []string{
 		"user_id=123",
@@ -86,30 +91,58 @@ func TestLogWithFields(t *testing.T) {
 }
 
 func TestErrorLogging(t *testing.T) {
-	buf := bytes.Buffer{}
-	log.SetOutput(&buf)
-	defer func() {
-		log.SetOutput(os.Stderr)
-	}()
+	read := captureGlobal(t)
 
 	err := fmt.Errorf("database connection failed")
 	Error("db operation", err, nil)
 
-	time.Sleep(100 * time.Millisecond)
-
-	output := buf.String()
+	output := read()
 
 	if !strings.Contains(output, "database connection failed") {
 		t.Error("Expected error message in log output")
 	}
 }
 
+func TestLogWithContextFields(t *testing.T) {
+	read := captureGlobal(t)
+
+	ctx := ContextWithFields(context.Background(), map[string]any{"request_id": "req-1"})
+	ctx = ContextWithFields(ctx, map[string]any{"trace_id": "abc"})
+
+	InfoContext(ctx, "context info", map[string]any{"task_id": 7})
+	WarnContext(ctx, "context warn", nil)
+	ErrorContext(ctx, "context error", fmt.Errorf("boom"), map[string]any{"request_id": "override"})
+
+	output := read()
+
+	expected := []string{
+		"context info",
+		"request_id=req-1",
+		"trace_id=abc",
+		"task_id=7",
+		"context warn",
+		"request_id=override",
+		"error=boom",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected log output to contain %q", exp)
+		}
+	}
+}
+
+func TestContextWithFieldsDoesNotModifyParent(t *testing.T) {
+	parent := ContextWithFields(context.Background(), map[string]any{"request_id": "req-1"})
+	ContextWithFields(parent, map[string]any{"request_id": "req-2"})
+
+	if got := FieldsFromContext(parent)["request_id"]; got != "req-1" {
+		t.Errorf("Expected parent context to keep request_id %q, got %v", "req-1", got)
+	}
+}
+
 func TestConcurrentLogging(t *testing.T) {
-	buf := bytes.Buffer{}
-	log.SetOutput(&buf)
-	defer func() {
-		log.SetOutput(os.Stderr)
-	}()
+	read := captureGlobal(t)
 
 	var wg sync.WaitGroup
 	count := 100
@@ -123,9 +156,7 @@ func TestConcurrentLogging(t *testing.T) {
 	}
 
 	wg.Wait()
-	time.Sleep(100 * time.Millisecond)
-
-	output := buf.String()
+	output := read()
 
 	for i := range count {
 		if !strings.Contains(output, fmt.Sprintf("concurrent log %d", i)) {
@@ -135,18 +166,29 @@ func TestConcurrentLogging(t *testing.T) {
 }
 
 func TestLoggerClose(t *testing.T) {
-	logger := Init()
+	logger, err := CreateLogger(DefaultOptions())
+	if err != nil {
+		t.Fatalf("CreateLogger returned error: %v", err)
+	}
+	sink := &recordingSink{}
+	logger.Configure(TextFormatter{}, sink)
+
+	logger.log(LevelInfo, "test before close", nil)
 
-	var closed bool
+	closed := make(chan struct{})
 	go func() {
 		logger.Close()
-		closed = true
+		close(closed)
 	}()
 
-	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Logger should be closed")
+	}
 
-	if !closed {
-		t.Error("Logger should be closed")
+	if len(sink.Lines()) != 1 || !sink.closed {
+		t.Errorf("Expected queued entry to be written and sink closed, got %v", sink.Lines())
 	}
 
 	func() {
@@ -156,5 +198,10 @@ func TestLoggerClose(t *testing.T) {
 			}
 		}()
 		logger.log(LevelInfo, "test after close", nil)
+		logger.Close()
 	}()
+
+	if dropped := logger.Stats().Dropped; dropped != 1 {
+		t.Errorf("Expected entry logged after close to be dropped, got %d drops", dropped)
+	}
 }
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockedLogger returns a logger whose writer is stuck on the first entry
+// until the returned sink's gate is closed, so the queue can be filled.
+func blockedLogger(t *testing.T, opts Options) (*Logger, *recordingSink) {
+	t.Helper()
+
+	logger, err := CreateLogger(opts)
+	if err != nil {
+		t.Fatalf("CreateLogger returned error: %v", err)
+	}
+
+	sink := &recordingSink{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+	logger.Configure(TextFormatter{}, sink)
+
+	logger.log(LevelInfo, "entry-1", nil)
+	select {
+	case <-sink.started:
+	case <-time.After(time.Second):
+		t.Fatal("Writer did not pick up the first entry")
+	}
+
+	return logger, sink
+}
+
+func messages(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		_, message, _ := strings.Cut(line, ": ")
+		out = append(out, message)
+	}
+	return out
+}
+
+func flush(t *testing.T, logger *Logger) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+}
+
+func TestCreateLoggerInvalidOptions(t *testing.T) {
+	tests := []Options{
+		{BufferSize: 0, Policy: PolicyBlock},
+		{BufferSize: 10, Policy: "drop_random"},
+	}
+
+	for _, opts := range tests {
+		if _, err := CreateLogger(opts); err == nil {
+			t.Errorf("Expected error for options %+v", opts)
+		}
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy   string
+		expected []string
+		dropped  uint64
+	}{
+		{policy: PolicyDropNewest, expected: []string{"entry-1", "entry-2", "entry-3"}, dropped: 2},
+		{policy: PolicyDropOldest, expected: []string{"entry-1", "entry-4", "entry-5"}, dropped: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			logger, sink := blockedLogger(t, Options{BufferSize: 2, Policy: tt.policy})
+			defer logger.Close()
+
+			for _, message := range []string{"entry-2", "entry-3", "entry-4", "entry-5"} {
+				logger.log(LevelInfo, message, nil)
+			}
+
+			stats := logger.Stats()
+			if stats.Dropped != tt.dropped || stats.Queued != 2 || stats.Policy != tt.policy {
+				t.Errorf("Unexpected stats %+v", stats)
+			}
+
+			close(sink.gate)
+			flush(t, logger)
+
+			if got := messages(sink.Lines()); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBlockPolicyWaitsForSpace(t *testing.T) {
+	logger, sink := blockedLogger(t, Options{BufferSize: 1, Policy: PolicyBlock})
+	defer logger.Close()
+
+	logger.log(LevelInfo, "entry-2", nil)
+
+	logged := make(chan struct{})
+	go func() {
+		logger.log(LevelInfo, "entry-3", nil)
+		close(logged)
+	}()
+
+	select {
+	case <-logged:
+		t.Fatal("Expected caller to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(sink.gate)
+	<-logged
+	flush(t, logger)
+
+	if got := messages(sink.Lines()); len(got) != 3 || logger.Stats().Dropped != 0 {
+		t.Errorf("Expected all entries to be written without drops, got %v", got)
+	}
+}
+
+func TestFlushWaitsForQueuedEntries(t *testing.T) {
+	logger, sink := blockedLogger(t, Options{BufferSize: 10, Policy: PolicyBlock})
+	defer logger.Close()
+
+	logger.log(LevelInfo, "entry-2", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := logger.Flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected Flush to time out while the writer is blocked, got %v", err)
+	}
+
+	close(sink.gate)
+	flush(t, logger)
+
+	if got := messages(sink.Lines()); len(got) != 2 {
+		t.Errorf("Expected 2 entries after flush, got %v", got)
+	}
+}
+
+func TestDropOldestKeepsFlushMarker(t *testing.T) {
+	logger, sink := blockedLogger(t, Options{BufferSize: 1, Policy: PolicyDropOldest})
+	defer logger.Close()
+
+	flushed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		flushed <- logger.Flush(ctx)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for logger.Stats().Queued == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	go logger.log(LevelInfo, "entry-2", nil)
+	time.Sleep(20 * time.Millisecond)
+	close(sink.gate)
+
+	if err := <-flushed; err != nil {
+		t.Errorf("Expected flush to complete, got %v", err)
+	}
+	if dropped := logger.Stats().Dropped; dropped != 0 {
+		t.Errorf("Expected no drops, got %d", dropped)
+	}
+}
+
+func TestCloseDrainsQueue(t *testing.T) {
+	logger, sink := blockedLogger(t, Options{BufferSize: 100, Policy: PolicyBlock})
+
+	for i := 0; i < 50; i++ {
+		logger.log(LevelInfo, "queued", nil)
+	}
+
+	close(sink.gate)
+	logger.Close()
+
+	if got := len(sink.Lines()); got != 51 {
+		t.Errorf("Expected 51 entries written before close returned, got %d", got)
+	}
+	if !sink.closed {
+		t.Error("Expected sink to be closed")
+	}
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Errorf("Expected Flush after close to succeed, got %v", err)
+	}
+}
+
+func TestFatalFlushesBeforeExit(t *testing.T) {
+	logger, err := CreateLogger(Options{BufferSize: 10, Policy: PolicyBlock})
+	if err != nil {
+		t.Fatalf("CreateLogger returned error: %v", err)
+	}
+	defer logger.Close()
+
+	sink := &recordingSink{}
+	logger.Configure(TextFormatter{}, sink)
+
+	var exitCode int
+	var linesAtExit []string
+	osExit = func(code int) {
+		exitCode = code
+		linesAtExit = sink.Lines()
+	}
+	defer func() {
+		osExit = os.Exit
+	}()
+
+	logger.SetLevels(LevelSettings{Global: LevelError})
+	logger.log(LevelError, "before fatal", nil)
+	logger.fatal("fatal error", map[string]any{"error": "boom"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if len(linesAtExit) != 2 ||
+		!strings.Contains(linesAtExit[0], "ERROR: before fatal") ||
+		!strings.Contains(linesAtExit[1], "FATAL: fatal error error=boom") {
+		t.Errorf("Expected queued entry and fatal entry before exit, got %v", linesAtExit)
+	}
+}
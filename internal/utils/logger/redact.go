@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DetectorEmail = "email"
+	DetectorToken = "token"
+	DetectorPhone = "phone"
+
+	redactedValue = "[REDACTED]"
+)
+
+// RedactionConfig describes what the logger hides before an entry is
+// formatted.
+//
+// Keys are case-insensitive patterns with "*" wildcards matched against the
+// whole field name and against its last dotted segment, so "password" also
+// covers "user.password". When Allowlist is not empty, every field that does
+// not match it is redacted as well. Detectors replace emails, tokens and
+// phone numbers found inside string values and the message, except in
+// SafeKeys. String values longer than MaxValueLength runes are truncated;
+// zero disables truncation.
+type RedactionConfig struct {
+	Keys           []string
+	Allowlist      []string
+	SafeKeys       []string
+	Detectors      []string
+	MaxValueLength int
+}
+
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Keys: []string{
+			"password", "passwd", "*secret*", "*token*", "api_key", "apikey",
+			"authorization", "cookie", "set-cookie", "title", "description",
+		},
+		SafeKeys:       []string{"method", "request_id", "trace_id", "span_id"},
+		Detectors:      []string{DetectorEmail, DetectorToken, DetectorPhone},
+		MaxValueLength: 256,
+	}
+}
+
+type detector struct {
+	name    string
+	pattern *regexp.Regexp
+	// accept filters candidate matches the pattern alone cannot rule out.
+	accept func(match string) bool
+}
+
+var ipv4Pattern = regexp.MustCompile(`^\d{1,3}(\.\d{1,3}){3}$`)
+
+var detectors = map[string]detector{
+	DetectorEmail: {
+		name:    DetectorEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	DetectorToken: {
+		name: DetectorToken,
+		pattern: regexp.MustCompile(`(?i:bearer\s+[A-Za-z0-9._~+/-]+=*)` +
+			`|eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+` +
+			`|\b(?:sk|pk|rk)_(?:live|test)_[A-Za-z0-9]{10,}` +
+			`|\bgh[pousr]_[A-Za-z0-9]{30,}` +
+			`|\bxox[abpr]-[A-Za-z0-9-]{10,}` +
+			`|\bAKIA[0-9A-Z]{16}\b`),
+	},
+	// Phone numbers need a leading "+" or a separator so that plain numeric
+	// IDs and timestamps are left alone; IPv4 addresses are not phones.
+	DetectorPhone: {
+		name:    DetectorPhone,
+		pattern: regexp.MustCompile(`\+?\(?\d[\d\s().-]{7,}\d`),
+		accept: func(match string) bool {
+			if ipv4Pattern.MatchString(match) {
+				return false
+			}
+
+			digits := 0
+			for _, c := range match {
+				if c >= '0' && c <= '9' {
+					digits++
+				}
+			}
+			return digits >= 10 && digits <= 15 &&
+				(strings.HasPrefix(match, "+") || strings.ContainsAny(match, " ().-"))
+		},
+	},
+}
+
+// Redactor is immutable once created and safe for concurrent use.
+type Redactor struct {
+	keys           []string
+	allowlist      []string
+	safeKeys       map[string]bool
+	detectors      []detector
+	maxValueLength int
+}
+
+func CreateRedactor(config RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		safeKeys:       make(map[string]bool, len(config.SafeKeys)),
+		maxValueLength: config.MaxValueLength,
+	}
+
+	for _, pattern := range config.Keys {
+		normalized, err := normalizeKeyPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.keys = append(r.keys, normalized)
+	}
+	for _, pattern := range config.Allowlist {
+		normalized, err := normalizeKeyPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.allowlist = append(r.allowlist, normalized)
+	}
+	for _, key := range config.SafeKeys {
+		r.safeKeys[key] = true
+	}
+	for _, name := range config.Detectors {
+		d, ok := detectors[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown redaction detector %q", name)
+		}
+		r.detectors = append(r.detectors, d)
+	}
+	if config.MaxValueLength < 0 {
+		return nil, fmt.Errorf("logger: max value length must not be negative, got %d", config.MaxValueLength)
+	}
+
+	return r, nil
+}
+
+func normalizeKeyPattern(pattern string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(pattern))
+	if normalized == "" {
+		return "", fmt.Errorf("logger: empty redaction key pattern")
+	}
+	if _, err := path.Match(normalized, ""); err != nil {
+		return "", fmt.Errorf("logger: invalid redaction key pattern %q: %w", pattern, err)
+	}
+
+	return normalized, nil
+}
+
+// Redact returns a copy of the entry with sensitive data removed; the
+// caller's fields map is not modified.
+func (r *Redactor) Redact(entry LogEntry) LogEntry {
+	if !entry.redacted {
+		entry.Message = r.redactString(entry.Message)
+	}
+
+	if len(entry.Fields) == 0 {
+		return entry
+	}
+
+	fields := make(map[string]any, len(entry.Fields))
+	for key, value := range entry.Fields {
+		fields[key] = r.redactField(key, value)
+	}
+	entry.Fields = fields
+
+	return entry
+}
+
+// RedactQuery treats the parameters of a raw URL query as fields, so that
+// "?token=..." is hidden like a "token" field would be. The order of the
+// parameters and the encoding of the untouched ones are kept.
+func (r *Redactor) RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		name, value, _ := strings.Cut(param, "=")
+		decodedName, err := url.QueryUnescape(name)
+		if err != nil {
+			decodedName = name
+		}
+		decodedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			decodedValue = value
+		}
+
+		if redacted, _ := r.redactField(decodedName, decodedValue).(string); redacted != decodedValue {
+			params[i] = name + "=" + redacted
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// RedactText runs the detectors over a value but keeps its length, for values
+// the caller embeds in a message it redacts itself.
+func (r *Redactor) RedactText(value string) string {
+	return r.detect(value)
+}
+
+func (r *Redactor) redactField(key string, value any) any {
+	if r.safeKeys[key] {
+		return value
+	}
+	if matchesKey(r.keys, key) || (len(r.allowlist) > 0 && !matchesKey(r.allowlist, key)) {
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.redactString(v)
+	case error:
+		return r.redactString(v.Error())
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) redactString(value string) string {
+	value = r.detect(value)
+
+	if r.maxValueLength > 0 && utf8.RuneCountInString(value) > r.maxValueLength {
+		runes := []rune(value)
+		value = fmt.Sprintf("%s...[truncated %d chars]", string(runes[:r.maxValueLength]), len(runes)-r.maxValueLength)
+	}
+
+	return value
+}
+
+func (r *Redactor) detect(value string) string {
+	for _, d := range r.detectors {
+		value = d.pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if d.accept != nil && !d.accept(match) {
+				return match
+			}
+			return "[REDACTED:" + d.name + "]"
+		})
+	}
+
+	return value
+}
+
+func matchesKey(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	last := key
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		last = key[i+1:]
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, last); ok {
+			return true
+		}
+	}
+
+	return false
+}
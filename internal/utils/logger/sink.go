@@ -0,0 +1,388 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+// Sink receives formatted entries from the logger goroutine. The entry is
+// passed alongside the line for sinks that need the level or time, such as
+// syslog.
+type Sink interface {
+	Write(entry LogEntry, line []byte) error
+	Close() error
+}
+
+// stdLogSink is the sink used until the logger is configured: lines go
+// through the standard log package, so log.SetOutput keeps working.
+type stdLogSink struct{}
+
+func (stdLogSink) Write(entry LogEntry, line []byte) error {
+	log.Println(string(line))
+	return nil
+}
+
+func (stdLogSink) Close() error {
+	return nil
+}
+
+// WriterSink writes newline-terminated lines to w, e.g. os.Stdout.
+type WriterSink struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func CreateWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Write(entry LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.w.Write(append(line, '\n'))
+	return err
+}
+
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// Facility codes from RFC 5424.
+const (
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityLocal0 = 16
+)
+
+// SyslogSink sends RFC 5424 messages over UDP, one datagram per entry.
+// Delivery is best effort, as with any UDP syslog.
+type SyslogSink struct {
+	conn     net.Conn
+	facility int
+	hostname string
+	appName  string
+	pid      int
+	mu       sync.Mutex
+}
+
+func CreateSyslogSink(address, appName string, facility int) (*SyslogSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("logger: dial syslog %s: %w", address, err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	if appName == "" {
+		appName = "-"
+	}
+
+	return &SyslogSink{
+		conn:     conn,
+		facility: facility,
+		hostname: hostname,
+		appName:  appName,
+		pid:      os.Getpid(),
+	}, nil
+}
+
+func (s *SyslogSink) Write(entry LogEntry, line []byte) error {
+	priority := s.facility*8 + syslogSeverity(entry.Level)
+	message := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		priority,
+		entry.Timestamp.Format(time.RFC3339Nano),
+		s.hostname,
+		s.appName,
+		s.pid,
+		line,
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.conn.Write([]byte(message))
+	return err
+}
+
+func (s *SyslogSink) Close() error {
+	return s.conn.Close()
+}
+
+func syslogSeverity(level string) int {
+	switch level {
+	case LevelFatal:
+		return 2
+	case LevelError:
+		return 3
+	case LevelWarn:
+		return 4
+	case LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+type FileSinkConfig struct {
+	Path       string
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+	Compress   bool
+}
+
+const backupTimeLayout = "2006-01-02T15-04-05.000000000"
+
+// FileSink appends lines to a file and rotates it once it would grow beyond
+// MaxSize bytes or has been open for MaxAge; a zero value disables that
+// trigger. Rotated files are renamed with a timestamp, optionally gzipped,
+// and only the newest MaxBackups are kept (zero keeps all).
+type FileSink struct {
+	config   FileSinkConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	mu       sync.Mutex
+
+	// millChan holds at most one pending request to process the backups; a
+	// request already queued covers later rotations too.
+	millChan chan struct{}
+	millDone chan struct{}
+}
+
+func CreateFileSink(config FileSinkConfig) (*FileSink, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("logger: file sink path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("logger: create log directory: %w", err)
+	}
+
+	s := &FileSink{
+		config:   config,
+		millChan: make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	go s.mill()
+
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("logger: stat log file: %w", err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = time.Now()
+
+	return nil
+}
+
+func (s *FileSink) Write(entry LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	data := append(line, '\n')
+	if s.shouldRotate(int64(len(data))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+
+	return err
+}
+
+func (s *FileSink) shouldRotate(next int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.config.MaxSize > 0 && s.size+next > s.config.MaxSize {
+		return true
+	}
+	return s.config.MaxAge > 0 && time.Since(s.openedAt) >= s.config.MaxAge
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("logger: close log file: %w", err)
+	}
+
+	ext := filepath.Ext(s.config.Path)
+	backup := fmt.Sprintf("%s-%s%s",
+		strings.TrimSuffix(s.config.Path, ext),
+		time.Now().UTC().Format(backupTimeLayout),
+		ext,
+	)
+	if err := os.Rename(s.config.Path, backup); err != nil {
+		return fmt.Errorf("logger: rotate log file: %w", err)
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	select {
+	case s.millChan <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// mill enforces retention and compresses rotated files in the background, so
+// the logger goroutine is not held up by gzip. Each run works from a fresh
+// listing of the backups, oldest first, so a file removed by retention is
+// never compressed.
+func (s *FileSink) mill() {
+	defer close(s.millDone)
+
+	for range s.millChan {
+		if err := s.millRun(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: process rotated log files: %v\n", err)
+		}
+	}
+}
+
+func (s *FileSink) millRun() error {
+	backups, err := s.Backups()
+	if err != nil {
+		return err
+	}
+
+	if s.config.MaxBackups > 0 && len(backups) > s.config.MaxBackups {
+		for _, backup := range backups[:len(backups)-s.config.MaxBackups] {
+			if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		backups = backups[len(backups)-s.config.MaxBackups:]
+	}
+
+	if !s.config.Compress {
+		return nil
+	}
+	for _, backup := range backups {
+		if strings.HasSuffix(backup, ".gz") {
+			continue
+		}
+		if err := compressFile(backup); err != nil {
+			return fmt.Errorf("compress %s: %w", backup, err)
+		}
+	}
+
+	return nil
+}
+
+// Backups returns rotated files, oldest first.
+func (s *FileSink) Backups() ([]string, error) {
+	ext := filepath.Ext(s.config.Path)
+	prefix := strings.TrimSuffix(filepath.Base(s.config.Path), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(s.config.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ext)
+		stamp = strings.TrimPrefix(stamp, prefix)
+		if _, err := time.Parse(backupTimeLayout, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(s.config.Path), name))
+	}
+
+	// The timestamp layout sorts lexically in time order and ReadDir
+	// returns names sorted, so backups are already oldest first.
+	return backups, nil
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	if s.file == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	close(s.millChan)
+	s.mu.Unlock()
+
+	<-s.millDone
+
+	return err
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,279 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink keeps written lines; when gate is set, every write waits
+// for it to be closed after announcing itself on started.
+type recordingSink struct {
+	gate    chan struct{}
+	started chan struct{}
+	lines   []string
+	closed  bool
+	mu      sync.Mutex
+}
+
+func (s *recordingSink) Write(entry LogEntry, line []byte) error {
+	if s.gate != nil {
+		select {
+		case s.started <- struct{}{}:
+		default:
+		}
+		<-s.gate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = append(s.lines, string(line))
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.lines...)
+}
+
+func TestLoggerConfigure(t *testing.T) {
+	first := &recordingSink{}
+	second := &recordingSink{}
+	l := &Logger{formatter: TextFormatter{}, sink: first}
+
+	if err := l.Configure(JSONFormatter{}, second); err != nil {
+		t.Fatalf("Configure returned error: %v", err)
+	}
+	l.write(testEntry())
+
+	if !first.closed {
+		t.Error("Expected previous sink to be closed")
+	}
+	if len(first.Lines()) != 0 {
+		t.Errorf("Expected no lines in previous sink, got %d", len(first.lines))
+	}
+	if lines := second.Lines(); len(lines) != 1 || !strings.HasPrefix(lines[0], `{"time":`) {
+		t.Errorf("Expected one JSON line in new sink, got %v", lines)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	buf := bytes.Buffer{}
+	sink := CreateWriterSink(&buf)
+
+	sink.Write(testEntry(), []byte("first"))
+	sink.Write(testEntry(), []byte("second"))
+
+	if buf.String() != "first\nsecond\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := CreateFileSink(FileSinkConfig{
+		Path:       path,
+		MaxSize:    20,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateFileSink returned error: %v", err)
+	}
+
+	for _, line := range []string{"line-1 xxxxxx", "line-2 xxxxxx", "line-3 xxxxxx", "line-4 xxxxxx", "line-5 xxxxxx"} {
+		if err := sink.Write(testEntry(), []byte(line)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read current file: %v", err)
+	}
+	if string(current) != "line-5 xxxxxx\n" {
+		t.Errorf("Unexpected current file content %q", current)
+	}
+
+	backups, err := sink.Backups()
+	if err != nil {
+		t.Fatalf("Backups returned error: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups to be retained, got %v", backups)
+	}
+
+	for i, backup := range backups {
+		if !strings.HasSuffix(backup, ".log.gz") {
+			t.Errorf("Expected compressed backup, got %s", backup)
+			continue
+		}
+
+		content := readGzip(t, backup)
+		expected := []string{"line-3 xxxxxx\n", "line-4 xxxxxx\n"}[i]
+		if content != expected {
+			t.Errorf("Expected backup %s to contain %q, got %q", backup, expected, content)
+		}
+	}
+}
+
+func TestFileSinkRotationDoesNotWaitForMill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// The sink is put together by hand so that the mill only starts after
+	// all the rotations below.
+	sink := &FileSink{
+		config:   FileSinkConfig{Path: path, MaxSize: 10, MaxBackups: 3, Compress: true},
+		millChan: make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := sink.open(); err != nil {
+		t.Fatalf("open returned error: %v", err)
+	}
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for i := range 50 {
+			sink.Write(testEntry(), []byte(fmt.Sprintf("line-%02d", i)))
+		}
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked while the mill was behind")
+	}
+
+	go sink.mill()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	backups, err := sink.Backups()
+	if err != nil {
+		t.Fatalf("Backups returned error: %v", err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("Expected 3 backups to be retained, got %v", backups)
+	}
+	for i, backup := range backups {
+		expected := fmt.Sprintf("line-%02d\n", 46+i)
+		if content := readGzip(t, backup); content != expected {
+			t.Errorf("Expected backup %s to contain %q, got %q", backup, expected, content)
+		}
+	}
+}
+
+func TestFileSinkRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := CreateFileSink(FileSinkConfig{Path: path, MaxAge: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("CreateFileSink returned error: %v", err)
+	}
+	defer sink.Close()
+
+	sink.Write(testEntry(), []byte("old"))
+	time.Sleep(30 * time.Millisecond)
+	sink.Write(testEntry(), []byte("new"))
+
+	backups, err := sink.Backups()
+	if err != nil {
+		t.Fatalf("Backups returned error: %v", err)
+	}
+	if len(backups) != 1 || strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("Expected one uncompressed backup, got %v", backups)
+	}
+
+	content, _ := os.ReadFile(backups[0])
+	if string(content) != "old\n" {
+		t.Errorf("Unexpected backup content %q", content)
+	}
+}
+
+func TestFileSinkWriteAfterClose(t *testing.T) {
+	sink, err := CreateFileSink(FileSinkConfig{Path: filepath.Join(t.TempDir(), "app.log")})
+	if err != nil {
+		t.Fatalf("CreateFileSink returned error: %v", err)
+	}
+	sink.Close()
+
+	if err := sink.Write(testEntry(), []byte("late")); err == nil {
+		t.Error("Expected error when writing to a closed sink")
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := CreateSyslogSink(conn.LocalAddr().String(), "task-service", FacilityLocal0)
+	if err != nil {
+		t.Fatalf("CreateSyslogSink returned error: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Write(testEntry(), []byte("task update failed")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read datagram: %v", err)
+	}
+	message := string(buf[:n])
+
+	if !strings.HasPrefix(message, "<131>1 2025-07-31T11:17:18Z ") {
+		t.Errorf("Unexpected syslog header in %q", message)
+	}
+	if !strings.Contains(message, " task-service ") || !strings.HasSuffix(message, " - - task update failed") {
+		t.Errorf("Unexpected syslog message %q", message)
+	}
+}
+
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read gzip header of %s: %v", path, err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to decompress %s: %v", path, err)
+	}
+
+	return string(content)
+}
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogLevelTrace is the slog level that maps to TRACE; slog has no name for
+// it and prints it as "DEBUG-4".
+const SlogLevelTrace = slog.LevelDebug - 4
+
+// SlogHandler is a slog.Handler that queues records on a Logger, so slog
+// callers share its level thresholds, formatter, sink and queue. Attributes
+// become entry fields; groups are flattened into dotted keys such as
+// "http.status", and fields carried by the context (see ContextWithFields)
+// are added to every record.
+type SlogHandler struct {
+	logger *Logger
+	fields map[string]any
+	prefix string
+}
+
+func CreateSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Slog returns a slog.Logger backed by the global logger.
+func Slog() *slog.Logger {
+	return slog.New(CreateSlogHandler(Get()))
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	filter := h.logger.levels.Load()
+	if filter == nil {
+		return true
+	}
+
+	// Component thresholds depend on the "method" field, which is only known
+	// in Handle, so here the record only has to pass the lowest threshold.
+	return levelRanks[levelFromSlog(level)] >= filter.lowest
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	contextFields := FieldsFromContext(ctx)
+
+	fields := make(map[string]any, len(contextFields)+len(h.fields)+record.NumAttrs())
+	for k, v := range contextFields {
+		fields[k] = v
+	}
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	timestamp := record.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	h.logger.logEntry(LogEntry{
+		Level:     levelFromSlog(record.Level),
+		Message:   record.Message,
+		Timestamp: timestamp,
+		Fields:    fields,
+	})
+
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	fields := make(map[string]any, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, attr := range attrs {
+		addAttr(fields, h.prefix, attr)
+	}
+
+	return &SlogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &SlogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func addAttr(fields map[string]any, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			addAttr(fields, groupPrefix, member)
+		}
+		return
+	}
+
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
+
+func levelFromSlog(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// logFields is the entry point of the map based helpers such as Info and
+// ErrorContext, which share the slog path.
+func logFields(ctx context.Context, level slog.Level, message string, fields map[string]any) {
+	handler := CreateSlogHandler(Get())
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, message, 0)
+	for _, key := range sortedKeys(fields) {
+		record.AddAttrs(slog.Any(key, fields[key]))
+	}
+
+	handler.Handle(ctx, record)
+}
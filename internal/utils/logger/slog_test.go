@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func jsonLogger(t *testing.T) (*Logger, *recordingSink) {
+	t.Helper()
+
+	logger, err := CreateLogger(Options{BufferSize: 16, Policy: PolicyBlock})
+	if err != nil {
+		t.Fatalf("CreateLogger returned error: %v", err)
+	}
+	t.Cleanup(logger.Close)
+
+	sink := &recordingSink{}
+	logger.Configure(JSONFormatter{}, sink)
+
+	return logger, sink
+}
+
+func decodeLines(t *testing.T, lines []string) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode %s: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestSlogHandlerAttrsAndGroups(t *testing.T) {
+	logger, sink := jsonLogger(t)
+
+	slogger := slog.New(CreateSlogHandler(logger)).
+		With("component", "api").
+		WithGroup("http").
+		With("method", "GET")
+
+	ctx := ContextWithFields(context.Background(), map[string]any{"request_id": "req-1"})
+	slogger.InfoContext(ctx, "request served",
+		"status", 200,
+		slog.Group("client", "ip", "192.0.2.10"),
+		slog.Group("empty"),
+		slog.Attr{},
+	)
+
+	flush(t, logger)
+	entries := decodeLines(t, sink.Lines())
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	expected := map[string]any{
+		"level":          "INFO",
+		"message":        "request served",
+		"component":      "api",
+		"request_id":     "req-1",
+		"http.method":    "GET",
+		"http.status":    float64(200),
+		"http.client.ip": "192.0.2.10",
+	}
+	for key, value := range expected {
+		if entries[0][key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entries[0][key])
+		}
+	}
+	if len(entries[0]) != len(expected)+1 {
+		t.Errorf("Unexpected fields in %v", entries[0])
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{level: SlogLevelTrace, want: LevelTrace},
+		{level: slog.LevelDebug, want: LevelDebug},
+		{level: slog.LevelInfo, want: LevelInfo},
+		{level: slog.LevelInfo + 2, want: LevelInfo},
+		{level: slog.LevelWarn, want: LevelWarn},
+		{level: slog.LevelError, want: LevelError},
+		{level: slog.LevelError + 4, want: LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := levelFromSlog(tt.level); got != tt.want {
+			t.Errorf("levelFromSlog(%v) = %s, want %s", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	logger, sink := jsonLogger(t)
+	logger.SetLevels(LevelSettings{
+		Global:     LevelWarn,
+		Components: map[string]string{"Repository.*": LevelDebug},
+	})
+
+	handler := CreateSlogHandler(logger)
+	ctx := context.Background()
+
+	if !handler.Enabled(ctx, slog.LevelDebug) {
+		t.Error("Expected DEBUG to be enabled while a component allows it")
+	}
+	if handler.Enabled(ctx, SlogLevelTrace) {
+		t.Error("Expected TRACE to be disabled")
+	}
+
+	slogger := slog.New(handler)
+	slogger.Debug("dropped by global threshold")
+	slogger.Debug("kept by component threshold", "method", "Repository.GetTaskByID")
+	slogger.Warn("kept by global threshold")
+
+	flush(t, logger)
+	entries := decodeLines(t, sink.Lines())
+	if len(entries) != 2 ||
+		entries[0]["message"] != "kept by component threshold" ||
+		entries[1]["message"] != "kept by global threshold" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+}
+
+func TestSlogHandlerKeepsRecordTime(t *testing.T) {
+	logger, sink := jsonLogger(t)
+
+	at := time.Date(2025, 7, 31, 11, 17, 18, 0, time.UTC)
+	record := slog.NewRecord(at, slog.LevelInfo, "at fixed time", 0)
+	CreateSlogHandler(logger).Handle(context.Background(), record)
+
+	flush(t, logger)
+	entries := decodeLines(t, sink.Lines())
+	if len(entries) != 1 || entries[0]["time"] != "2025-07-31T11:17:18Z" {
+		t.Errorf("Expected record time to be kept, got %v", entries)
+	}
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		io.WriteString(w, r.Gather())
+	})
+}
+
+func Handler() http.Handler {
+	return Default.Handler()
+}
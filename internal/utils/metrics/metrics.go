@@ -0,0 +1,406 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+type desc struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+}
+
+type collector interface {
+	describe() desc
+	collect(w *strings.Builder)
+}
+
+type Registry struct {
+	collectors map[string]collector
+	mu         sync.RWMutex
+}
+
+func CreateRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]collector),
+	}
+}
+
+var Default = CreateRegistry()
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := c.describe().name
+	if _, exists := r.collectors[name]; exists {
+		panic(fmt.Sprintf("metrics: %s is already registered", name))
+	}
+	r.collectors[name] = c
+}
+
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.collectors, name)
+}
+
+// Gather renders every registered metric in the Prometheus text exposition
+// format, ordered by metric name.
+func (r *Registry) Gather() string {
+	r.mu.RLock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(collectors, func(a, b collector) int {
+		return strings.Compare(a.describe().name, b.describe().name)
+	})
+
+	var b strings.Builder
+	for _, c := range collectors {
+		d := c.describe()
+		fmt.Fprintf(&b, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", d.name, d.metricType)
+		c.collect(&b)
+	}
+
+	return b.String()
+}
+
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if f.bits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(value float64) {
+	f.bits.Store(math.Float64bits(value))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// series keeps one child per distinct combination of label values.
+type series[T any] struct {
+	desc     desc
+	children map[string]*child[T]
+	create   func() *T
+	mu       sync.RWMutex
+}
+
+type child[T any] struct {
+	labelValues []string
+	value       *T
+}
+
+func newSeries[T any](d desc, create func() *T) *series[T] {
+	return &series[T]{
+		desc:     d,
+		children: make(map[string]*child[T]),
+		create:   create,
+	}
+}
+
+func (s *series[T]) get(labelValues []string) *T {
+	if len(labelValues) != len(s.desc.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", s.desc.name, len(s.desc.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	s.mu.RLock()
+	c, ok := s.children[key]
+	s.mu.RUnlock()
+	if ok {
+		return c.value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.children[key]; ok {
+		return c.value
+	}
+	c = &child[T]{labelValues: slices.Clone(labelValues), value: s.create()}
+	s.children[key] = c
+
+	return c.value
+}
+
+func (s *series[T]) sorted() []*child[T] {
+	s.mu.RLock()
+	children := make([]*child[T], 0, len(s.children))
+	for _, c := range s.children {
+		children = append(children, c)
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(children, func(a, b *child[T]) int {
+		return slices.Compare(a.labelValues, b.labelValues)
+	})
+
+	return children
+}
+
+func (s *series[T]) describe() desc {
+	return s.desc
+}
+
+type Counter struct {
+	value atomicFloat
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.value.Add(delta)
+}
+
+type CounterVec struct {
+	*series[Counter]
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	v := &CounterVec{newSeries(desc{name, help, typeCounter, labelNames}, func() *Counter { return &Counter{} })}
+	r.register(v)
+	return v
+}
+
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return v.get(labelValues)
+}
+
+func (v *CounterVec) collect(w *strings.Builder) {
+	for _, c := range v.sorted() {
+		writeSample(w, v.desc.name, v.desc.labelNames, c.labelValues, c.value.value.Load())
+	}
+}
+
+type Gauge struct {
+	value atomicFloat
+}
+
+func (g *Gauge) Set(value float64) {
+	g.value.Set(value)
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Add(delta float64) {
+	g.value.Add(delta)
+}
+
+type GaugeVec struct {
+	*series[Gauge]
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	v := &GaugeVec{newSeries(desc{name, help, typeGauge, labelNames}, func() *Gauge { return &Gauge{} })}
+	r.register(v)
+	return v
+}
+
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return r.NewGaugeVec(name, help).WithLabelValues()
+}
+
+func (v *GaugeVec) WithLabelValues(labelValues ...string) *Gauge {
+	return v.get(labelValues)
+}
+
+func (v *GaugeVec) collect(w *strings.Builder) {
+	for _, c := range v.sorted() {
+		writeSample(w, v.desc.name, v.desc.labelNames, c.labelValues, c.value.value.Load())
+	}
+}
+
+type Histogram struct {
+	upperBounds []float64
+	buckets     []atomic.Uint64
+	count       atomic.Uint64
+	sum         atomicFloat
+}
+
+func (h *Histogram) Observe(value float64) {
+	i, _ := slices.BinarySearch(h.upperBounds, value)
+	if i < len(h.buckets) {
+		h.buckets[i].Add(1)
+	}
+	h.count.Add(1)
+	h.sum.Add(value)
+}
+
+type HistogramVec struct {
+	*series[Histogram]
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	upperBounds := slices.Clone(buckets)
+	slices.Sort(upperBounds)
+
+	v := &HistogramVec{newSeries(desc{name, help, typeHistogram, labelNames}, func() *Histogram {
+		return &Histogram{
+			upperBounds: upperBounds,
+			buckets:     make([]atomic.Uint64, len(upperBounds)),
+		}
+	})}
+	r.register(v)
+	return v
+}
+
+func (v *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	return v.get(labelValues)
+}
+
+func (v *HistogramVec) collect(w *strings.Builder) {
+	labelNames := append(slices.Clone(v.desc.labelNames), "le")
+
+	for _, c := range v.sorted() {
+		h := c.value
+
+		var cumulative uint64
+		for i, bound := range h.upperBounds {
+			cumulative += h.buckets[i].Load()
+			writeSample(w, v.desc.name+"_bucket", labelNames, append(slices.Clone(c.labelValues), formatFloat(bound)), float64(cumulative))
+		}
+		count := max(h.count.Load(), cumulative)
+		writeSample(w, v.desc.name+"_bucket", labelNames, append(slices.Clone(c.labelValues), "+Inf"), float64(count))
+		writeSample(w, v.desc.name+"_sum", v.desc.labelNames, c.labelValues, h.sum.Load())
+		writeSample(w, v.desc.name+"_count", v.desc.labelNames, c.labelValues, float64(count))
+	}
+}
+
+// funcCollector reads its samples at scrape time, for values that already
+// live elsewhere such as queue lengths or row counts.
+type funcCollector struct {
+	desc      desc
+	collectFn func(emit func(value float64, labelValues ...string))
+}
+
+func (f *funcCollector) describe() desc {
+	return f.desc
+}
+
+func (f *funcCollector) collect(w *strings.Builder) {
+	f.collectFn(func(value float64, labelValues ...string) {
+		writeSample(w, f.desc.name, f.desc.labelNames, labelValues, value)
+	})
+}
+
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.NewGaugeVecFunc(name, help, nil, func(emit func(float64, ...string)) {
+		emit(fn())
+	})
+}
+
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.register(&funcCollector{
+		desc: desc{name, help, typeCounter, nil},
+		collectFn: func(emit func(float64, ...string)) {
+			emit(fn())
+		},
+	})
+}
+
+func (r *Registry) NewGaugeVecFunc(name, help string, labelNames []string, fn func(emit func(value float64, labelValues ...string))) {
+	r.register(&funcCollector{
+		desc:      desc{name, help, typeGauge, labelNames},
+		collectFn: fn,
+	})
+}
+
+func writeSample(w *strings.Builder, name string, labelNames, labelValues []string, value float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 {
+		w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, labelName, escapeLabelValue(labelValues[i]))
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return fmt.Sprint(value)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labelNames...)
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labelNames...)
+}
+
+func NewGauge(name, help string) *Gauge {
+	return Default.NewGauge(name, help)
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labelNames...)
+}
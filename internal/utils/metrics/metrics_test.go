@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Gather(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(r *Registry)
+		expected string
+	}{
+		{
+			name: "counter with labels",
+			setup: func(r *Registry) {
+				c := r.NewCounterVec("requests_total", "Total requests.", "method", "code")
+				c.WithLabelValues("POST", "201").Inc()
+				c.WithLabelValues("GET", "200").Add(2)
+			},
+			expected: "# HELP requests_total Total requests.\n" +
+				"# TYPE requests_total counter\n" +
+				`requests_total{method="GET",code="200"} 2` + "\n" +
+				`requests_total{method="POST",code="201"} 1` + "\n",
+		},
+		{
+			name: "gauge without labels",
+			setup: func(r *Registry) {
+				g := r.NewGauge("in_flight", "In-flight requests.")
+				g.Inc()
+				g.Inc()
+				g.Dec()
+			},
+			expected: "# HELP in_flight In-flight requests.\n" +
+				"# TYPE in_flight gauge\n" +
+				"in_flight 1\n",
+		},
+		{
+			name: "histogram buckets are cumulative",
+			setup: func(r *Registry) {
+				h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+				h.WithLabelValues("/tasks").Observe(0.05)
+				h.WithLabelValues("/tasks").Observe(0.5)
+				h.WithLabelValues("/tasks").Observe(3)
+			},
+			expected: "# HELP latency_seconds Latency.\n" +
+				"# TYPE latency_seconds histogram\n" +
+				`latency_seconds_bucket{route="/tasks",le="0.1"} 1` + "\n" +
+				`latency_seconds_bucket{route="/tasks",le="1"} 2` + "\n" +
+				`latency_seconds_bucket{route="/tasks",le="+Inf"} 3` + "\n" +
+				`latency_seconds_sum{route="/tasks"} 3.55` + "\n" +
+				`latency_seconds_count{route="/tasks"} 3` + "\n",
+		},
+		{
+			name: "func collectors and ordering by name",
+			setup: func(r *Registry) {
+				r.NewGaugeFunc("queue_length", "Queue length.", func() float64 { return 7 })
+				r.NewGaugeVecFunc("items", "Items by state.", []string{"state"}, func(emit func(float64, ...string)) {
+					emit(0, "done")
+					emit(3, "open")
+				})
+			},
+			expected: "# HELP items Items by state.\n" +
+				"# TYPE items gauge\n" +
+				`items{state="done"} 0` + "\n" +
+				`items{state="open"} 3` + "\n" +
+				"# HELP queue_length Queue length.\n" +
+				"# TYPE queue_length gauge\n" +
+				"queue_length 7\n",
+		},
+		{
+			name: "escaping",
+			setup: func(r *Registry) {
+				c := r.NewCounterVec("escaped_total", "Line one\nback\\slash.", "value")
+				c.WithLabelValues("say \"hi\"\n\\").Inc()
+			},
+			expected: "# HELP escaped_total Line one\\nback\\\\slash.\n" +
+				"# TYPE escaped_total counter\n" +
+				`escaped_total{value="say \"hi\"\n\\"} 1` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := CreateRegistry()
+			tt.setup(r)
+			assert.Equal(t, tt.expected, r.Gather())
+		})
+	}
+}
+
+func TestRegistry_DuplicateRegistrationPanics(t *testing.T) {
+	r := CreateRegistry()
+	r.NewCounterVec("duplicate_total", "First.")
+
+	assert.Panics(t, func() {
+		r.NewGauge("duplicate_total", "Second.")
+	})
+
+	r.Unregister("duplicate_total")
+	assert.NotPanics(t, func() {
+		r.NewGauge("duplicate_total", "Second.")
+	})
+}
+
+func TestVec_WrongLabelCountPanics(t *testing.T) {
+	c := CreateRegistry().NewCounterVec("labelled_total", "Labelled.", "a", "b")
+
+	assert.Panics(t, func() {
+		c.WithLabelValues("only-one")
+	})
+}
+
+func TestCounter_RejectsNegativeDelta(t *testing.T) {
+	c := CreateRegistry().NewCounterVec("monotonic_total", "Monotonic.").WithLabelValues()
+
+	assert.Panics(t, func() {
+		c.Add(-1)
+	})
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := CreateRegistry()
+	r.NewGauge("up", "Whether the service is up.").Set(1)
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := io.ReadAll(w.Body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, string(body), "up 1\n")
+}
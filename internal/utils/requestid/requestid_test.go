@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		expected bool
+	}{
+		{name: "uuid", id: "3f2b8c1e-4a5d-4e6f-8a7b-9c0d1e2f3a4b", expected: true},
+		{name: "opaque token", id: "req_01HZX:abc/def+1=", expected: true},
+		{name: "empty", id: "", expected: false},
+		{name: "too long", id: strings.Repeat("a", maxLength+1), expected: false},
+		{name: "space", id: "req 1", expected: false},
+		{name: "newline", id: "req\n1", expected: false},
+		{name: "quote", id: `req"1`, expected: false},
+		{name: "non-ascii", id: "запрос", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Valid(tt.id))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := Generate(), Generate()
+
+	assert.Regexp(t, uuidV4, first)
+	assert.True(t, Valid(first))
+	assert.NotEqual(t, first, second)
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "req-1", FromContext(NewContext(context.Background(), "req-1")))
+}
@@ -0,0 +1,27 @@
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/utils/requestid"
+)
+
+type ErrorBody struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Error writes a JSON error body that includes the request ID, so a client
+// can quote it when reporting a failed request.
+func Error(w http.ResponseWriter, r *http.Request, message string, status int) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(ErrorBody{
+		Error:     message,
+		RequestID: requestid.FromContext(r.Context()),
+	})
+}
@@ -0,0 +1,253 @@
+package taskcodec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+const maxLineSize = 1 << 20
+
+// ErrMalformed is returned when the file cannot be read past some point, e.g.
+// a JSON syntax error outside of a single record.
+var ErrMalformed = errors.New("malformed file")
+
+// Record is a task read from a file; Row is its 1-based position among the
+// records (the CSV header does not count). Err is set when this record could
+// not be decoded; decoding continues with the next one.
+type Record struct {
+	Row  int
+	Task models.Task
+	Err  error
+}
+
+type Decoder interface {
+	// Next returns io.EOF after the last record and any other error when
+	// the rest of the file cannot be read.
+	Next() (Record, error)
+}
+
+// CreateDecoder returns a decoder reading a list of tasks; unknown formats
+// fall back to JSON.
+func CreateDecoder(r io.Reader, format Format) Decoder {
+	c, ok := codecs[format]
+	if !ok {
+		c = codecs[FormatJSON]
+	}
+	return c.newDecoder(r)
+}
+
+func newJSONDecoder(r io.Reader) Decoder {
+	return &jsonDecoder{decoder: json.NewDecoder(r)}
+}
+
+func newNDJSONDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+func newCSVDecoder(r io.Reader) Decoder {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	return &csvDecoder{reader: reader}
+}
+
+type jsonDecoder struct {
+	decoder *json.Decoder
+	started bool
+	row     int
+}
+
+func (d *jsonDecoder) Next() (Record, error) {
+	if !d.started {
+		d.started = true
+		token, err := d.decoder.Token()
+		if err != nil && !isSyntaxError(err) {
+			return Record{}, err
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return Record{}, fmt.Errorf("%w: expected a JSON array of tasks", ErrMalformed)
+		}
+	}
+
+	if !d.decoder.More() {
+		if _, err := d.decoder.Token(); err != nil {
+			return Record{}, malformed(err)
+		}
+		return Record{}, io.EOF
+	}
+
+	// Reading the raw value first separates a broken document from a
+	// well-formed record with wrong fields, which only fails that record.
+	var raw json.RawMessage
+	if err := d.decoder.Decode(&raw); err != nil {
+		return Record{}, malformed(err)
+	}
+
+	d.row++
+	return decodeJSONRecord(d.row, raw), nil
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (d *ndjsonDecoder) Next() (Record, error) {
+	for d.scanner.Scan() {
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		d.row++
+		return decodeJSONRecord(d.row, line), nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return Record{}, fmt.Errorf("%w: record %d is longer than %d bytes", ErrMalformed, d.row+1, maxLineSize)
+		}
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+func decodeJSONRecord(row int, data []byte) Record {
+	record := Record{Row: row}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&record.Task); err != nil {
+		record.Err = fmt.Errorf("%w: %v", errs.ErrValidation, err)
+	}
+	return record
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns []string
+	row     int
+}
+
+func (d *csvDecoder) Next() (Record, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return Record{}, err
+		}
+	}
+
+	fields, err := d.reader.Read()
+	if err == io.EOF {
+		return Record{}, io.EOF
+	}
+
+	d.row++
+	record := Record{Row: d.row}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		record.Err = fmt.Errorf("%w: line %d: %v", errs.ErrValidation, parseErr.Line, parseErr.Err)
+		return record, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	for i, column := range d.columns {
+		if err := setField(&record.Task, column, fields[i]); err != nil {
+			record.Err = fmt.Errorf("%w: %s: %v", errs.ErrValidation, column, err)
+			break
+		}
+	}
+	return record, nil
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return malformed(err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet programs like to start UTF-8 files with a BOM.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if !slices.Contains(csvHeader, name) {
+			return fmt.Errorf("%w: unknown column %q, want some of %v", ErrMalformed, name, csvHeader)
+		}
+		if slices.Contains(columns[:i], name) {
+			return fmt.Errorf("%w: duplicate column %q", ErrMalformed, name)
+		}
+		columns[i] = name
+	}
+	if !slices.Contains(columns, "title") {
+		return fmt.Errorf("%w: missing column \"title\"", ErrMalformed)
+	}
+
+	d.columns = columns
+	return nil
+}
+
+func setField(task *models.Task, column, value string) error {
+	var err error
+	switch column {
+	case "id":
+		if value != "" {
+			task.ID, err = strconv.ParseInt(value, 10, 64)
+		}
+	case "title":
+		task.Title = value
+	case "description":
+		task.Description = value
+	case "status":
+		task.Status = models.TaskStatus(value)
+	case "created_at":
+		task.CreatedAt, err = parseTime(value)
+	case "updated_at":
+		task.UpdatedAt, err = parseTime(value)
+	}
+	return err
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func isSyntaxError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var xmlSyntaxErr *xml.SyntaxError
+	return errors.As(err, &syntaxErr) || errors.As(err, &xmlSyntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF
+}
+
+// malformed marks syntax errors; read errors, such as an exceeded body size
+// limit, are passed through as they are.
+func malformed(err error) error {
+	if isSyntaxError(err) {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return err
+}
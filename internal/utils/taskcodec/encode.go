@@ -0,0 +1,154 @@
+package taskcodec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+// csvHeader lists the CSV columns in the order they are written.
+var csvHeader = []string{"id", "title", "description", "status", "created_at", "updated_at"}
+
+type Encoder interface {
+	Encode(task *models.Task) error
+	// Close finishes the document; it does not close the underlying writer.
+	Close() error
+}
+
+// CreateEncoder returns an encoder writing a list of tasks; unknown formats
+// fall back to JSON.
+func CreateEncoder(w io.Writer, format Format) Encoder {
+	c, ok := codecs[format]
+	if !ok {
+		c = codecs[FormatJSON]
+	}
+	return c.newEncoder(w)
+}
+
+// WriteTask writes a single task, e.g. a JSON object rather than an array.
+func WriteTask(w io.Writer, format Format, task *models.Task) error {
+	c, ok := codecs[format]
+	if !ok {
+		c = codecs[FormatJSON]
+	}
+	if c.writeTask != nil {
+		return c.writeTask(w, task)
+	}
+
+	encoder := c.newEncoder(w)
+	if err := encoder.Encode(task); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func writeJSONTask(w io.Writer, task *models.Task) error {
+	return json.NewEncoder(w).Encode(task)
+}
+
+// jsonEncoder writes a JSON array with one task per line.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) Encoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) Encode(task *models.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	e.count++
+
+	_, err = io.WriteString(e.w, prefix+string(data))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if e.count == 0 {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) Encoder {
+	return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(task *models.Task) error {
+	return e.encoder.Encode(task)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVEncoder(w io.Writer) Encoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(task *models.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	return e.writer.Write([]string{
+		strconv.FormatInt(task.ID, 10),
+		task.Title,
+		task.Description,
+		string(task.Status),
+		formatTime(task.CreatedAt),
+		formatTime(task.UpdatedAt),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// writeHeader writes the header once, so that an empty export is still a
+// valid import file.
+func (e *csvEncoder) writeHeader() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+
+	return e.writer.Write(csvHeader)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
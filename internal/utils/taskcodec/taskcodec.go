@@ -0,0 +1,153 @@
+// Package taskcodec reads and writes tasks as JSON, NDJSON, CSV and XML, one
+// task at a time, and picks the format for a Content-Type or Accept header.
+package taskcodec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatXML    Format = "xml"
+)
+
+// Formats lists the supported formats in order of preference; the first one
+// is the default.
+var Formats = []Format{FormatJSON, FormatNDJSON, FormatCSV, FormatXML}
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// codec is everything the package knows about one format. Adding a format
+// means adding it to Formats and codecs.
+type codec struct {
+	// mediaTypes are matched against Accept and Content-Type headers.
+	mediaTypes  []string
+	contentType string
+	newEncoder  func(w io.Writer) Encoder
+	newDecoder  func(r io.Reader) Decoder
+	// writeTask writes a single task. Without it a list of one is written,
+	// which is the same thing for line-based formats.
+	writeTask func(w io.Writer, task *models.Task) error
+}
+
+var codecs = map[Format]codec{
+	FormatJSON: {
+		mediaTypes:  []string{"application/json"},
+		contentType: "application/json",
+		newEncoder:  newJSONEncoder,
+		newDecoder:  newJSONDecoder,
+		writeTask:   writeJSONTask,
+	},
+	FormatNDJSON: {
+		mediaTypes:  []string{"application/x-ndjson", "application/ndjson", "application/jsonl"},
+		contentType: "application/x-ndjson",
+		newEncoder:  newNDJSONEncoder,
+		newDecoder:  newNDJSONDecoder,
+	},
+	FormatCSV: {
+		mediaTypes:  []string{"text/csv"},
+		contentType: "text/csv; charset=utf-8",
+		newEncoder:  newCSVEncoder,
+		newDecoder:  newCSVDecoder,
+	},
+	FormatXML: {
+		mediaTypes:  []string{"application/xml", "text/xml"},
+		contentType: "application/xml; charset=utf-8",
+		newEncoder:  newXMLEncoder,
+		newDecoder:  newXMLDecoder,
+		writeTask:   writeXMLTask,
+	},
+}
+
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if !slices.Contains(Formats, format) {
+		return "", fmt.Errorf("%w %q, want one of %v", ErrUnknownFormat, name, Formats)
+	}
+	return format, nil
+}
+
+// lookup finds the first format, in order of preference, with a media type
+// matching mediaType, which may be a wildcard such as "*/*" or "text/*".
+func lookup(mediaType string) (Format, bool) {
+	for _, format := range Formats {
+		for _, candidate := range codecs[format].mediaTypes {
+			if mediaTypeMatches(mediaType, candidate) {
+				return format, true
+			}
+		}
+	}
+	return "", false
+}
+
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	return ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(mediaType, prefix)
+}
+
+// FormatForMediaType maps a Content-Type value to a format.
+func FormatForMediaType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid media type %q", ErrUnknownFormat, contentType)
+	}
+
+	format, ok := lookup(mediaType)
+	if !ok || strings.Contains(mediaType, "*") {
+		return "", fmt.Errorf("%w: unsupported media type %q", ErrUnknownFormat, mediaType)
+	}
+	return format, nil
+}
+
+// FormatForAccept picks the format preferred by an Accept header. An empty
+// header selects JSON, a wildcard the first format it matches, e.g. CSV for
+// "text/*".
+func FormatForAccept(accept string) (Format, error) {
+	if strings.TrimSpace(accept) == "" {
+		return Formats[0], nil
+	}
+
+	best, bestQuality := Format(""), 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		format, ok := lookup(mediaType)
+		if ok && quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("%w: none of %q is supported", ErrUnknownFormat, accept)
+	}
+	return best, nil
+}
+
+func (f Format) ContentType() string {
+	return codecs[f].contentType
+}
@@ -0,0 +1,260 @@
+package taskcodec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+var testTasks = []*models.Task{
+	{
+		ID:          1,
+		Title:       "First task",
+		Description: "Has \"quotes\", commas\nand a newline",
+		Status:      models.StatusPending,
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC),
+		UpdatedAt:   time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC),
+	},
+	{
+		ID:        2,
+		Title:     "Second task",
+		Status:    models.StatusCompleted,
+		CreatedAt: time.Date(2024, 2, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 2, 3, 3, 4, 5, 0, time.UTC),
+	},
+}
+
+func encode(t *testing.T, format Format, tasks []*models.Task) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	encoder := CreateEncoder(&b, format)
+	for _, task := range tasks {
+		require.NoError(t, encoder.Encode(task))
+	}
+	require.NoError(t, encoder.Close())
+	return b.String()
+}
+
+func decodeAll(t *testing.T, format Format, data string) ([]Record, error) {
+	t.Helper()
+
+	decoder := CreateDecoder(strings.NewReader(data), format)
+	var records []Record
+	for {
+		record, err := decoder.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			records, err := decodeAll(t, format, encode(t, format, testTasks))
+			require.NoError(t, err)
+			require.Len(t, records, len(testTasks))
+
+			for i, record := range records {
+				require.NoError(t, record.Err)
+				assert.Equal(t, i+1, record.Row)
+				assert.Equal(t, *testTasks[i], record.Task)
+			}
+		})
+	}
+}
+
+func TestEncode_Empty(t *testing.T) {
+	assert.Equal(t, "[]\n", encode(t, FormatJSON, nil))
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<tasks>\n</tasks>\n", encode(t, FormatXML, nil))
+	assert.Equal(t, "", encode(t, FormatNDJSON, nil))
+	assert.Equal(t, "id,title,description,status,created_at,updated_at\n", encode(t, FormatCSV, nil))
+
+	for _, format := range Formats {
+		records, err := decodeAll(t, format, encode(t, format, nil))
+		assert.NoError(t, err, format)
+		assert.Empty(t, records, format)
+	}
+}
+
+func TestWriteTask(t *testing.T) {
+	task := testTasks[1]
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: FormatJSON, expected: `{"id":2,"title":"Second task","description":"","status":"completed","created_at":"2024-02-02T03:04:05Z","updated_at":"2024-02-03T03:04:05Z"}` + "\n"},
+		{format: FormatNDJSON, expected: `{"id":2,"title":"Second task","description":"","status":"completed","created_at":"2024-02-02T03:04:05Z","updated_at":"2024-02-03T03:04:05Z"}` + "\n"},
+		{format: FormatCSV, expected: "id,title,description,status,created_at,updated_at\n2,Second task,,completed,2024-02-02T03:04:05Z,2024-02-03T03:04:05Z\n"},
+		{format: FormatXML, expected: xml.Header + "<task><id>2</id><title>Second task</title><description></description><status>completed</status><created_at>2024-02-02T03:04:05Z</created_at><updated_at>2024-02-03T03:04:05Z</updated_at></task>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, WriteTask(&b, tt.format, task))
+			assert.Equal(t, tt.expected, b.String())
+		})
+	}
+}
+
+func TestDecode_RecordErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		failed []int
+	}{
+		{
+			name:   "JSON Wrong Types And Unknown Fields",
+			format: FormatJSON,
+			data:   `[{"title":"Good task"}, {"id":"1","title":"Bad id"}, {"title":"Extra","color":"red"}, null]`,
+			failed: []int{2, 3},
+		},
+		{
+			name:   "NDJSON Broken Line",
+			format: FormatNDJSON,
+			data:   "{\"title\":\"Good task\"}\n\n{broken\n{\"title\":\"Also good\"}\n",
+			failed: []int{2},
+		},
+		{
+			name:   "XML Bad Values And Unknown Elements",
+			format: FormatXML,
+			data:   "<tasks><task><title>Good task</title></task><task><id>abc</id><title>Bad id</title></task><task><title>Extra</title><color>red</color></task></tasks>",
+			failed: []int{2, 3},
+		},
+		{
+			name:   "CSV Bad Values",
+			format: FormatCSV,
+			data:   "\ufeffTitle,id,created_at\nGood task,,\nBad id,abc,\nBad time,3,yesterday\nToo,many,fields,here\nPartial columns,4,2024-01-02T03:04:05Z\n",
+			failed: []int{2, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := decodeAll(t, tt.format, tt.data)
+			require.NoError(t, err)
+
+			var failed []int
+			for _, record := range records {
+				if record.Err != nil {
+					assert.ErrorIs(t, record.Err, errs.ErrValidation)
+					failed = append(failed, record.Row)
+				}
+			}
+			assert.Equal(t, tt.failed, failed)
+			assert.Equal(t, "Good task", records[0].Task.Title)
+		})
+	}
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{name: "JSON Not An Array", format: FormatJSON, data: `{"title":"Task"}`},
+		{name: "JSON Empty", format: FormatJSON, data: ``},
+		{name: "JSON Truncated", format: FormatJSON, data: `[{"title":"Task"},`},
+		{name: "JSON Broken Element", format: FormatJSON, data: `[{"title":"Task"}, {title}]`},
+		{name: "NDJSON Line Too Long", format: FormatNDJSON, data: `{"title":"` + strings.Repeat("a", maxLineSize) + `"}`},
+		{name: "XML Empty", format: FormatXML, data: ``},
+		{name: "XML Wrong Root", format: FormatXML, data: `<task><title>Task</title></task>`},
+		{name: "XML Unexpected Element", format: FormatXML, data: `<tasks><note>Task</note></tasks>`},
+		{name: "XML Truncated", format: FormatXML, data: `<tasks><task><title>Task</title></task>`},
+		{name: "XML Broken Element", format: FormatXML, data: `<tasks><task><title>Task</task></tasks>`},
+		{name: "CSV Unknown Column", format: FormatCSV, data: "title,priority\nTask,high\n"},
+		{name: "CSV Duplicate Column", format: FormatCSV, data: "title,title\nTask,Task\n"},
+		{name: "CSV Missing Title", format: FormatCSV, data: "id,status\n1,pending\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeAll(t, tt.format, tt.data)
+			assert.ErrorIs(t, err, ErrMalformed)
+		})
+	}
+}
+
+func TestDecode_ReadErrorsPassThrough(t *testing.T) {
+	readErr := errors.New("connection reset")
+	for _, format := range Formats {
+		decoder := CreateDecoder(iotest.ErrReader(readErr), format)
+		_, err := decoder.Next()
+		assert.ErrorIs(t, err, readErr, format)
+		assert.NotErrorIs(t, err, ErrMalformed, format)
+	}
+}
+
+func TestFormatForAccept(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected Format
+		wantErr  bool
+	}{
+		{accept: "", expected: FormatJSON},
+		{accept: "*/*", expected: FormatJSON},
+		{accept: "text/csv", expected: FormatCSV},
+		{accept: "application/x-ndjson", expected: FormatNDJSON},
+		{accept: "text/csv;q=0.5, application/json", expected: FormatJSON},
+		{accept: "text/html, text/csv;q=0.8, */*;q=0.1", expected: FormatCSV},
+		{accept: "application/json;q=0, text/csv;q=0.1", expected: FormatCSV},
+		{accept: "text/*", expected: FormatCSV},
+		{accept: "application/*;q=0.5, application/xml", expected: FormatXML},
+		{accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expected: FormatXML},
+		{accept: "text/html", wantErr: true},
+		{accept: "application/json;q=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			format, err := FormatForAccept(tt.accept)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnknownFormat)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestFormatForMediaType(t *testing.T) {
+	format, err := FormatForMediaType("text/csv; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = FormatForMediaType("text/plain")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	format, err = ParseFormat("NDJSON")
+	require.NoError(t, err)
+	assert.Equal(t, FormatNDJSON, format)
+
+	_, err = ParseFormat("yaml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	format, err = FormatForMediaType("text/xml")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXML, format)
+
+	_, err = FormatForMediaType("text/*")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
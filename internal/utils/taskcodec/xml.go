@@ -0,0 +1,166 @@
+package taskcodec
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+// xmlTask uses the CSV column names as element names and the CSV encoding of
+// IDs and times, so the two formats read and write values the same way.
+type xmlTask struct {
+	XMLName     xml.Name `xml:"task"`
+	ID          string   `xml:"id"`
+	Title       string   `xml:"title"`
+	Description string   `xml:"description"`
+	Status      string   `xml:"status"`
+	CreatedAt   string   `xml:"created_at"`
+	UpdatedAt   string   `xml:"updated_at"`
+	// Unknown collects elements that are not task fields.
+	Unknown []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func toXMLTask(task *models.Task) xmlTask {
+	return xmlTask{
+		ID:          strconv.FormatInt(task.ID, 10),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      string(task.Status),
+		CreatedAt:   formatTime(task.CreatedAt),
+		UpdatedAt:   formatTime(task.UpdatedAt),
+	}
+}
+
+// xmlEncoder writes a <tasks> element with one <task> per line.
+type xmlEncoder struct {
+	w       io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func newXMLEncoder(w io.Writer) Encoder {
+	return &xmlEncoder{w: w, encoder: xml.NewEncoder(w)}
+}
+
+func (e *xmlEncoder) Encode(task *models.Task) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	if err := e.encoder.Encode(toXMLTask(task)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+func (e *xmlEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(e.w, "</tasks>\n")
+	return err
+}
+
+func (e *xmlEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	_, err := io.WriteString(e.w, xml.Header+"<tasks>\n")
+	return err
+}
+
+func writeXMLTask(w io.Writer, task *models.Task) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(w).Encode(toXMLTask(task)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type xmlDecoder struct {
+	decoder *xml.Decoder
+	started bool
+	row     int
+}
+
+func newXMLDecoder(r io.Reader) Decoder {
+	return &xmlDecoder{decoder: xml.NewDecoder(r)}
+}
+
+func (d *xmlDecoder) Next() (Record, error) {
+	for {
+		token, err := d.decoder.Token()
+		if err == io.EOF && d.started {
+			return Record{}, io.EOF
+		}
+		if err != nil {
+			if err == io.EOF {
+				return Record{}, fmt.Errorf("%w: expected a <tasks> element", ErrMalformed)
+			}
+			return Record{}, malformed(err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			// Text between elements, comments, processing instructions and
+			// the end of <tasks>.
+			continue
+		}
+
+		if !d.started {
+			if start.Name.Local != "tasks" {
+				return Record{}, fmt.Errorf("%w: expected a <tasks> element, got <%s>", ErrMalformed, start.Name.Local)
+			}
+			d.started = true
+			continue
+		}
+		if start.Name.Local != "task" {
+			return Record{}, fmt.Errorf("%w: expected a <task> element, got <%s>", ErrMalformed, start.Name.Local)
+		}
+
+		var raw xmlTask
+		if err := d.decoder.DecodeElement(&raw, &start); err != nil {
+			return Record{}, malformed(err)
+		}
+
+		d.row++
+		return decodeXMLRecord(d.row, raw), nil
+	}
+}
+
+func decodeXMLRecord(row int, raw xmlTask) Record {
+	record := Record{Row: row}
+	if len(raw.Unknown) > 0 {
+		record.Err = fmt.Errorf("%w: unknown element <%s>", errs.ErrValidation, raw.Unknown[0].XMLName.Local)
+		return record
+	}
+
+	fields := []struct{ column, value string }{
+		{"id", raw.ID},
+		{"title", raw.Title},
+		{"description", raw.Description},
+		{"status", raw.Status},
+		{"created_at", raw.CreatedAt},
+		{"updated_at", raw.UpdatedAt},
+	}
+	for _, field := range fields {
+		if err := setField(&record.Task, field.column, field.value); err != nil {
+			record.Err = fmt.Errorf("%w: %s: %v", errs.ErrValidation, field.column, err)
+			break
+		}
+	}
+	return record
+}
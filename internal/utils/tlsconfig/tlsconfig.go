@@ -0,0 +1,193 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+const (
+	ClientAuthNone             = "none"
+	ClientAuthRequest          = "request"
+	ClientAuthRequire          = "require"
+	ClientAuthVerifyIfGiven    = "verify_if_given"
+	ClientAuthRequireAndVerify = "require_and_verify"
+)
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	ClientAuthNone:             tls.NoClientCert,
+	ClientAuthRequest:          tls.RequestClientCert,
+	ClientAuthRequire:          tls.RequireAnyClientCert,
+	ClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	ClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+func ParseClientAuth(name string) (tls.ClientAuthType, error) {
+	authType, ok := clientAuthTypes[name]
+	if !ok {
+		return tls.NoClientCert, fmt.Errorf("tlsconfig: unknown client auth %q", name)
+	}
+
+	return authType, nil
+}
+
+// Options describes the server side of TLS. ClientCAFile is required when
+// ClientAuth verifies client certificates.
+type Options struct {
+	CertFile      string
+	KeyFile       string
+	ClientCAFile  string
+	ClientAuth    string
+	CheckInterval time.Duration
+}
+
+// CreateServerConfig returns a TLS 1.2+ configuration whose certificate is
+// served by a CertReloader, so rotated certificates are picked up without a
+// restart.
+func CreateServerConfig(opts Options) (*tls.Config, error) {
+	authType, err := ParseClientAuth(opts.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	reloader, err := CreateCertReloader(opts.CertFile, opts.KeyFile, opts.CheckInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+		ClientAuth:     authType,
+	}
+
+	if opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tlsconfig: read client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tlsconfig: no certificates found in %s", opts.ClientCAFile)
+		}
+		config.ClientCAs = pool
+	}
+
+	if (authType == tls.VerifyClientCertIfGiven || authType == tls.RequireAndVerifyClientCert) && config.ClientCAs == nil {
+		return nil, fmt.Errorf("tlsconfig: client auth %q requires a client CA file", opts.ClientAuth)
+	}
+
+	return config, nil
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// CertReloader serves a certificate and key pair from disk and reloads them
+// when either file changes. Files are checked during handshakes, at most once
+// per check interval; if the new pair cannot be loaded, for example because
+// only one of the files has been replaced so far, the previous certificate
+// keeps being served and the check is repeated later.
+type CertReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+
+	cert atomic.Pointer[tls.Certificate]
+
+	mu        sync.Mutex
+	state     [2]fileState
+	lastCheck time.Time
+}
+
+func CreateCertReloader(certFile, keyFile string, checkInterval time.Duration) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+
+	state, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tlsconfig: load key pair: %w", err)
+	}
+
+	r.cert.Store(&cert)
+	r.state = state
+	r.lastCheck = time.Now()
+
+	return r, nil
+}
+
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.maybeReload()
+	return r.cert.Load(), nil
+}
+
+func (r *CertReloader) maybeReload() {
+	const funcName = "CertReloader.maybeReload"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastCheck) < r.checkInterval {
+		return
+	}
+	r.lastCheck = now
+
+	state, err := r.stat()
+	if err != nil {
+		logger.Error("failed to check TLS certificate", err, map[string]any{
+			"method": funcName,
+		})
+		return
+	}
+	if state == r.state {
+		return
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		logger.Error("failed to reload TLS certificate, keeping the current one", err, map[string]any{
+			"method":    funcName,
+			"cert_file": r.certFile,
+		})
+		return
+	}
+
+	r.cert.Store(&cert)
+	r.state = state
+
+	logger.Info("TLS certificate reloaded", map[string]any{
+		"method":    funcName,
+		"cert_file": r.certFile,
+	})
+}
+
+func (r *CertReloader) stat() ([2]fileState, error) {
+	var state [2]fileState
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return state, fmt.Errorf("tlsconfig: %w", err)
+		}
+		state[i] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSpan() SpanData {
+	start := time.Unix(1700000000, 0)
+	return SpanData{
+		Name: "Usecase.CreateTask",
+		SpanContext: SpanContext{
+			TraceID: TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:  SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			Flags:   FlagSampled,
+		},
+		ParentSpanID:  SpanID{0, 0, 0, 0, 0, 0, 0, 1},
+		Kind:          SpanKindInternal,
+		StartTime:     start,
+		EndTime:       start.Add(1500 * time.Microsecond),
+		Attributes:    map[string]any{"task_id": int64(42), "atomic": true, "title": "Task"},
+		StatusCode:    StatusError,
+		StatusMessage: "task not found",
+	}
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var received map[string]any
+	var contentType string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	exporter := CreateOTLPExporter(collector.URL+"/v1/traces", "task-service", time.Second)
+	require.NoError(t, exporter.Export(context.Background(), []SpanData{testSpan()}))
+
+	assert.Equal(t, "application/json", contentType)
+
+	resourceSpans := received["resourceSpans"].([]any)[0].(map[string]any)
+	resource := resourceSpans["resource"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{
+		"key":   "service.name",
+		"value": map[string]any{"stringValue": "task-service"},
+	}}, resource["attributes"])
+
+	span := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", span["spanId"])
+	assert.Equal(t, "0000000000000001", span["parentSpanId"])
+	assert.Equal(t, "Usecase.CreateTask", span["name"])
+	assert.Equal(t, float64(SpanKindInternal), span["kind"])
+	assert.Equal(t, "1700000000000000000", span["startTimeUnixNano"])
+	assert.Equal(t, "1700000000001500000", span["endTimeUnixNano"])
+	assert.Equal(t, map[string]any{"code": float64(StatusError), "message": "task not found"}, span["status"])
+	assert.Equal(t, []any{
+		map[string]any{"key": "atomic", "value": map[string]any{"boolValue": true}},
+		map[string]any{"key": "task_id", "value": map[string]any{"intValue": "42"}},
+		map[string]any{"key": "title", "value": map[string]any{"stringValue": "Task"}},
+	}, span["attributes"])
+}
+
+func TestOTLPExporter_CollectorError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	exporter := CreateOTLPExporter(collector.URL, "task-service", time.Second)
+	err := exporter.Export(context.Background(), []SpanData{testSpan()})
+
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestOTLPExporter_ThroughTracer(t *testing.T) {
+	requests := make(chan []byte, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- body
+	}))
+	defer collector.Close()
+
+	tracer := CreateTracer(CreateOTLPExporter(collector.URL, "task-service", time.Second))
+	_, span := tracer.Start(context.Background(), "request")
+	span.End()
+	require.NoError(t, tracer.Close(context.Background()))
+
+	select {
+	case body := <-requests:
+		assert.Contains(t, string(body), `"name":"request"`)
+	default:
+		t.Fatal("collector did not receive spans on close")
+	}
+}
+
+func TestStdoutExporter_Export(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := CreateStdoutExporter(&buf, "task-service")
+
+	require.NoError(t, exporter.Export(context.Background(), []SpanData{testSpan(), testSpan()}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &out))
+	assert.Equal(t, "task-service", out["service"])
+	assert.Equal(t, "Usecase.CreateTask", out["name"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", out["trace_id"])
+	assert.Equal(t, "0000000000000001", out["parent_span_id"])
+	assert.Equal(t, "internal", out["kind"])
+	assert.Equal(t, float64(1500*time.Microsecond), out["duration_ns"])
+	assert.Equal(t, "error", out["status"])
+	assert.Equal(t, "task not found", out["status_message"])
+}
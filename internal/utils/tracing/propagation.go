@@ -0,0 +1,235 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+
+	maxTracestateMembers = 32
+	maxTracestateLength  = 512
+)
+
+var (
+	ErrInvalidTraceparent = errors.New("tracing: invalid traceparent")
+	ErrInvalidTracestate  = errors.New("tracing: invalid tracestate")
+)
+
+type TraceID [16]byte
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type SpanID [8]byte
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type TraceFlags byte
+
+const FlagSampled TraceFlags = 0x01
+
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      TraceFlags
+	TraceState string
+	Remote     bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// Traceparent formats the span context as a version 00 traceparent value.
+func (sc SpanContext) Traceparent() string {
+	var b strings.Builder
+	b.Grow(55)
+	b.WriteString("00-")
+	b.WriteString(sc.TraceID.String())
+	b.WriteByte('-')
+	b.WriteString(sc.SpanID.String())
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString([]byte{byte(sc.Flags)}))
+	return b.String()
+}
+
+// ParseTraceparent parses a traceparent header value. Versions above 00 are
+// accepted as long as their first four fields follow the version 00 layout,
+// as the specification requires.
+func ParseTraceparent(value string) (SpanContext, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	version, ok := decodeLowerHex(value[0:2])
+	if !ok || version[0] == 0xff {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	if version[0] == 0 && len(value) != 55 {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	var sc SpanContext
+	traceID, ok := decodeLowerHex(value[3:35])
+	if !ok {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	copy(sc.TraceID[:], traceID)
+
+	spanID, ok := decodeLowerHex(value[36:52])
+	if !ok {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	copy(sc.SpanID[:], spanID)
+
+	flags, ok := decodeLowerHex(value[53:55])
+	if !ok {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	sc.Flags = TraceFlags(flags[0])
+
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	return sc, nil
+}
+
+func decodeLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return nil, false
+		}
+	}
+
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// ParseTracestate validates a tracestate header value and returns it with
+// empty list members removed. Vendor values are opaque, so only the list
+// structure and keys are checked.
+func ParseTracestate(value string) (string, error) {
+	if len(value) > maxTracestateLength {
+		return "", ErrInvalidTracestate
+	}
+
+	members := make([]string, 0, 4)
+	seen := make(map[string]bool)
+	for member := range strings.SplitSeq(value, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(member, "=")
+		if !ok || !validTracestateKey(key) || !validTracestateValue(val) || seen[key] {
+			return "", ErrInvalidTracestate
+		}
+		seen[key] = true
+		members = append(members, member)
+	}
+
+	if len(members) > maxTracestateMembers {
+		return "", ErrInvalidTracestate
+	}
+
+	return strings.Join(members, ","), nil
+}
+
+func validTracestateKey(key string) bool {
+	tenant, system, multiTenant := strings.Cut(key, "@")
+	if multiTenant {
+		return len(tenant) <= 241 && len(system) <= 14 &&
+			validKeyChars(tenant, true) && validKeyChars(system, false)
+	}
+	return len(key) <= 256 && validKeyChars(key, false)
+}
+
+func validKeyChars(s string, allowDigitFirst bool) bool {
+	if s == "" {
+		return false
+	}
+	first := s[0]
+	if !(first >= 'a' && first <= 'z') && !(allowDigitFirst && first >= '0' && first <= '9') {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') &&
+			c != '_' && c != '-' && c != '*' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+func validTracestateValue(value string) bool {
+	if value == "" || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract reads the remote span context from request headers. An invalid
+// tracestate is dropped while the traceparent is kept.
+func Extract(header http.Header) (SpanContext, bool) {
+	sc, err := ParseTraceparent(header.Get(TraceparentHeader))
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	if state, err := ParseTracestate(strings.Join(header.Values(TracestateHeader), ",")); err == nil {
+		sc.TraceState = state
+	}
+	sc.Remote = true
+
+	return sc, true
+}
+
+// Inject writes the span context carried by ctx into outgoing headers.
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	header.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(TracestateHeader, sc.TraceState)
+	} else {
+		header.Del(TracestateHeader)
+	}
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantErr   bool
+		traceID   string
+		spanID    string
+		sampled   bool
+		formatted string
+	}{
+		{
+			name:      "sampled",
+			value:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			traceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:    "00f067aa0ba902b7",
+			sampled:   true,
+			formatted: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:      "not sampled",
+			value:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			traceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:    "00f067aa0ba902b7",
+			formatted: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			name:      "future version with extra fields",
+			value:     "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-09-what-the-future-holds",
+			traceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:    "00f067aa0ba902b7",
+			sampled:   true,
+			formatted: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-09",
+		},
+		{name: "empty", value: "", wantErr: true},
+		{name: "version ff", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "version 00 with extra fields", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "uppercase hex", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "wrong separator", value: "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "short span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTraceparent)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.traceID, sc.TraceID.String())
+			assert.Equal(t, tt.spanID, sc.SpanID.String())
+			assert.Equal(t, tt.sampled, sc.IsSampled())
+			assert.Equal(t, tt.formatted, sc.Traceparent())
+		})
+	}
+}
+
+func TestParseTracestate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "single member", value: "congo=t61rcWkgMzE", expected: "congo=t61rcWkgMzE"},
+		{name: "empty members are dropped", value: "rojo=00f067aa0ba902b7, ,congo=t61rcWkgMzE", expected: "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE"},
+		{name: "multi-tenant key", value: "fw529a3039@dt=value", expected: "fw529a3039@dt=value"},
+		{name: "empty", value: "", expected: ""},
+		{name: "duplicate key", value: "a=1,a=2", wantErr: true},
+		{name: "uppercase key", value: "Congo=1", wantErr: true},
+		{name: "missing value", value: "congo=", wantErr: true},
+		{name: "missing equals", value: "congo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := ParseTracestate(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTracestate)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, state)
+		})
+	}
+}
+
+func TestExtractAndInject(t *testing.T) {
+	incoming := http.Header{}
+	incoming.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	incoming.Add(TracestateHeader, "rojo=00f067aa0ba902b7")
+	incoming.Add(TracestateHeader, "congo=t61rcWkgMzE")
+
+	remote, ok := Extract(incoming)
+	require.True(t, ok)
+	assert.True(t, remote.Remote)
+	assert.Equal(t, "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", remote.TraceState)
+
+	ctx, span := CreateTracer(nil).Start(ContextWithSpanContext(context.Background(), remote), "child")
+	defer span.End()
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+
+	sent, err := ParseTraceparent(outgoing.Get(TraceparentHeader))
+	require.NoError(t, err)
+	assert.Equal(t, remote.TraceID, sent.TraceID)
+	assert.Equal(t, span.SpanContext().SpanID, sent.SpanID)
+	assert.Equal(t, remote.TraceState, outgoing.Get(TracestateHeader))
+}
+
+func TestExtract_InvalidTracestateKeepsTraceparent(t *testing.T) {
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	header.Set(TracestateHeader, "INVALID")
+
+	sc, ok := Extract(header)
+	require.True(t, ok)
+	assert.Empty(t, sc.TraceState)
+}
+
+func TestInject_WithoutSpan(t *testing.T) {
+	header := http.Header{}
+	Inject(context.Background(), header)
+
+	assert.Empty(t, header.Get(TraceparentHeader))
+}
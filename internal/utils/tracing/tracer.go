@@ -0,0 +1,350 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/supchaser/LO_test_task/internal/utils/logger"
+)
+
+const (
+	defaultQueueSize = 2048
+	maxExportBatch   = 256
+	exportInterval   = 2 * time.Second
+)
+
+type SpanKind int
+
+// Values match the OTLP SpanKind enumeration.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+type StatusCode int
+
+// Values match the OTLP Status.StatusCode enumeration.
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+type SpanData struct {
+	Name          string
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]any
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+type spanContextKey struct{}
+
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+type StartOption func(*SpanData)
+
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(d *SpanData) {
+		d.Kind = kind
+	}
+}
+
+func WithAttributes(attributes map[string]any) StartOption {
+	return func(d *SpanData) {
+		for key, value := range attributes {
+			d.Attributes[key] = value
+		}
+	}
+}
+
+type Span struct {
+	tracer *Tracer
+	data   SpanData
+	ended  bool
+	mu     sync.Mutex
+}
+
+func (s *Span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *Span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Name = name
+	}
+}
+
+func (s *Span) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+}
+
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.StatusCode = code
+		s.data.StatusMessage = message
+	}
+}
+
+// RecordError marks the span as failed; a nil error is ignored so it can be
+// called unconditionally on return paths.
+func (s *Span) RecordError(err error) {
+	if err != nil {
+		s.SetStatus(StatusError, err.Error())
+	}
+}
+
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if data.SpanContext.IsSampled() {
+		s.tracer.enqueue(data)
+	}
+}
+
+// Tracer hands finished spans to the exporter in batches from a background
+// goroutine, so ending a span never waits on the network. Spans that do not
+// fit into the queue are dropped.
+type Tracer struct {
+	exporter Exporter
+	queue    chan SpanData
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Uint64
+	stopOnce sync.Once
+}
+
+// CreateTracer starts a tracer; with a nil exporter spans still propagate
+// through contexts and headers but are discarded when they end.
+func CreateTracer(exporter Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if exporter == nil {
+		close(t.done)
+		return t
+	}
+
+	t.queue = make(chan SpanData, defaultQueueSize)
+	go t.run()
+
+	return t
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	data := SpanData{
+		Name:       name,
+		Kind:       SpanKindInternal,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]any),
+	}
+	for _, opt := range opts {
+		opt(&data)
+	}
+
+	if parent.IsValid() {
+		data.SpanContext = SpanContext{
+			TraceID:    parent.TraceID,
+			Flags:      parent.Flags,
+			TraceState: parent.TraceState,
+		}
+		data.ParentSpanID = parent.SpanID
+	} else {
+		data.SpanContext = SpanContext{
+			TraceID: newTraceID(),
+			Flags:   FlagSampled,
+		}
+	}
+	data.SpanContext.SpanID = newSpanID()
+
+	span := &Span{tracer: t, data: data}
+
+	return ContextWithSpanContext(ctx, data.SpanContext), span
+}
+
+func (t *Tracer) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	if t.queue == nil {
+		return
+	}
+
+	select {
+	case <-t.stop:
+		t.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, maxExportBatch)
+	flush := func() {
+		if len(batch) > 0 {
+			t.export(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) == maxExportBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-t.stop:
+			for {
+				select {
+				case data := <-t.queue:
+					batch = append(batch, data)
+					if len(batch) == maxExportBatch {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracer) export(batch []SpanData) {
+	const funcName = "Tracer.export"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := t.exporter.Export(ctx, batch); err != nil {
+		logger.Error("failed to export spans", err, map[string]any{
+			"method": funcName,
+			"spans":  len(batch),
+		})
+	}
+}
+
+// Close exports the spans that already ended and shuts the exporter down.
+func (t *Tracer) Close(ctx context.Context) error {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
+
+var global atomic.Pointer[Tracer]
+
+func init() {
+	global.Store(CreateTracer(nil))
+}
+
+func SetTracer(t *Tracer) {
+	global.Store(t)
+}
+
+func GetTracer() *Tracer {
+	return global.Load()
+}
+
+// Start begins a span on the tracer installed with SetTracer.
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	return GetTracer().Start(ctx, name, opts...)
+}
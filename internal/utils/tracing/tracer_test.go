@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	spans    []SpanData
+	shutdown bool
+	mu       sync.Mutex
+}
+
+func (e *recordingExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.shutdown = true
+	return nil
+}
+
+func TestTracer_ParentChild(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := CreateTracer(exporter)
+
+	ctx, parent := tracer.Start(context.Background(), "parent", WithSpanKind(SpanKindServer))
+	_, child := tracer.Start(ctx, "child", WithAttributes(map[string]any{"task_id": 1}))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	parent.End()
+
+	require.NoError(t, tracer.Close(context.Background()))
+
+	require.Len(t, exporter.spans, 2)
+	childData, parentData := exporter.spans[0], exporter.spans[1]
+
+	assert.Equal(t, "parent", parentData.Name)
+	assert.Equal(t, SpanKindServer, parentData.Kind)
+	assert.False(t, parentData.ParentSpanID.IsValid())
+	assert.True(t, parentData.SpanContext.IsSampled())
+
+	assert.Equal(t, "child", childData.Name)
+	assert.Equal(t, parentData.SpanContext.TraceID, childData.SpanContext.TraceID)
+	assert.Equal(t, parentData.SpanContext.SpanID, childData.ParentSpanID)
+	assert.Equal(t, StatusError, childData.StatusCode)
+	assert.Equal(t, "boom", childData.StatusMessage)
+	assert.Equal(t, 1, childData.Attributes["task_id"])
+	assert.False(t, childData.EndTime.Before(childData.StartTime))
+
+	assert.True(t, exporter.shutdown)
+}
+
+func TestTracer_RemoteParent(t *testing.T) {
+	remote, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	remote.TraceState = "congo=t61rcWkgMzE"
+
+	exporter := &recordingExporter{}
+	tracer := CreateTracer(exporter)
+
+	_, span := tracer.Start(ContextWithSpanContext(context.Background(), remote), "server")
+	span.End()
+	require.NoError(t, tracer.Close(context.Background()))
+
+	require.Len(t, exporter.spans, 1)
+	assert.Equal(t, remote.TraceID, exporter.spans[0].SpanContext.TraceID)
+	assert.Equal(t, remote.SpanID, exporter.spans[0].ParentSpanID)
+	assert.Equal(t, remote.TraceState, exporter.spans[0].SpanContext.TraceState)
+	assert.NotEqual(t, remote.SpanID, exporter.spans[0].SpanContext.SpanID)
+}
+
+func TestTracer_UnsampledParentIsNotExported(t *testing.T) {
+	remote, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.NoError(t, err)
+
+	exporter := &recordingExporter{}
+	tracer := CreateTracer(exporter)
+
+	ctx, span := tracer.Start(ContextWithSpanContext(context.Background(), remote), "server")
+	span.End()
+	require.NoError(t, tracer.Close(context.Background()))
+
+	assert.Empty(t, exporter.spans)
+	assert.False(t, SpanContextFromContext(ctx).IsSampled())
+}
+
+func TestSpan_EndIsIdempotent(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := CreateTracer(exporter)
+
+	_, span := tracer.Start(context.Background(), "span")
+	span.End()
+	span.End()
+	span.SetAttribute("ignored", true)
+	require.NoError(t, tracer.Close(context.Background()))
+
+	require.Len(t, exporter.spans, 1)
+	assert.NotContains(t, exporter.spans[0].Attributes, "ignored")
+}
+
+func TestTracer_SpansAfterCloseAreDropped(t *testing.T) {
+	tracer := CreateTracer(&recordingExporter{})
+	require.NoError(t, tracer.Close(context.Background()))
+
+	_, span := tracer.Start(context.Background(), "late")
+	span.End()
+
+	assert.Equal(t, uint64(1), tracer.Dropped())
+}
+
+func TestTracer_WithoutExporter(t *testing.T) {
+	tracer := CreateTracer(nil)
+
+	ctx, span := tracer.Start(context.Background(), "span")
+	span.End()
+
+	assert.True(t, SpanContextFromContext(ctx).IsValid())
+	assert.NoError(t, tracer.Close(context.Background()))
+}
@@ -2,9 +2,11 @@ package validate
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"unicode/utf8"
 
+	"github.com/supchaser/LO_test_task/internal/app/models"
 	"github.com/supchaser/LO_test_task/internal/utils/errs"
 )
 
@@ -12,6 +14,14 @@ const (
 	MaxTaskTitleLength       = 200
 	MinTaskTitleLength       = 3
 	MaxTaskDescriptionLength = 5000
+	MaxWebhookURLLength      = 2048
+	MinWebhookSecretLength   = 16
+	MaxWebhookSecretLength   = 256
+
+	// MaxTaskID bounds imported and restored IDs, so that new IDs, which
+	// continue after the largest one, cannot overflow. It is also the largest
+	// integer a JSON client using doubles reads exactly.
+	MaxTaskID = 1<<53 - 1
 )
 
 var taskTitleRegex = regexp.MustCompile(`^[A-Za-z0-9А-Яа-я\s.,!?-]+$`)
@@ -37,6 +47,15 @@ func CheckTaskTitle(title string) error {
 	return nil
 }
 
+func CheckTaskStatus(status models.TaskStatus) error {
+	switch status {
+	case models.StatusPending, models.StatusInProgress, models.StatusCompleted:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown task status %q", errs.ErrValidation, status)
+	}
+}
+
 func CheckTaskDescription(description string) error {
 	length := utf8.RuneCountInString(description)
 	if length > MaxTaskDescriptionLength {
@@ -45,3 +64,45 @@ func CheckTaskDescription(description string) error {
 
 	return nil
 }
+
+func CheckWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("%w: webhook url cannot be empty", errs.ErrValidation)
+	}
+
+	if len(rawURL) > MaxWebhookURLLength {
+		return fmt.Errorf("%w: webhook url cannot be longer than %d characters", errs.ErrValidation, MaxWebhookURLLength)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an absolute http or https url", errs.ErrValidation)
+	}
+
+	return nil
+}
+
+func CheckWebhookSecret(secret string) error {
+	length := len(secret)
+	if length < MinWebhookSecretLength {
+		return fmt.Errorf("%w: webhook secret must be at least %d characters", errs.ErrValidation, MinWebhookSecretLength)
+	}
+
+	if length > MaxWebhookSecretLength {
+		return fmt.Errorf("%w: webhook secret cannot be longer than %d characters", errs.ErrValidation, MaxWebhookSecretLength)
+	}
+
+	return nil
+}
+
+func CheckWebhookEventTypes(eventTypes []models.TaskEventType) error {
+	for _, eventType := range eventTypes {
+		switch eventType {
+		case models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskDeleted, models.EventTaskStatusChanged:
+		default:
+			return fmt.Errorf("%w: unknown event type %q", errs.ErrValidation, eventType)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,479 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+
+	continuationFrame = 0
+)
+
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+)
+
+const (
+	DefaultMaxMessageSize = 1 << 20
+	DefaultWriteTimeout   = 10 * time.Second
+
+	acceptGUID          = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	maxControlFrameSize = 125
+	closeWriteTimeout   = time.Second
+)
+
+var (
+	ErrBadHandshake = errors.New("websocket: bad handshake")
+	ErrBadOrigin    = errors.New("websocket: origin not allowed")
+	ErrClosed       = errors.New("websocket: connection closed")
+	errProtocol     = errors.New("websocket: protocol error")
+)
+
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: close %d %s", e.Code, e.Reason)
+}
+
+func (e *CloseError) Unwrap() error {
+	return ErrClosed
+}
+
+type Conn struct {
+	conn           net.Conn
+	reader         *bufio.Reader
+	isServer       bool
+	maxMessageSize int64
+	writeTimeout   time.Duration
+	pongHandler    func(data []byte) error
+	writeMu        sync.Mutex
+	closeOnce      sync.Once
+	closeSent      bool
+	// closing is set by Close before it waits for writeMu, so writers that
+	// get the lock first give up instead of starting a long write.
+	closing atomic.Bool
+}
+
+// Upgrade takes over the connection of a websocket handshake request. Browsers
+// send cookies with cross-site websocket requests, so an Origin header must
+// name the server itself or one of allowedOrigins; "*" allows any origin.
+// Requests without Origin come from non-browser clients and are accepted.
+func Upgrade(w http.ResponseWriter, r *http.Request, allowedOrigins []string) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		return nil, upgradeError(w, http.StatusMethodNotAllowed, "method must be GET")
+	}
+	if !CheckOrigin(r, allowedOrigins) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("%w: %q", ErrBadOrigin, r.Header.Get("Origin"))
+	}
+	if !headerContainsToken(r.Header, "Connection", "upgrade") {
+		return nil, upgradeError(w, http.StatusBadRequest, "missing Connection: upgrade header")
+	}
+	if !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		return nil, upgradeError(w, http.StatusBadRequest, "missing Upgrade: websocket header")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, upgradeError(w, http.StatusUpgradeRequired, "unsupported websocket version")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return nil, upgradeError(w, http.StatusBadRequest, "invalid Sec-WebSocket-Key header")
+	}
+
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, upgradeError(w, http.StatusInternalServerError, "connection cannot be upgraded")
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(response)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return newConn(netConn, rw.Reader, true), nil
+}
+
+func AcceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// CheckOrigin reports whether the Origin header of r is missing, matches the
+// host r was sent to or is one of allowed, compared case-insensitively.
+func CheckOrigin(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func upgradeError(w http.ResponseWriter, status int, reason string) error {
+	http.Error(w, reason, status)
+	return fmt.Errorf("%w: %s", ErrBadHandshake, reason)
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newConn(netConn net.Conn, reader *bufio.Reader, isServer bool) *Conn {
+	if reader == nil {
+		reader = bufio.NewReader(netConn)
+	}
+	return &Conn{
+		conn:           netConn,
+		reader:         reader,
+		isServer:       isServer,
+		maxMessageSize: DefaultMaxMessageSize,
+		writeTimeout:   DefaultWriteTimeout,
+	}
+}
+
+func (c *Conn) SetMaxMessageSize(size int64) {
+	c.maxMessageSize = size
+}
+
+// SetWriteTimeout limits how long writing a single frame may take, so that a
+// peer that stops reading cannot hold the writer forever; zero disables it.
+func (c *Conn) SetWriteTimeout(timeout time.Duration) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.writeTimeout = timeout
+}
+
+func (c *Conn) SetPongHandler(handler func(data []byte) error) {
+	c.pongHandler = handler
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage returns the next complete data message. Control frames that
+// arrive in between are handled here: pings are answered, pongs are passed to
+// the pong handler and a close frame is echoed before returning *CloseError.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+
+	for {
+		frame, err := c.readFrame()
+		if err != nil {
+			return 0, nil, c.failOnProtocolError(err)
+		}
+
+		switch frame.opcode {
+		case PingMessage:
+			if err := c.writeFrame(true, PongMessage, frame.payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			if c.pongHandler != nil {
+				if err := c.pongHandler(frame.payload); err != nil {
+					return 0, nil, err
+				}
+			}
+			continue
+		case CloseMessage:
+			return 0, nil, c.handleClose(frame.payload)
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.failOnProtocolError(fmt.Errorf("%w: new message before previous one finished", errProtocol))
+			}
+			messageType = frame.opcode
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.failOnProtocolError(fmt.Errorf("%w: unexpected continuation frame", errProtocol))
+			}
+		default:
+			return 0, nil, c.failOnProtocolError(fmt.Errorf("%w: unknown opcode %d", errProtocol, frame.opcode))
+		}
+
+		if int64(len(message)+len(frame.payload)) > c.maxMessageSize {
+			c.Close(CloseMessageTooBig, "message too big")
+			return 0, nil, &CloseError{Code: CloseMessageTooBig, Reason: "message too big"}
+		}
+		message = append(message, frame.payload...)
+
+		if frame.fin {
+			if messageType == TextMessage && !utf8.Valid(message) {
+				c.Close(CloseInvalidPayload, "invalid utf-8")
+				return 0, nil, &CloseError{Code: CloseInvalidPayload, Reason: "invalid utf-8"}
+			}
+			return messageType, message, nil
+		}
+	}
+}
+
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("%w: invalid message type %d", errProtocol, messageType)
+	}
+	return c.writeFrame(true, messageType, data)
+}
+
+func (c *Conn) Ping(data []byte) error {
+	if len(data) > maxControlFrameSize {
+		return fmt.Errorf("%w: control frame payload too large", errProtocol)
+	}
+	return c.writeFrame(true, PingMessage, data)
+}
+
+// Close sends a close frame with the given code and closes the underlying
+// connection. It is safe to call more than once. A write stalled on a peer
+// that does not read is cut short first, so Close never waits longer than
+// the close timeout for the write lock.
+func (c *Conn) Close(code int, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.closing.Store(true)
+		c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+		c.writeMu.Lock()
+		if !c.closeSent {
+			c.closeSent = true
+			c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+			c.writeFrameLocked(true, CloseMessage, closePayload(code, reason))
+		}
+		c.writeMu.Unlock()
+		err = c.conn.Close()
+	})
+	return err
+}
+
+func (c *Conn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatus}
+	if len(payload) == 1 {
+		c.Close(CloseProtocolError, "invalid close payload")
+		return &CloseError{Code: CloseProtocolError}
+	}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+
+	replyCode := closeErr.Code
+	if replyCode == CloseNoStatus {
+		replyCode = CloseNormalClosure
+	}
+	c.Close(replyCode, "")
+
+	return closeErr
+}
+
+func (c *Conn) failOnProtocolError(err error) error {
+	if errors.Is(err, errProtocol) {
+		c.Close(CloseProtocolError, "protocol error")
+	}
+	return err
+}
+
+type frame struct {
+	fin     bool
+	opcode  int
+	payload []byte
+}
+
+func (c *Conn) readFrame() (frame, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return frame{}, err
+	}
+
+	f := frame{
+		fin:    header[0]&0x80 != 0,
+		opcode: int(header[0] & 0x0f),
+	}
+	if header[0]&0x70 != 0 {
+		return frame{}, fmt.Errorf("%w: reserved bits are set", errProtocol)
+	}
+
+	masked := header[1]&0x80 != 0
+	if masked != c.isServer {
+		return frame{}, fmt.Errorf("%w: invalid frame masking", errProtocol)
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return frame{}, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return frame{}, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+		if length < 0 {
+			return frame{}, fmt.Errorf("%w: invalid payload length", errProtocol)
+		}
+	}
+
+	if f.opcode >= CloseMessage && (length > maxControlFrameSize || !f.fin) {
+		return frame{}, fmt.Errorf("%w: invalid control frame", errProtocol)
+	}
+	if length > c.maxMessageSize {
+		c.Close(CloseMessageTooBig, "message too big")
+		return frame{}, &CloseError{Code: CloseMessageTooBig, Reason: "message too big"}
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return frame{}, err
+		}
+	}
+
+	f.payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, f.payload); err != nil {
+		return frame{}, err
+	}
+	if masked {
+		maskBytes(mask, f.payload)
+	}
+
+	return f, nil
+}
+
+func (c *Conn) writeFrame(fin bool, opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return ErrClosed
+	}
+
+	if c.writeTimeout > 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return err
+		}
+	}
+	// Checked after the deadline is set: either this deadline is replaced by
+	// the shorter one of Close, or Close has already started.
+	if c.closing.Load() {
+		return ErrClosed
+	}
+	return c.writeFrameLocked(fin, opcode, payload)
+}
+
+func (c *Conn) writeFrameLocked(fin bool, opcode int, payload []byte) error {
+	buf := make([]byte, 0, 14+len(payload))
+
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	buf = append(buf, first)
+
+	var maskBit byte
+	if !c.isServer {
+		maskBit = 0x80
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		buf = append(buf, maskBit|byte(length))
+	case length <= 0xffff:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(length))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(length))
+	}
+
+	if c.isServer {
+		buf = append(buf, payload...)
+	} else {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		buf = append(buf, mask[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		maskBytes(mask, buf[start:])
+	}
+
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+func closePayload(code int, reason string) []byte {
+	if code == CloseNoStatus {
+		return nil
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(reason) > maxControlFrameSize-2 {
+		reason = reason[:maxControlFrameSize-2]
+	}
+	return append(payload, reason...)
+}
+
+func maskBytes(mask [4]byte, data []byte) {
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
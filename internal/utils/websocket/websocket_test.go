@@ -0,0 +1,288 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close(CloseNormalClosure, "")
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server) *Conn {
+	t.Helper()
+
+	conn, resp, err := Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	t.Cleanup(func() { conn.Close(CloseNormalClosure, "") })
+
+	return conn
+}
+
+func TestUpgrade_Echo(t *testing.T) {
+	conn := dial(t, echoServer(t))
+
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte("hello")))
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, TextMessage, messageType)
+	assert.Equal(t, "hello", string(data))
+
+	large := []byte(strings.Repeat("x", 70000))
+	require.NoError(t, conn.WriteMessage(BinaryMessage, large))
+	messageType, data, err = conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, BinaryMessage, messageType)
+	assert.Equal(t, large, data)
+}
+
+func TestUpgrade_FragmentedMessageAndPing(t *testing.T) {
+	conn := dial(t, echoServer(t))
+
+	pongs := make(chan string, 1)
+	conn.SetPongHandler(func(data []byte) error {
+		pongs <- string(data)
+		return nil
+	})
+
+	require.NoError(t, conn.writeFrame(false, TextMessage, []byte("hel")))
+	require.NoError(t, conn.Ping([]byte("ping-1")))
+	require.NoError(t, conn.writeFrame(true, continuationFrame, []byte("lo")))
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, "ping-1", <-pongs)
+}
+
+func TestUpgrade_CloseHandshake(t *testing.T) {
+	closed := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_, _, err = conn.ReadMessage()
+		closed <- err
+	}))
+	defer server.Close()
+
+	conn := dial(t, server)
+	require.NoError(t, conn.writeFrame(true, CloseMessage, closePayload(CloseGoingAway, "bye")))
+
+	var closeErr *CloseError
+	err := <-closed
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseGoingAway, closeErr.Code)
+	assert.Equal(t, "bye", closeErr.Reason)
+	assert.ErrorIs(t, err, ErrClosed)
+
+	_, _, err = conn.ReadMessage()
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseGoingAway, closeErr.Code)
+}
+
+func TestUpgrade_RejectsInvalidHandshake(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{
+			name:           "Wrong Method",
+			method:         "POST",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "Missing Upgrade",
+			method:         "GET",
+			headers:        map[string]string{"Connection": "Upgrade"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Wrong Version",
+			method: "GET",
+			headers: map[string]string{
+				"Connection":            "keep-alive, Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "8",
+			},
+			expectedStatus: http.StatusUpgradeRequired,
+		},
+		{
+			name:   "Invalid Key",
+			method: "GET",
+			headers: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "13",
+				"Sec-WebSocket-Key":     "short",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/ws", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+
+			_, err := Upgrade(w, req, nil)
+
+			assert.ErrorIs(t, err, ErrBadHandshake)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestConn_RejectsUnmaskedClientFrames(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	defer clientSide.Close()
+
+	server := newConn(serverSide, nil, true)
+	unmaskedClient := newConn(clientSide, nil, true)
+
+	go unmaskedClient.WriteMessage(TextMessage, []byte("hello"))
+
+	clientSide.SetReadDeadline(time.Now().Add(time.Second))
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			if _, err := clientSide.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	_, _, err := server.ReadMessage()
+	assert.True(t, errors.Is(err, errProtocol))
+}
+
+func TestConn_MessageTooBig(t *testing.T) {
+	server := echoServer(t)
+	conn := dial(t, server)
+	conn.SetMaxMessageSize(DefaultMaxMessageSize * 2)
+
+	require.NoError(t, conn.WriteMessage(BinaryMessage, make([]byte, DefaultMaxMessageSize+1)))
+
+	var closeErr *CloseError
+	_, _, err := conn.ReadMessage()
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseMessageTooBig, closeErr.Code)
+}
+
+func TestUpgrade_CheckOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		allowed  []string
+		expected bool
+	}{
+		{name: "No Origin", expected: true},
+		{name: "Same Origin", origin: "http://example.com", expected: true},
+		{name: "Cross Origin", origin: "https://evil.example", expected: false},
+		{name: "Allowed Origin", origin: "https://app.example", allowed: []string{"https://APP.example/"}, expected: true},
+		{name: "Wildcard", origin: "https://evil.example", allowed: []string{"*"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			assert.Equal(t, tt.expected, CheckOrigin(req, tt.allowed))
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	w := httptest.NewRecorder()
+
+	_, err := Upgrade(w, req, []string{"https://app.example"})
+
+	assert.ErrorIs(t, err, ErrBadOrigin)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestConn_WriteTimeout(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	defer clientSide.Close()
+
+	// Nobody reads from clientSide, so every write stalls.
+	conn := newConn(serverSide, nil, true)
+	conn.SetWriteTimeout(50 * time.Millisecond)
+
+	started := time.Now()
+	err := conn.WriteMessage(TextMessage, []byte("hello"))
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestConn_CloseDoesNotWaitForStalledWrite(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	defer clientSide.Close()
+
+	conn := newConn(serverSide, nil, true)
+	conn.SetWriteTimeout(time.Minute)
+
+	written := make(chan error, 1)
+	go func() {
+		written <- conn.WriteMessage(TextMessage, []byte("hello"))
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		conn.Close(CloseGoingAway, "")
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(3 * closeWriteTimeout):
+		t.Fatal("Close waited for the stalled write")
+	}
+	assert.Error(t, <-written)
+	assert.ErrorIs(t, conn.WriteMessage(TextMessage, []byte("again")), ErrClosed)
+}
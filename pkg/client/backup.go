@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+type RestoreResult = models.RestoreResponse
+
+const backupContentType = "application/x-ndjson"
+
+// Backup writes a backup file of the whole store to w and returns its size.
+// It is not retried once data has been written; a backup cut short lacks its
+// checksum line and is rejected on restore.
+func (c *Client) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	header := http.Header{"Accept": {backupContentType}}
+	resp, err := c.send(ctx, request{method: http.MethodGet, header: header}, c.baseURL.JoinPath("/admin/backup").String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, decodeError(resp)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("client: read backup: %w", err)
+	}
+	return n, nil
+}
+
+// Restore loads a backup file into the server's store. The store must be
+// empty, otherwise ErrStoreNotEmpty is returned.
+func (c *Client) Restore(ctx context.Context, data []byte) (*RestoreResult, error) {
+	var result RestoreResult
+	_, err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/admin/restore",
+		body:        data,
+		contentType: backupContentType,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
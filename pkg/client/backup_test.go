@@ -0,0 +1,47 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_BackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	source := newClient(t, newServer(t, nil))
+	task, err := source.CreateTask(ctx, CreateTaskRequest{Title: "Backed up"})
+	require.NoError(t, err)
+
+	var file bytes.Buffer
+	n, err := source.Backup(ctx, &file)
+	require.NoError(t, err)
+	assert.Equal(t, int64(file.Len()), n)
+
+	var contentType string
+	target := newClient(t, newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			next.ServeHTTP(w, r)
+		})
+	}))
+	result, err := target.Restore(ctx, file.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Restored)
+	assert.Equal(t, backupContentType, contentType)
+
+	restored, err := target.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, restored.Title)
+
+	_, err = target.Restore(ctx, file.Bytes())
+	assert.ErrorIs(t, err, ErrStoreNotEmpty)
+
+	_, err = target.Restore(ctx, file.Bytes()[:file.Len()/2])
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
@@ -0,0 +1,244 @@
+// Package client is a Go client for the tasks API.
+//
+//	c, err := client.CreateClient("http://localhost:8080")
+//	task, err := c.CreateTask(ctx, client.CreateTaskRequest{Title: "Write docs", Description: "..."})
+//	if errors.Is(err, client.ErrValidation) { ... }
+//
+//	for task, err := range c.Tasks(ctx, client.ListOptions{Status: client.StatusPending}) { ... }
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries  = 3
+	DefaultBackoffBase = 100 * time.Millisecond
+	DefaultBackoffMax  = 2 * time.Second
+	DefaultTimeout     = 30 * time.Second
+
+	headerIdempotencyKey = "Idempotency-Key"
+	headerReplayed       = "Idempotent-Replayed"
+	headerTotalCount     = "X-Total-Count"
+)
+
+// Client is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	maxRetries  int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	token       string
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the
+// exponential backoff between attempts; zero maxRetries disables retries.
+func WithRetries(maxRetries int, backoffBase, backoffMax time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoffBase = backoffBase
+		c.backoffMax = backoffMax
+	}
+}
+
+// WithBearerToken sends the token in the Authorization header of every
+// request.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func CreateClient(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:     u,
+		httpClient:  &http.Client{Timeout: DefaultTimeout},
+		maxRetries:  DefaultMaxRetries,
+		backoffBase: DefaultBackoffBase,
+		backoffMax:  DefaultBackoffMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	header http.Header
+	// contentType, if set, marks body as an already encoded []byte.
+	contentType string
+}
+
+// retryable reports whether the request may be sent again: only methods that
+// are idempotent by definition, or POSTs carrying an idempotency key.
+func (r request) retryable() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return r.header.Get(headerIdempotencyKey) != ""
+	}
+}
+
+// do sends the request, retrying transport errors and 429/502/503/504
+// responses, and decodes a successful JSON response into out. A 409 to a
+// request with an idempotency key means an earlier attempt is still being
+// processed, so it is retried too until the stored response is replayed.
+func (c *Client) do(ctx context.Context, req request, out any) (*http.Response, error) {
+	var body []byte
+	if raw, ok := req.body.([]byte); ok && req.contentType != "" {
+		body = raw
+	} else if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("client: encode request: %w", err)
+		}
+	}
+
+	u := c.baseURL.JoinPath(req.path)
+	u.RawQuery = req.query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, u.String(), body)
+		if err == nil && resp.StatusCode < 400 {
+			defer resp.Body.Close()
+			if out != nil {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return resp, fmt.Errorf("client: decode response: %w", err)
+				}
+			}
+			return resp, nil
+		}
+
+		var retryAfter time.Duration
+		inProgress := false
+		if err == nil {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			inProgress = resp.StatusCode == http.StatusConflict &&
+				req.header.Get(headerIdempotencyKey) != "" &&
+				resp.Header.Get(headerReplayed) == ""
+			err = decodeError(resp)
+			resp.Body.Close()
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= c.maxRetries || !req.retryable() || !(inProgress || temporary(err)) {
+			return nil, err
+		}
+
+		if err := sleep(ctx, max(c.backoff(attempt), min(retryAfter, c.backoffMax))); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, target string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", cmp.Or(req.contentType, "application/json"))
+	}
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// backoff returns the delay before retry number attempt+1: exponential,
+// capped at backoffMax, with the upper half randomized.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.backoffBase << attempt
+	if delay <= 0 || delay > c.backoffMax {
+		delay = c.backoffMax
+	}
+	if delay <= 1 {
+		return delay
+	}
+
+	return delay/2 + rand.N(delay/2)
+}
+
+func temporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// Anything else comes from the transport: refused or reset connections
+	// and timeouts are worth another attempt.
+	return true
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
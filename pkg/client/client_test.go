@@ -0,0 +1,254 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/app/repository"
+	"github.com/supchaser/LO_test_task/internal/app/usecase"
+	"github.com/supchaser/LO_test_task/internal/middleware/httprequestid"
+	"github.com/supchaser/LO_test_task/internal/middleware/idempotency"
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+// newServer serves the real task handlers; wrap, if given, sits in front of
+// them to inject failures.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+
+	taskDelivery := delivery.CreateTaskDelivery(usecase.CreateTaskUsecase(repository.CreateTaskRepository()))
+	idempotencyStore := idempotency.CreateStore(time.Hour)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /tasks", idempotencyStore.Middleware(http.HandlerFunc(taskDelivery.CreateTask)))
+	mux.HandleFunc("GET /tasks/{id}", taskDelivery.GetTask)
+	mux.HandleFunc("GET /tasks", taskDelivery.ListTasks)
+	mux.HandleFunc("PUT /tasks/{id}", taskDelivery.UpdateTask)
+	mux.HandleFunc("DELETE /tasks/{id}", taskDelivery.DeleteTask)
+	mux.HandleFunc("GET /admin/backup", taskDelivery.BackupTasks)
+	mux.HandleFunc("POST /admin/restore", taskDelivery.RestoreTasks)
+
+	var handler http.Handler = mux
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	server := httptest.NewServer(httprequestid.RequestIDMiddleware(handler))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+
+	c, err := CreateClient(server.URL, WithRetries(3, time.Millisecond, 5*time.Millisecond))
+	require.NoError(t, err)
+	return c
+}
+
+// failFirst answers the first n requests with status, then passes through.
+func failFirst(n int32, status int, calls *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= n {
+				w.WriteHeader(status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClient_CRUD(t *testing.T) {
+	c := newClient(t, newServer(t, nil))
+	ctx := context.Background()
+
+	created, err := c.CreateTask(ctx, CreateTaskRequest{Title: "Write docs", Description: "Client SDK"})
+	require.NoError(t, err)
+	assert.Equal(t, "Write docs", created.Title)
+	assert.Equal(t, StatusPending, created.Status)
+
+	got, err := c.GetTask(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+
+	updated, err := c.UpdateTask(ctx, created.ID, UpdateTaskRequest{Title: "Write docs", Description: "Client SDK", Status: StatusCompleted})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, updated.Status)
+
+	require.NoError(t, c.DeleteTask(ctx, created.ID))
+
+	_, err = c.GetTask(ctx, created.ID)
+	assert.ErrorIs(t, err, errs.ErrTaskNotFound)
+	assert.ErrorIs(t, c.DeleteTask(ctx, created.ID), ErrTaskNotFound)
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	c := newClient(t, newServer(t, nil))
+
+	_, err := c.CreateTask(context.Background(), CreateTaskRequest{Title: "x"})
+	require.ErrorIs(t, err, ErrValidation)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Message, "title")
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.False(t, errors.Is(err, ErrTaskNotFound))
+}
+
+func TestClient_Pagination(t *testing.T) {
+	c := newClient(t, newServer(t, nil))
+	ctx := context.Background()
+
+	var ids []int64
+	for i := range 5 {
+		task, err := c.CreateTask(ctx, CreateTaskRequest{Title: fmt.Sprintf("Task %d", i), Description: "Paged"})
+		require.NoError(t, err)
+		ids = append(ids, task.ID)
+	}
+
+	page, err := c.ListTasks(ctx, ListOptions{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 5, page.Total)
+	require.Len(t, page.Tasks, 2)
+	assert.Equal(t, ids[1], page.Tasks[0].ID)
+
+	var pages int
+	for page, err := range c.TaskPages(ctx, ListOptions{Limit: 2}) {
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Tasks), 2)
+		pages++
+	}
+	assert.Equal(t, 3, pages)
+
+	var listed []int64
+	for task, err := range c.Tasks(ctx, ListOptions{Limit: 2, Status: StatusPending}) {
+		require.NoError(t, err)
+		listed = append(listed, task.ID)
+	}
+	assert.Equal(t, ids, listed)
+
+	var first []int64
+	for task, err := range c.Tasks(ctx, ListOptions{}) {
+		require.NoError(t, err)
+		first = append(first, task.ID)
+		if len(first) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, ids[:3], first)
+}
+
+func TestClient_RetriesTemporaryFailures(t *testing.T) {
+	var calls atomic.Int32
+	c := newClient(t, newServer(t, failFirst(2, http.StatusServiceUnavailable, &calls)))
+
+	task, err := c.CreateTask(context.Background(), CreateTaskRequest{Title: "Retried", Description: "Eventually created"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	got, err := c.GetTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, got.ID)
+}
+
+func TestClient_RetriesWhileIdempotentRequestIsInProgress(t *testing.T) {
+	taskDelivery := delivery.CreateTaskDelivery(usecase.CreateTaskUsecase(repository.CreateTaskRepository()))
+	idempotencyStore := idempotency.CreateStore(time.Hour)
+
+	// The first attempt outlives the client timeout, so the retries with the
+	// same key find it still in progress until it ends.
+	var calls atomic.Int32
+	slowFirst := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		taskDelivery.CreateTask(w, r)
+	})
+
+	var conflicts atomic.Int32
+	countConflicts := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, r)
+			if rec.Code == http.StatusConflict {
+				conflicts.Add(1)
+			}
+			maps.Copy(w.Header(), rec.Header())
+			w.WriteHeader(rec.Code)
+			w.Write(rec.Body.Bytes())
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /tasks", countConflicts(idempotencyStore.Middleware(slowFirst)))
+	mux.HandleFunc("GET /tasks", taskDelivery.ListTasks)
+	server := httptest.NewServer(httprequestid.RequestIDMiddleware(mux))
+	t.Cleanup(server.Close)
+
+	c, err := CreateClient(server.URL,
+		WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		WithRetries(10, 20*time.Millisecond, 40*time.Millisecond))
+	require.NoError(t, err)
+
+	task, err := c.CreateTask(context.Background(), CreateTaskRequest{Title: "Slow task", Description: "Created once"})
+	require.NoError(t, err)
+	assert.Positive(t, conflicts.Load())
+
+	page, err := c.ListTasks(context.Background(), ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, task.ID, page.Tasks[0].ID)
+}
+
+func TestClient_RetriesAreLimited(t *testing.T) {
+	var calls atomic.Int32
+	c := newClient(t, newServer(t, failFirst(100, http.StatusBadGateway, &calls)))
+
+	_, err := c.GetTask(context.Background(), 1)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newClient(t, newServer(t, failFirst(0, 0, &calls)))
+
+	_, err := c.GetTask(context.Background(), 42)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	var calls atomic.Int32
+	server := newServer(t, failFirst(100, http.StatusServiceUnavailable, &calls))
+	c, err := CreateClient(server.URL, WithRetries(10, time.Second, time.Second))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err = c.GetTask(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 500*time.Millisecond)
+}
+
+func TestCreateClient_InvalidURL(t *testing.T) {
+	_, err := CreateClient("localhost:8080")
+	assert.Error(t, err)
+}
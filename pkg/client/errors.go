@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/supchaser/LO_test_task/internal/utils/errs"
+)
+
+// The errors the server reports, so that errors.Is works on both sides of
+// the API.
+var (
+	ErrTaskNotFound  = errs.ErrTaskNotFound
+	ErrInvalidID     = errs.ErrInvalidID
+	ErrValidation    = errs.ErrValidation
+	ErrBatchTooLarge = errs.ErrBatchTooLarge
+	ErrBatchAborted  = errs.ErrBatchAborted
+	ErrStoreNotEmpty = errs.ErrStoreNotEmpty
+)
+
+var knownErrors = []error{ErrTaskNotFound, ErrInvalidID, ErrValidation, ErrBatchTooLarge, ErrBatchAborted, ErrStoreNotEmpty}
+
+// APIError is returned for responses with a 4xx or 5xx status. It unwraps to
+// one of the Err* values when the server reported a known error.
+type APIError struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+	err        error
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("client: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	if e.RequestID != "" {
+		message += " (request_id " + e.RequestID + ")"
+	}
+	return message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+type errorBody struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body errorBody
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != "" {
+		apiErr.Message = body.Error
+		apiErr.RequestID = body.RequestID
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+
+	// The server writes the error text, optionally followed by details, e.g.
+	// "validation error: task title must be at least 3 characters".
+	for _, known := range knownErrors {
+		if apiErr.Message == known.Error() || strings.HasPrefix(apiErr.Message, known.Error()+":") {
+			apiErr.err = known
+			break
+		}
+	}
+
+	return apiErr
+}
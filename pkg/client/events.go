@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+type (
+	TaskEvent     = models.TaskEvent
+	TaskEventType = models.TaskEventType
+)
+
+const (
+	EventTaskCreated       = models.EventTaskCreated
+	EventTaskUpdated       = models.EventTaskUpdated
+	EventTaskDeleted       = models.EventTaskDeleted
+	EventTaskStatusChanged = models.EventTaskStatusChanged
+)
+
+// WatchOptions filters the event stream. With LastEventID set, events after
+// that ID still held by the server are replayed first.
+type WatchOptions struct {
+	Status      TaskStatus
+	TaskIDs     []int64
+	LastEventID uint64
+}
+
+// WatchEvents streams task events over Server-Sent Events until ctx is done
+// or the iteration stops. A dropped connection is re-established with the
+// ID of the last received event, so no events are missed while they are
+// still in the server's replay buffer; the stream ends with an error once
+// reconnecting has failed more often than the retry limit allows in a row.
+func (c *Client) WatchEvents(ctx context.Context, opts WatchOptions) iter.Seq2[*TaskEvent, error] {
+	return func(yield func(*TaskEvent, error) bool) {
+		failures := 0
+		for {
+			received, err := c.streamEvents(ctx, opts, func(event *TaskEvent) bool {
+				opts.LastEventID = event.ID
+				return yield(event, nil)
+			})
+			if err == errStopIteration || ctx.Err() != nil {
+				return
+			}
+			if received {
+				failures = 0
+			}
+			if err != nil && (!temporary(err) || failures >= c.maxRetries) {
+				yield(nil, err)
+				return
+			}
+
+			if err := sleep(ctx, c.backoff(failures)); err != nil {
+				return
+			}
+			failures++
+		}
+	}
+}
+
+var errStopIteration = errors.New("client: iteration stopped")
+
+// streamEvents reads one connection; it reports whether any event arrived
+// and returns nil when the server closed the stream cleanly.
+func (c *Client) streamEvents(ctx context.Context, opts WatchOptions, emit func(*TaskEvent) bool) (bool, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", string(opts.Status))
+	}
+	for _, id := range opts.TaskIDs {
+		query.Add("task_id", strconv.FormatInt(id, 10))
+	}
+
+	u := c.baseURL.JoinPath("/tasks/events")
+	u.RawQuery = query.Encode()
+
+	header := http.Header{"Accept": {"text/event-stream"}}
+	if opts.LastEventID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatUint(opts.LastEventID, 10))
+	}
+
+	// The stream stays open indefinitely, so the client timeout must not
+	// apply to it.
+	streamClient := *c
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	streamClient.httpClient = &httpClient
+
+	resp, err := streamClient.send(ctx, request{method: http.MethodGet, header: header}, u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, decodeError(resp)
+	}
+
+	received := false
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event TaskEvent
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return received, fmt.Errorf("client: decode event: %w", err)
+			}
+			data.Reset()
+			received = true
+			if !emit(&event) {
+				return received, errStopIteration
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// Comments (keepalives), "id", "event" and "retry" lines carry
+		// nothing that is not already in the JSON payload.
+	}
+
+	return received, scanner.Err()
+}
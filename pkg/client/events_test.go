@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supchaser/LO_test_task/internal/app/delivery"
+	"github.com/supchaser/LO_test_task/internal/app/events"
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+// newEventServer streams events from bus; every connection is closed by the
+// server after connectionTTL to exercise reconnects.
+func newEventServer(t *testing.T, bus *events.Bus, connectionTTL time.Duration) *httptest.Server {
+	t.Helper()
+
+	eventDelivery := delivery.CreateEventDelivery(bus, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), connectionTTL)
+		defer cancel()
+		eventDelivery.StreamEvents(w, r.WithContext(ctx))
+	}))
+	t.Cleanup(func() {
+		eventDelivery.Shutdown()
+		server.Close()
+	})
+	return server
+}
+
+func deliver(t *testing.T, bus *events.Bus, taskID int64, status TaskStatus) {
+	t.Helper()
+
+	require.NoError(t, bus.Deliver(context.Background(), models.TaskEvent{
+		Type:   EventTaskUpdated,
+		TaskID: taskID,
+		Task:   &Task{ID: taskID, Title: "Watched", Status: status},
+	}))
+}
+
+func TestClient_WatchEvents(t *testing.T) {
+	bus := events.CreateBus(100)
+	deliver(t, bus, 1, StatusPending)
+	deliver(t, bus, 2, StatusCompleted)
+	deliver(t, bus, 1, StatusCompleted)
+
+	c := newClient(t, newEventServer(t, bus, 50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		// Arrives after the first connection has been closed.
+		time.Sleep(150 * time.Millisecond)
+		deliver(t, bus, 1, StatusInProgress)
+	}()
+
+	var ids []uint64
+	for event, err := range c.WatchEvents(ctx, WatchOptions{TaskIDs: []int64{1}, LastEventID: 1}) {
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), event.TaskID)
+		ids = append(ids, event.ID)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []uint64{3, 4}, ids)
+}
+
+func TestClient_WatchEventsStopsOnClientError(t *testing.T) {
+	// The task server has no event stream route, so "events" is taken for
+	// a task ID.
+	c := newClient(t, newServer(t, nil))
+
+	var calls int
+	for _, err := range c.WatchEvents(context.Background(), WatchOptions{}) {
+		calls++
+		assert.ErrorIs(t, err, ErrInvalidID)
+	}
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/supchaser/LO_test_task/internal/app/models"
+)
+
+type (
+	Task              = models.Task
+	TaskStatus        = models.TaskStatus
+	CreateTaskRequest = models.CreateTaskRequest
+	UpdateTaskRequest = models.UpdateTaskRequest
+)
+
+const (
+	StatusPending    = models.StatusPending
+	StatusInProgress = models.StatusInProgress
+	StatusCompleted  = models.StatusCompleted
+
+	// DefaultPageSize is used by Tasks and TaskPages when ListOptions.Limit
+	// is zero.
+	DefaultPageSize = 100
+)
+
+// CreateTask sends an idempotency key with the request, so it is safe to
+// retry: a repeated attempt returns the task created by the first one.
+func (c *Client) CreateTask(ctx context.Context, req CreateTaskRequest) (*Task, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	_, err = c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/tasks",
+		body:   req,
+		header: http.Header{headerIdempotencyKey: {key}},
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, id int64) (*Task, error) {
+	var task Task
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(id)}, &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (c *Client) UpdateTask(ctx context.Context, id int64, req UpdateTaskRequest) (*Task, error) {
+	var task Task
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: taskPath(id), body: req}, &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// DeleteTask is retried like other idempotent requests, so if a response is
+// lost and the retry finds the task already gone, ErrTaskNotFound is
+// returned.
+func (c *Client) DeleteTask(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: taskPath(id)}, nil)
+	return err
+}
+
+// ListOptions filters and pages a task list; tasks are ordered by ID. A zero
+// Limit returns all remaining tasks from ListTasks.
+type ListOptions struct {
+	Status TaskStatus
+	Limit  int
+	Offset int
+}
+
+type TaskPage struct {
+	Tasks  []*Task
+	Offset int
+	// Total is the number of tasks matching the filter across all pages.
+	Total int
+}
+
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) (*TaskPage, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", string(opts.Status))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	var tasks []*Task
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: "/tasks", query: query}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TaskPage{Tasks: tasks, Offset: opts.Offset, Total: opts.Offset + len(tasks)}
+	if total, err := strconv.Atoi(resp.Header.Get(headerTotalCount)); err == nil {
+		page.Total = total
+	}
+
+	return page, nil
+}
+
+// TaskPages iterates over the pages of a task list starting at opts.Offset.
+// Iteration stops after the first error.
+func (c *Client) TaskPages(ctx context.Context, opts ListOptions) iter.Seq2[*TaskPage, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageSize
+	}
+
+	return func(yield func(*TaskPage, error) bool) {
+		for {
+			page, err := c.ListTasks(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(page.Tasks) == 0 || !yield(page, nil) {
+				return
+			}
+
+			opts.Offset += len(page.Tasks)
+			if opts.Offset >= page.Total {
+				return
+			}
+		}
+	}
+}
+
+// Tasks iterates over every task of a list, fetching pages as needed.
+func (c *Client) Tasks(ctx context.Context, opts ListOptions) iter.Seq2[*Task, error] {
+	return func(yield func(*Task, error) bool) {
+		for page, err := range c.TaskPages(ctx, opts) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, task := range page.Tasks {
+				if !yield(task, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func taskPath(id int64) string {
+	return "/tasks/" + strconv.FormatInt(id, 10)
+}
+
+func newIdempotencyKey() (string, error) {
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", fmt.Errorf("client: generate idempotency key: %w", err)
+	}
+	return hex.EncodeToString(key[:]), nil
+}